@@ -20,6 +20,22 @@ var (
 		Usage: `Genesis sections to export separated by comma (e.g. "brs-1" or "ers" or "evm-2")`,
 		Value: "brs,ers,evm",
 	}
+	GenesisExportUnitEpochs = cli.Uint64Flag{
+		Name:  "export.unit.epochs",
+		Usage: "Split block and epoch records into units of the given number of epochs (0 to write a single unit per section)",
+	}
+	GenesisExportResume = cli.BoolFlag{
+		Name:  "export.resume",
+		Usage: "Resume an interrupted export into the existing file, keeping its complete units",
+	}
+	GenesisExportExtend = cli.StringFlag{
+		Name:  "export.extend",
+		Usage: "Previous genesis file to extend, the export starts from the epoch after its last epoch (only block and epoch records are exported by default)",
+	}
+	ImportUnverifiedFlag = cli.BoolFlag{
+		Name:  "import.unverified",
+		Usage: "Write the block and epoch records which aren't decided by the LLR votes without verification (unsafe, the records are trusted for LLR votes validation)",
+	}
 	importCommand = cli.Command{
 		Name:      "import",
 		Usage:     "Import a blockchain file",
@@ -43,6 +59,23 @@ Events are fully verified by default, unless overridden by check=false flag.`,
 				Description: `
 The import command imports events from RLP-encoded files.
 Events are fully verified by default, unless overridden by --check=false flag.`,
+			},
+			{
+				Action:    utils.MigrateFlags(importBlocks),
+				Name:      "blocks",
+				Usage:     "Import block and epoch records",
+				ArgsUsage: "<filename> (<filename 2> ... <filename N>)",
+				Flags: []cli.Flag{
+					DataDirFlag,
+					ImportUnverifiedFlag,
+				},
+				Description: `
+    opera import blocks
+
+The import command imports block and epoch records from files written by the export blocks command.
+Records are applied without processing of events. Records are verified against the LLR votes,
+so a record is rejected if its block or epoch isn't decided yet. Not decided records are written
+unverified only if --import.unverified is set, and get replaced by the verified records once synced.`,
 			},
 			{
 				Action:    utils.MigrateFlags(importEvm),
@@ -80,18 +113,38 @@ Requires a first argument of the file to write to.
 Optional second and third arguments control the first and
 last epoch to write. If the file ends with .gz, the output will
 be gzipped
+`,
+			},
+			{
+				Name:      "blocks",
+				Usage:     "Export block and epoch records",
+				ArgsUsage: "<filename> [<epochFrom> <epochTo>]",
+				Action:    utils.MigrateFlags(exportBlocks),
+				Flags: []cli.Flag{
+					DataDirFlag,
+				},
+				Description: `
+    opera export blocks
+
+Requires a first argument of the file to write to.
+Optional second and third arguments control the first and
+last epoch to write. Blocks of each epoch are followed by the epoch record.
+If the file ends with .gz, the output will be gzipped
 `,
 			},
 			{
 				Name:      "genesis",
 				Usage:     "Export current state into a genesis file",
-				ArgsUsage: "<filename or dry-run> [<epochFrom> <epochTo>] [--export.evm.mode=MODE --export.evm.exclude=DB_PATH --export.sections=A,B,C]",
+				ArgsUsage: "<filename or dry-run> [<epochFrom> <epochTo>] [--export.evm.mode=MODE --export.evm.exclude=DB_PATH --export.sections=A,B,C --export.unit.epochs=N --export.resume --export.extend=FILE]",
 				Action:    utils.MigrateFlags(exportGenesis),
 				Flags: []cli.Flag{
 					DataDirFlag,
 					EvmExportMode,
 					EvmExportExclude,
 					GenesisExportSections,
+					GenesisExportUnitEpochs,
+					GenesisExportResume,
+					GenesisExportExtend,
 				},
 				Description: `
     opera export genesis
@@ -102,6 +155,13 @@ Optional second and third arguments control the first and
 last epoch to write.
 Pass dry-run instead of filename for calculation of hashes without exporting data.
 EVM export mode is configured with --export.evm.mode.
+Block and epoch records are split into units of N epochs with --export.unit.epochs,
+units of higher epochs have higher indexes (e.g. "brs-2" follows "brs-1").
+An interrupted export is resumed from its last complete unit with --export.resume,
+using the same arguments.
+With --export.extend, the file starts from the epoch after the last epoch of
+the previous genesis file, and its units continue the indexes of the previous file.
+Such files are appended to the previous one (e.g. with cat) to get the extended genesis.
 `,
 			},
 			{
@@ -137,6 +197,55 @@ Requires a first argument of the DB directory to write to.
     opera check evm
 
 Checks EVM storage roots and code hashes
+`,
+			},
+		},
+	}
+	genesisCommand = cli.Command{
+		Name:     "genesis",
+		Usage:    "Build, inspect and verify genesis files",
+		Category: "MISCELLANEOUS COMMANDS",
+
+		Subcommands: []cli.Command{
+			{
+				Name:      "build",
+				Usage:     "Build a genesis file from a network spec",
+				ArgsUsage: "<spec.json or spec.toml> <filename>",
+				Action:    utils.MigrateFlags(buildGenesis),
+				Description: `
+    opera genesis build
+
+Builds a genesis file from a declarative network spec in JSON or TOML format.
+The spec defines the network rules (as overrides of BaseRules "main", "test" or "fake"),
+start time, epoch and block, validators with their pubkeys and stakes, delegations,
+prefunded accounts and predeployed contracts with storage.
+The output is deterministic, so the same spec always produces the same genesis.
+`,
+			},
+			{
+				Name:      "inspect",
+				Usage:     "Print genesis file header and sections",
+				ArgsUsage: "<filename>",
+				Action:    utils.MigrateFlags(inspectGenesis),
+				Description: `
+    opera genesis inspect
+
+Prints the genesis header (genesis ID, network ID and name) and the metadata
+of every section: unit name, hash, compressed and uncompressed sizes.
+Blocks and epochs sections are read to print their first and last records.
+`,
+			},
+			{
+				Name:      "verify",
+				Usage:     "Verify integrity of a genesis file",
+				ArgsUsage: "<filename>",
+				Action:    utils.MigrateFlags(verifyGenesis),
+				Description: `
+    opera genesis verify
+
+Prints the same data as the inspect command, and reads every section including EVM,
+checking every piece of the file against the stored hashes.
+Fails if the file is corrupted.
 `,
 			},
 		},
@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"github.com/Fantom-foundation/lachesis-base/abft"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/multidb"
 	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
@@ -22,6 +24,7 @@ import (
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/evmcore/txpolicy"
 	"github.com/Fantom-foundation/go-opera/gossip"
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
 	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
@@ -96,6 +99,16 @@ var (
 		Value: gossip.DefaultConfig(cachescale.Identity).RPCTimeout,
 	}
 
+	TraceIndexFlag = cli.BoolFlag{
+		Name:  "trace.index",
+		Usage: "Record and index internal calls of transactions, enables the trace_* RPC API",
+	}
+
+	AddressTxIndexFlag = cli.BoolFlag{
+		Name:  "address.index",
+		Usage: "Index transactions by senders and recipients, enables the ftm_getTransactionsByAddress RPC API",
+	}
+
 	SyncModeFlag = cli.StringFlag{
 		Name:  "syncmode",
 		Usage: `Blockchain sync mode ("full" or "snap")`,
@@ -125,6 +138,61 @@ var (
 		Name:  "db.preset",
 		Usage: "DBs layout preset ('pbl-1' or 'ldb-1' or 'legacy-ldb' or 'legacy-pbl')",
 	}
+	DBFreezerFlag = cli.StringFlag{
+		Name:  "db.freezer",
+		Usage: "Directory of the freezer which finalized blocks, receipts and events of old epochs are moved into (absolute or relative to chaindata)",
+	}
+
+	SentryModeFlag = cli.StringFlag{
+		Name:  "sentry.mode",
+		Usage: `Sentry topology mode ("validator" to connect only to the sentry nodes, "sentry" to relay traffic to the validators)`,
+	}
+	SentryNodesFlag = cli.StringFlag{
+		Name:  "sentry.nodes",
+		Usage: "Comma separated enode URLs of the sentries in the validator mode, or of the validators in the sentry mode",
+	}
+	PrivateTxsPeersFlag = cli.StringFlag{
+		Name:  "privatetxs.peers",
+		Usage: "Comma separated enode URLs of the validators (or of their sentries) which private transactions are forwarded to",
+	}
+	PrivateTxsOriginatorsFlag = cli.StringFlag{
+		Name:  "privatetxs.originators",
+		Usage: "Comma separated IDs of the validators which receive private transactions and originate them in turns",
+	}
+	HistoryEpochsFlag = cli.UintFlag{
+		Name:  "history.epochs",
+		Usage: "Number of the latest sealed epochs to keep events, blocks, receipts and logs of (0 = keep the whole history)",
+	}
+
+	TxPoolSnapshotFlag = cli.StringFlag{
+		Name:  "txpool.snapshot",
+		Usage: "Disk snapshot of remote transactions to survive node restarts, disabled if empty",
+	}
+	TxPoolResnapshotFlag = cli.DurationFlag{
+		Name:  "txpool.resnapshot",
+		Usage: "Time interval to regenerate the snapshot of remote transactions",
+		Value: evmcore.DefaultTxPoolConfig.Resnapshot,
+	}
+
+	HealthAddrFlag = cli.StringFlag{
+		Name:  "health.addr",
+		Usage: "Enable health check HTTP server on the given address (e.g. 127.0.0.1:18546), with liveness (/health) and readiness (/ready) endpoints",
+	}
+	HealthMinPeersFlag = cli.IntFlag{
+		Name:  "health.minpeers",
+		Usage: "Min number of peers for the node to be ready",
+		Value: gossip.DefaultHealthConfig().MinPeers,
+	}
+	HealthMaxBlockAgeFlag = cli.DurationFlag{
+		Name:  "health.maxblockage",
+		Usage: "Max age of the head block for the node to be ready",
+		Value: gossip.DefaultHealthConfig().MaxBlockAge,
+	}
+
+	MetricsPrometheusAddrFlag = cli.StringFlag{
+		Name:  "metrics.prometheus.addr",
+		Usage: "Enable Prometheus metrics server with labeled consensus metrics on the given address (e.g. 127.0.0.1:6061), served on /metrics",
+	}
 )
 
 type GenesisTemplate struct {
@@ -158,6 +226,7 @@ type config struct {
 	Opera         gossip.Config
 	Emitter       emitter.Config
 	TxPool        evmcore.TxPoolConfig
+	TxPolicy      txpolicy.Config
 	OperaStore    gossip.StoreConfig
 	Lachesis      abft.Config
 	LachesisStore abft.StoreConfig
@@ -272,6 +341,42 @@ func setDataDir(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setSentryTopology configures P2P according to the sentry mode.
+// The private peers are always connected and trusted.
+// In the validator mode, the node is connected only to its sentries and doesn't take part in discovery.
+func setSentryTopology(opera *gossip.Config, cfg *node.Config) error {
+	if len(opera.Sentry.Mode) == 0 {
+		return nil
+	}
+	nodes, err := opera.Sentry.Nodes()
+	if err != nil {
+		return err
+	}
+	cfg.P2P.StaticNodes = append(cfg.P2P.StaticNodes, nodes...)
+	cfg.P2P.TrustedNodes = append(cfg.P2P.TrustedNodes, nodes...)
+	if opera.Sentry.Mode == gossip.SentryValidatorMode {
+		cfg.P2P.NoDiscovery = true
+		cfg.P2P.DiscoveryV5 = false
+		cfg.P2P.BootstrapNodes = []*enode.Node{}
+		cfg.P2P.BootstrapNodesV5 = []*enode.Node{}
+		opera.OperaDiscoveryURLs = nil
+		opera.SnapDiscoveryURLs = nil
+	}
+	return nil
+}
+
+// setPrivateTxPeers keeps the node connected to the peers which private transactions are forwarded to
+func setPrivateTxPeers(opera *gossip.Config, cfg *node.Config) error {
+	for _, url := range opera.PrivateTxs.Peers {
+		node, err := enode.Parse(enode.ValidSchemes, url)
+		if err != nil {
+			return fmt.Errorf("invalid private txs peer %s: %v", url, err)
+		}
+		cfg.P2P.StaticNodes = append(cfg.P2P.StaticNodes, node)
+	}
+	return nil
+}
+
 func setGPO(ctx *cli.Context, cfg *gasprice.Config) {}
 
 func setTxPool(ctx *cli.Context, cfg *evmcore.TxPoolConfig) {
@@ -294,6 +399,12 @@ func setTxPool(ctx *cli.Context, cfg *evmcore.TxPoolConfig) {
 	if ctx.GlobalIsSet(utils.TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.GlobalDuration(utils.TxPoolRejournalFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolSnapshotFlag.Name) {
+		cfg.Snapshot = ctx.GlobalString(TxPoolSnapshotFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolResnapshotFlag.Name) {
+		cfg.Resnapshot = ctx.GlobalDuration(TxPoolResnapshotFlag.Name)
+	}
 	if ctx.GlobalIsSet(utils.TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.GlobalUint64(utils.TxPoolPriceLimitFlag.Name)
 	}
@@ -331,12 +442,49 @@ func gossipConfigWithFlags(ctx *cli.Context, src gossip.Config) (gossip.Config,
 	if ctx.GlobalIsSet(RPCGlobalTimeoutFlag.Name) {
 		cfg.RPCTimeout = ctx.GlobalDuration(RPCGlobalTimeoutFlag.Name)
 	}
+	if ctx.GlobalIsSet(TraceIndexFlag.Name) {
+		cfg.TraceIndex = ctx.GlobalBool(TraceIndexFlag.Name)
+	}
+	if ctx.GlobalIsSet(AddressTxIndexFlag.Name) {
+		cfg.AddressTxIndex = ctx.GlobalBool(AddressTxIndexFlag.Name)
+	}
 	if ctx.GlobalIsSet(SyncModeFlag.Name) {
 		if syncmode := ctx.GlobalString(SyncModeFlag.Name); syncmode != "full" && syncmode != "snap" {
 			utils.Fatalf("--%s must be either 'full' or 'snap'", SyncModeFlag.Name)
 		}
 		cfg.AllowSnapsync = ctx.GlobalString(SyncModeFlag.Name) == "snap"
 	}
+	if ctx.GlobalIsSet(SentryModeFlag.Name) {
+		cfg.Sentry.Mode = ctx.GlobalString(SentryModeFlag.Name)
+	}
+	if ctx.GlobalIsSet(SentryNodesFlag.Name) {
+		cfg.Sentry.PrivatePeers = nil
+		for _, url := range strings.Split(ctx.GlobalString(SentryNodesFlag.Name), ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				cfg.Sentry.PrivatePeers = append(cfg.Sentry.PrivatePeers, url)
+			}
+		}
+	}
+	if ctx.GlobalIsSet(PrivateTxsPeersFlag.Name) {
+		cfg.PrivateTxs.Peers = nil
+		for _, url := range strings.Split(ctx.GlobalString(PrivateTxsPeersFlag.Name), ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				cfg.PrivateTxs.Peers = append(cfg.PrivateTxs.Peers, url)
+			}
+		}
+	}
+	if ctx.GlobalIsSet(HistoryEpochsFlag.Name) {
+		cfg.HistoryRetention.Epochs = idx.Epoch(ctx.GlobalUint(HistoryEpochsFlag.Name))
+	}
+	if ctx.GlobalIsSet(HealthAddrFlag.Name) {
+		cfg.Health.ListenAddr = ctx.GlobalString(HealthAddrFlag.Name)
+	}
+	if ctx.GlobalIsSet(HealthMinPeersFlag.Name) {
+		cfg.Health.MinPeers = ctx.GlobalInt(HealthMinPeersFlag.Name)
+	}
+	if ctx.GlobalIsSet(HealthMaxBlockAgeFlag.Name) {
+		cfg.Health.MaxBlockAge = ctx.GlobalDuration(HealthMaxBlockAgeFlag.Name)
+	}
 
 	return cfg, nil
 }
@@ -372,6 +520,14 @@ func setDBConfig(ctx *cli.Context, cfg integration.DBsConfig, cacheRatio cachesc
 	if ctx.GlobalIsSet(DBMigrationModeFlag.Name) {
 		cfg.MigrationMode = ctx.GlobalString(DBMigrationModeFlag.Name)
 	}
+	if ctx.GlobalIsSet(DBFreezerFlag.Name) {
+		table := cfg.Routing.DBsTable()
+		table["freezer"] = multidb.Route{
+			Type: integration.FreezerType,
+			Name: ctx.GlobalString(DBFreezerFlag.Name),
+		}
+		cfg.Routing.Table = table
+	}
 	return cfg
 }
 
@@ -450,15 +606,31 @@ func mayMakeAllConfigs(ctx *cli.Context) (*config, error) {
 		return nil, err
 	}
 	cfg.Node = nodeConfigWithFlags(ctx, cfg.Node)
+	err = setSentryTopology(&cfg.Opera, &cfg.Node)
+	if err != nil {
+		return nil, err
+	}
+	err = setPrivateTxPeers(&cfg.Opera, &cfg.Node)
+	if err != nil {
+		return nil, err
+	}
 	cfg.DBs = setDBConfig(ctx, cfg.DBs, cacheRatio)
+	cfg.OperaStore.FreezerDir = cfg.DBs.Routing.FreezerDir(path.Join(cfg.Node.DataDir, "chaindata"))
 
 	err = setValidator(ctx, &cfg.Emitter)
 	if err != nil {
 		return nil, err
 	}
+	err = setPrivateTxOriginators(ctx, &cfg.Emitter)
+	if err != nil {
+		return nil, err
+	}
 	if cfg.Emitter.Validator.ID != 0 && len(cfg.Emitter.PrevEmittedEventFile.Path) == 0 {
 		cfg.Emitter.PrevEmittedEventFile.Path = cfg.Node.ResolvePath(path.Join("emitter", fmt.Sprintf("last-%d", cfg.Emitter.Validator.ID)))
 	}
+	if len(cfg.OperaStore.EVM.LivePruning.Datadir) == 0 && len(cfg.Node.DataDir) != 0 {
+		cfg.OperaStore.EVM.LivePruning.Datadir = cfg.Node.ResolvePath("livepruning")
+	}
 	setTxPool(ctx, &cfg.TxPool)
 
 	if err := cfg.Opera.Validate(); err != nil {
@@ -515,3 +687,20 @@ func checkConfig(ctx *cli.Context) error {
 	_, err := mayMakeAllConfigs(ctx)
 	return err
 }
+
+// reloadTxPolicy reads the txpool admission policy from the config file and applies it
+func reloadTxPolicy(ctx *cli.Context, policy *txpolicy.Policy) error {
+	file := ctx.GlobalString(configFileFlag.Name)
+	if file == "" {
+		return fmt.Errorf("config file isn't specified by --%s", configFileFlag.Name)
+	}
+	cfg := config{}
+	if err := loadAllConfigs(file, &cfg); err != nil {
+		return err
+	}
+	if err := policy.Reload(cfg.TxPolicy); err != nil {
+		return fmt.Errorf("invalid TxPolicy config: %v", err)
+	}
+	log.Info("Txpool admission policy is reloaded", "file", file)
+	return nil
+}
@@ -72,3 +72,38 @@ func TestConfigFile(t *testing.T) {
 		})
 	}
 }
+
+func TestSetSentryTopology(t *testing.T) {
+	require := require.New(t)
+	sentry := "enode://a979fb575495b8d6db44f750317d0f4622bf4c2aa3365d6af7c284339968eef29b69ad0dce72a4d8db5ebb4968de0e3bec910127f134779fbcb0cb6d3331163c@52.16.188.185:30303"
+
+	opera := gossip.DefaultConfig(cachescale.Identity)
+	opera.OperaDiscoveryURLs = []string{"enrtree://example.org"}
+	node := defaultNodeConfig()
+	require.NoError(setSentryTopology(&opera, &node))
+	require.False(node.P2P.NoDiscovery)
+	require.Empty(node.P2P.StaticNodes)
+
+	opera.Sentry = gossip.SentryConfig{
+		Mode:         gossip.SentryValidatorMode,
+		PrivatePeers: []string{sentry},
+	}
+	require.NoError(opera.Validate())
+	require.NoError(setSentryTopology(&opera, &node))
+	require.True(node.P2P.NoDiscovery)
+	require.False(node.P2P.DiscoveryV5)
+	require.Empty(node.P2P.BootstrapNodes)
+	require.Empty(opera.OperaDiscoveryURLs)
+	require.Equal([]*enode.Node{enode.MustParse(sentry)}, node.P2P.StaticNodes)
+	require.Equal([]*enode.Node{enode.MustParse(sentry)}, node.P2P.TrustedNodes)
+
+	opera.Sentry.PrivatePeers = nil
+	require.Error(opera.Validate())
+	opera.Sentry.Mode = "unknown"
+	require.Error(opera.Validate())
+	opera.Sentry = gossip.SentryConfig{
+		Mode:         gossip.SentryMode,
+		PrivatePeers: []string{"invalid"},
+	}
+	require.Error(opera.Validate())
+}
@@ -0,0 +1,78 @@
+package launcher
+
+import (
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
+)
+
+func indexAddressTxs(ctx *cli.Context) error {
+	if len(ctx.Args()) > 2 {
+		utils.Fatalf("This command requires at most 2 arguments.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	rawDbs := makeDirectDBsProducer(cfg)
+	gdb := makeGossipStore(rawDbs, cfg)
+	defer gdb.Close()
+	evms := gdb.EvmStore()
+
+	from := idx.Block(1)
+	if len(ctx.Args()) > 0 {
+		n, err := strconv.ParseUint(ctx.Args().Get(0), 10, 64)
+		if err != nil {
+			return err
+		}
+		from = idx.Block(n)
+	}
+	to := gdb.GetLatestBlockIndex()
+	if len(ctx.Args()) > 1 {
+		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return err
+		}
+		if idx.Block(n) < to {
+			to = idx.Block(n)
+		}
+	}
+
+	// the pruned history cannot be indexed
+	if start := gdb.GetHistoryStart(); from < start.Block {
+		log.Warn("History before the block is pruned, skipping it", "block", start.Block)
+		from = start.Block
+	}
+
+	signer := gsignercache.Wrap(types.LatestSignerForChainID(new(big.Int).SetUint64(gdb.GetRules().NetworkID)))
+
+	log.Info("Indexing transactions by addresses", "from", from, "to", to)
+	start, reported := time.Now(), time.Now()
+	for n := from; n <= to; n++ {
+		block := gdb.GetBlock(n)
+		if block == nil {
+			continue
+		}
+		txs, err := gdb.TryGetBlockTxs(n, block)
+		if err != nil {
+			log.Warn("Skipping block with missing transactions", "block", n, "err", err)
+			continue
+		}
+		receipts := evms.GetReceipts(n, signer, common.Hash(block.Atropos), txs)
+		evms.IndexAddressTxs(n, txs, receipts, signer)
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Indexing transactions by addresses", "last", n, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Transactions are indexed by addresses", "from", from, "to", to, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -140,7 +140,7 @@ func (ee *dbMigrationEntries) Pop() *dbMigrationEntry {
 var dbLocatorOf = multidb.DBLocatorOf
 
 func readRoutes(cfg *config, dbTypes map[multidb.TypeName]kvdb.FullDBProducer) (map[string]dbMigrationEntry, error) {
-	router, err := multidb.NewProducer(dbTypes, cfg.DBs.Routing.Table, integration.TablesKey)
+	router, err := multidb.NewProducer(dbTypes, cfg.DBs.Routing.DBsTable(), integration.TablesKey)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +70,22 @@ will migrate tables layout according to the configuration.
 				Description: `
 opera db heal --experimental
 Experimental - try to heal dirty DB.
+`,
+			},
+			{
+				Name:      "index-addresses",
+				Usage:     "Build the index of transactions by senders and recipients",
+				ArgsUsage: "[<from> [<to>]]",
+				Action:    utils.MigrateFlags(indexAddressTxs),
+				Category:  "DB COMMANDS",
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+				},
+				Description: `
+opera db index-addresses [<from> [<to>]]
+will index the transactions of the existing blocks by their senders and recipients.
+Optional first and second arguments set the blocks range, all the blocks are indexed by default.
+The index is used by ftm_getTransactionsByAddress if AddressTxIndex is enabled.
 `,
 			},
 		},
@@ -21,11 +21,22 @@ import (
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/inter/ibr"
+	"github.com/Fantom-foundation/go-opera/inter/ier"
 )
 
 var (
 	eventsFileHeader  = hexutils.HexToBytes("7e995678")
 	eventsFileVersion = hexutils.HexToBytes("00010001")
+
+	blocksFileHeader  = hexutils.HexToBytes("7e995679")
+	blocksFileVersion = hexutils.HexToBytes("00010001")
+)
+
+// kinds of the records in a blocks file, each record is preceded by its kind
+const (
+	blocksFileBlockRecord = 1
+	blocksFileEpochRecord = 2
 )
 
 // statsReportLimit is the time limit during import and export after which we
@@ -118,6 +129,126 @@ func exportTo(w io.Writer, gdb *gossip.Store, from, to idx.Epoch) (err error) {
 	return
 }
 
+func exportBlocks(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	cfg := makeAllConfigs(ctx)
+
+	rawDbs := makeDirectDBsProducer(cfg)
+	gdb := makeGossipStore(rawDbs, cfg)
+	defer gdb.Close()
+
+	fn := ctx.Args().First()
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+
+	from := idx.Epoch(1)
+	if len(ctx.Args()) > 1 {
+		n, err := strconv.ParseUint(ctx.Args().Get(1), 10, 32)
+		if err != nil {
+			return err
+		}
+		from = idx.Epoch(n)
+	}
+	to := gdb.GetEpoch()
+	if len(ctx.Args()) > 2 {
+		n, err := strconv.ParseUint(ctx.Args().Get(2), 10, 32)
+		if err != nil {
+			return err
+		}
+		to = idx.Epoch(n)
+	}
+	if from < 1 {
+		// avoid underflow
+		from = 1
+	}
+	if to > gdb.GetEpoch() {
+		to = gdb.GetEpoch()
+	}
+
+	log.Info("Exporting blocks to file", "file", fn)
+	// Write header and version
+	_, err = writer.Write(append(blocksFileHeader, blocksFileVersion...))
+	if err != nil {
+		return err
+	}
+	err = exportBlocksTo(writer, gdb, from, to)
+	if err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+
+	return nil
+}
+
+func writeBlocksFileRecord(w io.Writer, kind uint8, record interface{}) error {
+	if err := rlp.Encode(w, kind); err != nil {
+		return err
+	}
+	return rlp.Encode(w, record)
+}
+
+// exportBlocksTo writes the block records of the epochs, each epoch is followed by its epoch record.
+func exportBlocksTo(w io.Writer, gdb *gossip.Store, from, to idx.Epoch) error {
+	start, reported := time.Now(), time.Time{}
+
+	var (
+		blocks int
+		epochs int
+		last   idx.Block
+	)
+	for epoch := from; epoch <= to; epoch++ {
+		er := gdb.GetFullEpochRecord(epoch)
+		if er == nil {
+			log.Warn("No epoch record", "epoch", epoch)
+			continue
+		}
+		for n := getEpochBlock(epoch-1, gdb) + 1; n <= er.BlockState.LastBlock.Idx; n++ {
+			br := gdb.GetFullBlockRecord(n)
+			if br == nil {
+				log.Warn("No block record", "block", n)
+				continue
+			}
+			err := writeBlocksFileRecord(w, blocksFileBlockRecord, ibr.LlrIdxFullBlockRecord{
+				LlrFullBlockRecord: *br,
+				Idx:                n,
+			})
+			if err != nil {
+				return err
+			}
+			blocks++
+			last = n
+			if blocks%100 == 1 && time.Since(reported) >= statsReportLimit {
+				log.Info("Exporting blocks", "last", last, "exported", blocks, "elapsed", common.PrettyDuration(time.Since(start)))
+				reported = time.Now()
+			}
+		}
+		err := writeBlocksFileRecord(w, blocksFileEpochRecord, ier.LlrIdxFullEpochRecord{
+			LlrFullEpochRecord: *er,
+			Idx:                epoch,
+		})
+		if err != nil {
+			return err
+		}
+		epochs++
+	}
+	log.Info("Exported blocks", "last", last, "exported", blocks, "epochs", epochs, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
+
 func exportEvmKeys(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
 		utils.Fatalf("This command requires an argument.")
@@ -0,0 +1,154 @@
+package launcher
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/eventcheck"
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+	"github.com/Fantom-foundation/go-opera/inter/ibr"
+	"github.com/Fantom-foundation/go-opera/inter/ier"
+)
+
+// testRecordsImporter writes the records into a store, like the LLR records processing does
+type testRecordsImporter struct {
+	store      *gossip.Store
+	unverified int
+}
+
+func (i *testRecordsImporter) ProcessFullBlockRecord(br ibr.LlrIdxFullBlockRecord) error {
+	if i.store.HasBlock(br.Idx) {
+		return eventcheck.ErrAlreadyProcessedBR
+	}
+	i.store.WriteFullBlockRecord(br)
+	return nil
+}
+
+func (i *testRecordsImporter) ProcessFullEpochRecord(er ier.LlrIdxFullEpochRecord) error {
+	if i.store.HasHistoryBlockEpochState(er.Idx) {
+		return eventcheck.ErrAlreadyProcessedER
+	}
+	i.store.WriteFullEpochRecord(er)
+	return nil
+}
+
+func (i *testRecordsImporter) ImportFullBlockRecord(br ibr.LlrIdxFullBlockRecord) (bool, error) {
+	i.unverified++
+	return false, i.ProcessFullBlockRecord(br)
+}
+
+func (i *testRecordsImporter) ImportFullEpochRecord(er ier.LlrIdxFullEpochRecord) (bool, error) {
+	i.unverified++
+	return false, i.ProcessFullEpochRecord(er)
+}
+
+// writeTestRecords writes 2 blocks with a transaction for each epoch
+func writeTestRecords(store *gossip.Store, epochs idx.Epoch) {
+	validators := pos.EqualWeightValidators([]idx.ValidatorID{1, 2}, 1)
+	n := idx.Block(1)
+	for epoch := idx.Epoch(1); epoch <= epochs; epoch++ {
+		for i := 0; i < 2; i++ {
+			tx := types.NewTransaction(uint64(n), common.Address{1}, big.NewInt(int64(n)), 21000, big.NewInt(1), nil)
+			store.WriteFullBlockRecord(ibr.LlrIdxFullBlockRecord{
+				LlrFullBlockRecord: ibr.LlrFullBlockRecord{
+					Atropos: hash.FakeEvent(),
+					Root:    hash.Hash(hash.FakeHash(int64(n))),
+					Txs:     types.Transactions{tx},
+					Receipts: []*types.ReceiptForStorage{{
+						Status:            types.ReceiptStatusSuccessful,
+						CumulativeGasUsed: 21000,
+						Logs:              []*types.Log{},
+					}},
+					Time:    inter.Timestamp(n),
+					GasUsed: 21000,
+				},
+				Idx: n,
+			})
+			n++
+		}
+		store.WriteFullEpochRecord(ier.LlrIdxFullEpochRecord{
+			LlrFullEpochRecord: ier.LlrFullEpochRecord{
+				BlockState: iblockproc.BlockState{
+					LastBlock: iblockproc.BlockCtx{Idx: n - 1, Time: inter.Timestamp(n - 1)},
+				},
+				EpochState: iblockproc.EpochState{
+					Epoch:      epoch,
+					Validators: validators,
+				},
+			},
+			Idx: epoch,
+		})
+	}
+}
+
+func exportTestBlocksFile(t *testing.T, store *gossip.Store, fn string, from, to idx.Epoch) []byte {
+	var buf bytes.Buffer
+	buf.Write(append(blocksFileHeader, blocksFileVersion...))
+	require.NoError(t, exportBlocksTo(&buf, store, from, to))
+	require.NoError(t, ioutil.WriteFile(fn, buf.Bytes(), 0600))
+	return buf.Bytes()
+}
+
+func TestExportImportBlocks(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "blocks")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	src := gossip.NewMemStore()
+	defer src.Close()
+	writeTestRecords(src, 3)
+	exported := exportTestBlocksFile(t, src, filepath.Join(dir, "src.rlp"), 1, 3)
+
+	// the records are verified by default
+	dst := &testRecordsImporter{store: gossip.NewMemStore()}
+	defer dst.store.Close()
+	require.NoError(importBlocksFile(dst, filepath.Join(dir, "src.rlp"), false))
+	require.Equal(0, dst.unverified)
+	for n := idx.Block(1); n <= 6; n++ {
+		require.Equal(src.GetFullBlockRecord(n).Hash(), dst.store.GetFullBlockRecord(n).Hash(), n)
+	}
+	for epoch := idx.Epoch(1); epoch <= 3; epoch++ {
+		require.Equal(src.GetFullEpochRecord(epoch).Hash(), dst.store.GetFullEpochRecord(epoch).Hash(), epoch)
+	}
+	reexported := exportTestBlocksFile(t, dst.store, filepath.Join(dir, "dst.rlp"), 1, 3)
+	require.Equal(exported, reexported)
+
+	// already imported records are skipped
+	require.NoError(importBlocksFile(dst, filepath.Join(dir, "src.rlp"), false))
+
+	// the records are written unverified only if it's allowed explicitly
+	unverified := &testRecordsImporter{store: gossip.NewMemStore()}
+	defer unverified.store.Close()
+	require.NoError(importBlocksFile(unverified, filepath.Join(dir, "src.rlp"), true))
+	require.Equal(6+3, unverified.unverified)
+
+	// a range of epochs
+	exportTestBlocksFile(t, src, filepath.Join(dir, "range.rlp"), 2, 2)
+	ranged := &testRecordsImporter{store: gossip.NewMemStore()}
+	defer ranged.store.Close()
+	require.NoError(importBlocksFile(ranged, filepath.Join(dir, "range.rlp"), false))
+	for n := idx.Block(1); n <= 6; n++ {
+		require.Equal(n == 3 || n == 4, ranged.store.HasBlock(n), n)
+	}
+	require.False(ranged.store.HasHistoryBlockEpochState(1))
+	require.True(ranged.store.HasHistoryBlockEpochState(2))
+
+	// not a blocks file
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "bad.rlp"), []byte("bad"), 0600))
+	require.Error(importBlocksFile(dst, filepath.Join(dir, "bad.rlp"), false))
+}
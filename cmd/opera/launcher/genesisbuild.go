@@ -0,0 +1,128 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/naoina/toml"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/integration/makegenesis"
+	"github.com/Fantom-foundation/go-opera/opera/genesis"
+	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
+)
+
+// readNetworkSpec decodes the network spec from a JSON or TOML file.
+// The rules are decoded over the base rules, so only the overridden rules need to be specified.
+func readNetworkSpec(fn string) (makegenesis.NetworkSpec, error) {
+	data, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return makegenesis.NetworkSpec{}, err
+	}
+	decode := func(spec *makegenesis.NetworkSpec) error {
+		if strings.HasSuffix(fn, ".toml") {
+			err := tomlSettings.NewDecoder(bufio.NewReader(bytes.NewReader(data))).Decode(spec)
+			if _, ok := err.(*toml.LineError); ok {
+				err = errors.New(fn + ", " + err.Error())
+			}
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(spec)
+	}
+
+	// decode the base rules name first
+	var spec makegenesis.NetworkSpec
+	if err := decode(&spec); err != nil {
+		return spec, err
+	}
+	rules, err := makegenesis.BaseRules(spec.BaseRules)
+	if err != nil {
+		return spec, err
+	}
+
+	spec = makegenesis.NetworkSpec{
+		Rules:      rules,
+		StartEpoch: 2,
+		StartBlock: 1,
+	}
+	err = decode(&spec)
+	return spec, err
+}
+
+// writeGenesisStore writes the epochs, blocks and EVM sections of the genesis into the file.
+func writeGenesisStore(fn string, store *genesisstore.Store, tmpPath string) (genesis.Hashes, error) {
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	hashes := genesis.Hashes{}
+	for _, name := range []string{genesisstore.EpochsSection(0), genesisstore.BlocksSection(0), genesisstore.EvmSection(0)} {
+		section, err := store.Section(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read genesis section %s: %v", name, err)
+		}
+		writer := newUnitWriter(fh)
+		err = writer.Start(store.Header(), name, tmpPath)
+		if err != nil {
+			return nil, err
+		}
+		_, err = io.Copy(writer, section)
+		if err != nil {
+			return nil, err
+		}
+		hashes[name], err = writer.Flush()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return hashes, nil
+}
+
+func buildGenesis(ctx *cli.Context) error {
+	if len(ctx.Args()) != 2 {
+		utils.Fatalf("This command requires 2 arguments.")
+	}
+	specFn, fn := ctx.Args().Get(0), ctx.Args().Get(1)
+
+	spec, err := readNetworkSpec(specFn)
+	if err != nil {
+		return fmt.Errorf("failed to read network spec: %v", err)
+	}
+
+	log.Info("Building genesis", "network", spec.Rules.Name, "id", spec.Rules.NetworkID, "validators", len(spec.Validators), "accounts", len(spec.Accounts))
+	store, err := makegenesis.BuildFromSpec(memorydb.NewProducer(""), spec)
+	if err != nil {
+		return fmt.Errorf("failed to build genesis: %v", err)
+	}
+	defer store.Close()
+
+	tmpPath := path.Join(filepath.Dir(fn), fmt.Sprintf("tmp-genesis-%d", os.Getpid()))
+	defer os.RemoveAll(tmpPath)
+	hashes, err := writeGenesisStore(fn, store, tmpPath)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Genesis is built", "file", fn)
+	fmt.Printf("- Genesis ID: %v \n", store.Header().GenesisID.String())
+	fmt.Printf("- Epochs hash: %v \n", hashes[genesisstore.EpochsSection(0)].String())
+	fmt.Printf("- Blocks hash: %v \n", hashes[genesisstore.BlocksSection(0)].String())
+	fmt.Printf("- EVM hash: %v \n", hashes[genesisstore.EvmSection(0)].String())
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"math"
 	"os"
 	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -202,6 +203,311 @@ func getEpochBlock(epoch idx.Epoch, store *gossip.Store) idx.Block {
 	return bs.LastBlock.Idx
 }
 
+// maxGenesisUnitIndex is the highest index of a unit which is read by the genesis store
+const maxGenesisUnitIndex = 1000
+
+// genesisUnit is a unit of the exported genesis file.
+// Units of block and epoch records contain the records of the epochs range.
+type genesisUnit struct {
+	name     string
+	from, to idx.Epoch
+}
+
+// sectionIndex parses the index of the unit name, e.g. 2 for "brs-2"
+func sectionIndex(name string, section func(int) string) (int, error) {
+	if name == section(0) {
+		return 0, nil
+	}
+	var i int
+	_, err := fmt.Sscanf(name, section(0)+"-%d", &i)
+	if err != nil || i <= 0 || i > maxGenesisUnitIndex || section(i) != name {
+		return 0, fmt.Errorf("malformed section name '%s'", name)
+	}
+	return i, nil
+}
+
+// splitEpochs splits the epochs into consecutive ranges of the given size, or returns the whole range if the size is zero
+func splitEpochs(from, to, size idx.Epoch) [][2]idx.Epoch {
+	if size == 0 {
+		return [][2]idx.Epoch{{from, to}}
+	}
+	if from > to {
+		return nil
+	}
+	ranges := make([][2]idx.Epoch, 0, (to-from)/size+1)
+	for start := from; start <= to && start >= from; start += size {
+		end := start + size - 1
+		if end > to || end < start {
+			end = to
+		}
+		ranges = append(ranges, [2]idx.Epoch{start, end})
+	}
+	return ranges
+}
+
+// planGenesisUnits returns the units in the order of writing. Each epochs range is exported into the units
+// of block and epoch records with the same index, so units with higher indexes contain higher epochs.
+// Indexes start from the index of the section name, or from firstIndex if it's not zero.
+func planGenesisUnits(sections map[string]string, from, to, unitEpochs idx.Epoch, firstIndex int) ([]genesisUnit, error) {
+	indexOf := func(kind string, section func(int) string) (int, error) {
+		if firstIndex != 0 {
+			return firstIndex, nil
+		}
+		return sectionIndex(sections[kind], section)
+	}
+	units := make([]genesisUnit, 0, 3)
+	for k, r := range splitEpochs(from, to, unitEpochs) {
+		for _, kind := range []string{"ers", "brs"} {
+			if len(sections[kind]) == 0 {
+				continue
+			}
+			section := genesisstore.EpochsSection
+			if kind == "brs" {
+				section = genesisstore.BlocksSection
+			}
+			i, err := indexOf(kind, section)
+			if err != nil {
+				return nil, err
+			}
+			if i+k > maxGenesisUnitIndex {
+				return nil, fmt.Errorf("too many units, the highest index is %d", maxGenesisUnitIndex)
+			}
+			units = append(units, genesisUnit{
+				name: section(i + k),
+				from: r[0],
+				to:   r[1],
+			})
+		}
+	}
+	if len(sections["evm"]) > 0 {
+		i, err := indexOf("evm", genesisstore.EvmSection)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, genesisUnit{
+			name: genesisstore.EvmSection(i),
+		})
+	}
+	return units, nil
+}
+
+func printUnitHash(name string, h hash.Hash) {
+	kind := "EVM"
+	base := genesisstore.EvmSection(0)
+	if strings.HasPrefix(name, "brs") {
+		kind, base = "Blocks", genesisstore.BlocksSection(0)
+	} else if strings.HasPrefix(name, "ers") {
+		kind, base = "Epochs", genesisstore.EpochsSection(0)
+	}
+	if name == base {
+		fmt.Printf("- %s hash: %v \n", kind, h.String())
+	} else {
+		fmt.Printf("- %s hash (%s): %v \n", kind, name, h.String())
+	}
+}
+
+// firstEpochRecord returns the first record of the epochs unit, which is the highest epoch of the unit
+func firstEpochRecord(store *genesisstore.Store, name string) (*ier.LlrIdxFullEpochRecord, error) {
+	r, err := store.Section(name)
+	if err != nil {
+		return nil, err
+	}
+	er := ier.LlrIdxFullEpochRecord{}
+	err = rlp.NewStream(r, 0).Decode(&er)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &er, nil
+}
+
+// readGenesisChainTip returns the highest epoch of the genesis file and the index for the units which extend it
+func readGenesisChainTip(fn string, header genesis.Header) (idx.Epoch, int, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return 0, 0, err
+	}
+	store, hashes, err := genesisstore.OpenGenesisStore(f)
+	if err != nil {
+		_ = f.Close()
+		return 0, 0, err
+	}
+	defer store.Close()
+	if !store.Header().Equal(header) {
+		return 0, 0, errors.New("the extended genesis file belongs to another network")
+	}
+
+	next, topEpochs := 0, -1
+	for name := range hashes {
+		for _, section := range []func(int) string{genesisstore.BlocksSection, genesisstore.EpochsSection, genesisstore.EvmSection} {
+			i, err := sectionIndex(name, section)
+			if err != nil {
+				continue
+			}
+			if i >= next {
+				next = i + 1
+			}
+			if section(0) == genesisstore.EpochsSection(0) && i > topEpochs {
+				topEpochs = i
+			}
+		}
+	}
+	if topEpochs < 0 {
+		return 0, 0, errors.New("the extended genesis file has no epoch records")
+	}
+	er, err := firstEpochRecord(store, genesisstore.EpochsSection(topEpochs))
+	if err != nil {
+		return 0, 0, err
+	}
+	if er == nil {
+		return 0, 0, errors.New("the extended genesis file has no epoch records")
+	}
+	return er.Idx, next, nil
+}
+
+// resumeGenesisExport keeps the complete units of an interrupted export and discards the rest of the file.
+// Returns the number of the complete units, which have to be the first planned units.
+func resumeGenesisExport(fh *os.File, units []genesisUnit, header genesis.Header) (int, error) {
+	stat, err := fh.Stat()
+	if err != nil {
+		return 0, err
+	}
+	infos, end := genesisstore.ReadCompleteUnitsInfo(fh, stat.Size())
+	if len(infos) > len(units) {
+		return 0, errors.New("the file contains more units than the export")
+	}
+	for i, info := range infos {
+		if info.UnitName != units[i].name || !info.Header.Equal(header) {
+			return 0, fmt.Errorf("unit '%s' of the file doesn't match the export", info.UnitName)
+		}
+	}
+	if err := fh.Truncate(end); err != nil {
+		return 0, err
+	}
+	if _, err := fh.Seek(end, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if len(infos) == 0 {
+		return 0, nil
+	}
+
+	// check that the complete units have the same epochs ranges
+	f, err := os.Open(fh.Name())
+	if err != nil {
+		return 0, err
+	}
+	store, _, err := genesisstore.OpenGenesisStore(f)
+	if err != nil {
+		_ = f.Close()
+		return 0, err
+	}
+	defer store.Close()
+	for i, info := range infos {
+		if !strings.HasPrefix(info.UnitName, "ers") {
+			continue
+		}
+		er, err := firstEpochRecord(store, info.UnitName)
+		if err != nil {
+			return 0, err
+		}
+		if er != nil && er.Idx != units[i].to {
+			return 0, fmt.Errorf("unit '%s' of the file ends at epoch %d instead of %d, resume with the same epochs range", info.UnitName, er.Idx, units[i].to)
+		}
+	}
+
+	log.Info("Resuming genesis export", "complete", len(infos), "units", len(units))
+	for _, info := range infos {
+		printUnitHash(info.UnitName, info.Hash)
+	}
+	return len(infos), nil
+}
+
+func exportEpochsUnit(writer *unitWriter, gdb *gossip.Store, from, to idx.Epoch) error {
+	for i := to; i >= from; i-- {
+		er := gdb.GetFullEpochRecord(i)
+		if er == nil {
+			log.Warn("No epoch record", "epoch", i)
+			break
+		}
+		b, _ := rlp.EncodeToBytes(ier.LlrIdxFullEpochRecord{
+			LlrFullEpochRecord: *er,
+			Idx:                i,
+		})
+		_, err := writer.Write(b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func exportBlocksUnit(writer *unitWriter, gdb *gossip.Store, fromBlock, toBlock idx.Block) error {
+	for i := toBlock; i >= fromBlock; i-- {
+		br := gdb.GetFullBlockRecord(i)
+		if br == nil {
+			log.Warn("No block record", "block", i)
+			break
+		}
+		if i%200000 == 0 {
+			log.Info("Exporting blocks", "last", i)
+		}
+		b, _ := rlp.EncodeToBytes(ibr.LlrIdxFullBlockRecord{
+			LlrFullBlockRecord: *br,
+			Idx:                i,
+		})
+		_, err := writer.Write(b)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportGenesisUnits writes the units into plain, or only calculates their hashes if plain is nil
+func exportGenesisUnits(plain io.WriteSeeker, gdb *gossip.Store, header genesis.Header, units []genesisUnit, tmpPath string, exportEvm func(io.Writer) error) error {
+	for _, unit := range units {
+		writer := newUnitWriter(plain)
+		err := writer.Start(header, unit.name, tmpPath)
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(unit.name, "ers"):
+			log.Info("Exporting epochs", "unit", unit.name, "from", unit.from, "to", unit.to)
+			err = exportEpochsUnit(writer, gdb, unit.from, unit.to)
+		case strings.HasPrefix(unit.name, "brs"):
+			toBlock := getEpochBlock(unit.to, gdb)
+			fromBlock := getEpochBlock(unit.from, gdb)
+			if unit.name != genesisstore.BlocksSection(0) {
+				// to continue prev section, include blocks of prev epochs too, excluding first blocks of prev epoch (which is last block if prev section)
+				fromBlock = getEpochBlock(unit.from-1, gdb) + 1
+			}
+			if fromBlock < 1 {
+				// avoid underflow
+				fromBlock = 1
+			}
+			log.Info("Exporting blocks", "unit", unit.name, "from", fromBlock, "to", toBlock)
+			err = exportBlocksUnit(writer, gdb, fromBlock, toBlock)
+		default:
+			log.Info("Exporting EVM data", "unit", unit.name)
+			err = exportEvm(writer)
+		}
+		if err != nil {
+			return err
+		}
+		h, err := writer.Flush()
+		if err != nil {
+			return err
+		}
+		log.Info("Exported unit", "unit", unit.name)
+		printUnitHash(unit.name, h)
+	}
+
+	return nil
+}
+
 func exportGenesis(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
 		utils.Fatalf("This command requires an argument.")
@@ -227,6 +533,10 @@ func exportGenesis(ctx *cli.Context) error {
 	if mode != "full" && mode != "ext-mpt" && mode != "mpt" {
 		return errors.New("--export.evm.mode must be one of {full, ext-mpt, mpt}")
 	}
+	unitEpochs := ctx.Uint64(GenesisExportUnitEpochs.Name)
+	if unitEpochs > math.MaxUint32 {
+		return errors.New("--export.unit.epochs is too large")
+	}
 
 	var excludeEvmDB kvdb.Store
 	if excludeEvmDBPath := ctx.String(EvmExportExclude.Name); len(excludeEvmDBPath) > 0 {
@@ -238,6 +548,10 @@ func exportGenesis(ctx *cli.Context) error {
 	}
 
 	sectionsStr := ctx.String(GenesisExportSections.Name)
+	if len(ctx.String(GenesisExportExtend.Name)) > 0 && !ctx.IsSet(GenesisExportSections.Name) {
+		// the extended file already has the EVM data, a full EVM dump isn't repeated unless it's requested explicitly
+		sectionsStr = "brs,ers"
+	}
 	sections := map[string]string{}
 	for _, str := range strings.Split(sectionsStr, ",") {
 		before := len(sections)
@@ -269,25 +583,11 @@ func exportGenesis(ctx *cli.Context) error {
 
 	fn := ctx.Args().First()
 
-	// Open the file handle
-	var plain io.WriteSeeker
-	if fn != "dry-run" {
-		fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
-		if err != nil {
-			return err
-		}
-		defer fh.Close()
-		plain = fh
-	}
-
 	header := genesis.Header{
 		GenesisID:   *gdb.GetGenesisID(),
 		NetworkID:   gdb.GetEpochState().Rules.NetworkID,
 		NetworkName: gdb.GetEpochState().Rules.Name,
 	}
-	var epochsHash hash.Hash
-	var blocksHash hash.Hash
-	var evmHash hash.Hash
 
 	if from < 1 {
 		// avoid underflow
@@ -296,86 +596,54 @@ func exportGenesis(ctx *cli.Context) error {
 	if to > gdb.GetEpoch() {
 		to = gdb.GetEpoch()
 	}
-	if len(sections["ers"]) > 0 {
-		log.Info("Exporting epochs", "from", from, "to", to)
-		writer := newUnitWriter(plain)
-		err := writer.Start(header, sections["ers"], tmpPath)
+
+	firstIndex := 0
+	if extendPath := ctx.String(GenesisExportExtend.Name); len(extendPath) > 0 {
+		if absExtend, absFn := absPath(extendPath), absPath(fn); absExtend == absFn {
+			return errors.New("the extended genesis file has to differ from the output file")
+		}
+		lastEpoch, next, err := readGenesisChainTip(extendPath, header)
 		if err != nil {
 			return err
 		}
-		for i := to; i >= from; i-- {
-			er := gdb.GetFullEpochRecord(i)
-			if er == nil {
-				log.Warn("No epoch record", "epoch", i)
-				break
-			}
-			b, _ := rlp.EncodeToBytes(ier.LlrIdxFullEpochRecord{
-				LlrFullEpochRecord: *er,
-				Idx:                i,
-			})
-			_, err := writer.Write(b)
-			if err != nil {
-				return err
-			}
+		if len(ctx.Args()) > 1 && from != lastEpoch+1 {
+			return fmt.Errorf("the extended genesis file ends at epoch %d, the export has to start from epoch %d", lastEpoch, lastEpoch+1)
 		}
-		epochsHash, err = writer.Flush()
-		if err != nil {
-			return err
+		from = lastEpoch + 1
+		if from > to {
+			return errors.New("no new epochs to extend the genesis file with")
 		}
-		log.Info("Exported epochs")
-		fmt.Printf("- Epochs hash: %v \n", epochsHash.String())
+		firstIndex = next
+		log.Info("Extending genesis file", "file", extendPath, "from", from, "index", firstIndex)
+	}
+	units, err := planGenesisUnits(sections, from, to, idx.Epoch(unitEpochs), firstIndex)
+	if err != nil {
+		return err
 	}
 
-	if len(sections["brs"]) > 0 {
-		toBlock := getEpochBlock(to, gdb)
-		fromBlock := getEpochBlock(from, gdb)
-		if sections["brs"] != "brs" {
-			// to continue prev section, include blocks of prev epochs too, excluding first blocks of prev epoch (which is last block if prev section)
-			fromBlock = getEpochBlock(from-1, gdb) + 1
-		}
-		if fromBlock < 1 {
-			// avoid underflow
-			fromBlock = 1
+	// Open the file handle
+	var plain io.WriteSeeker
+	if fn != "dry-run" {
+		flags := os.O_CREATE | os.O_RDWR
+		if !ctx.Bool(GenesisExportResume.Name) {
+			flags |= os.O_TRUNC
 		}
-		log.Info("Exporting blocks", "from", fromBlock, "to", toBlock)
-		writer := newUnitWriter(plain)
-		err := writer.Start(header, sections["brs"], tmpPath)
+		fh, err := os.OpenFile(fn, flags, os.ModePerm)
 		if err != nil {
 			return err
 		}
-		for i := toBlock; i >= fromBlock; i-- {
-			br := gdb.GetFullBlockRecord(i)
-			if br == nil {
-				log.Warn("No block record", "block", i)
-				break
-			}
-			if i%200000 == 0 {
-				log.Info("Exporting blocks", "last", i)
-			}
-			b, _ := rlp.EncodeToBytes(ibr.LlrIdxFullBlockRecord{
-				LlrFullBlockRecord: *br,
-				Idx:                i,
-			})
-			_, err := writer.Write(b)
+		defer fh.Close()
+		if ctx.Bool(GenesisExportResume.Name) {
+			complete, err := resumeGenesisExport(fh, units, header)
 			if err != nil {
 				return err
 			}
+			units = units[complete:]
 		}
-		blocksHash, err = writer.Flush()
-		if err != nil {
-			return err
-		}
-		log.Info("Exported blocks")
-		fmt.Printf("- Blocks hash: %v \n", blocksHash.String())
+		plain = fh
 	}
 
-	if len(sections["evm"]) > 0 {
-		log.Info("Exporting EVM data")
-		writer := newUnitWriter(plain)
-		err := writer.Start(header, sections["evm"], tmpPath)
-		if err != nil {
-			return err
-		}
+	return exportGenesisUnits(plain, gdb, header, units, tmpPath, func(writer io.Writer) error {
 		it := gdb.EvmStore().EvmDb.NewIterator(nil, nil)
 		if mode == "mpt" {
 			// iterate only over MPT data
@@ -388,17 +656,15 @@ func exportGenesis(ctx *cli.Context) error {
 			it = excludingIterator{it, excludeEvmDB}
 		}
 		defer it.Release()
-		err = iodb.Write(writer, it)
-		if err != nil {
-			return err
-		}
-		evmHash, err = writer.Flush()
-		if err != nil {
-			return err
-		}
-		log.Info("Exported EVM data")
-		fmt.Printf("- EVM hash: %v \n", evmHash.String())
-	}
+		return iodb.Write(writer, it)
+	})
+}
 
-	return nil
+// absPath returns the absolute path, or the path itself if it cannot be resolved
+func absPath(p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+	return abs
 }
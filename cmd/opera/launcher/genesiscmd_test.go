@@ -0,0 +1,190 @@
+package launcher
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/inter/ibr"
+	"github.com/Fantom-foundation/go-opera/inter/ier"
+	"github.com/Fantom-foundation/go-opera/opera/genesis"
+	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
+)
+
+func TestSplitEpochs(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal([][2]idx.Epoch{{5, 20}}, splitEpochs(5, 20, 0))
+	require.Equal([][2]idx.Epoch{{5, 9}, {10, 14}, {15, 19}, {20, 20}}, splitEpochs(5, 20, 5))
+	require.Equal([][2]idx.Epoch{{5, 20}}, splitEpochs(5, 20, 100))
+	require.Empty(splitEpochs(21, 20, 5))
+	// no overflow at the end of the epochs range
+	require.Equal([][2]idx.Epoch{{0xfffffffe, 0xffffffff}}, splitEpochs(0xfffffffe, 0xffffffff, 10))
+}
+
+func TestSectionIndex(t *testing.T) {
+	require := require.New(t)
+
+	i, err := sectionIndex("brs", genesisstore.BlocksSection)
+	require.NoError(err)
+	require.Equal(0, i)
+	i, err = sectionIndex("brs-12", genesisstore.BlocksSection)
+	require.NoError(err)
+	require.Equal(12, i)
+	for _, name := range []string{"brs-0", "brs-x", "brs12", "ers-1", "brs-1001"} {
+		_, err = sectionIndex(name, genesisstore.BlocksSection)
+		require.Error(err, name)
+	}
+}
+
+func TestPlanGenesisUnits(t *testing.T) {
+	require := require.New(t)
+
+	names := func(units []genesisUnit) []string {
+		res := make([]string, len(units))
+		for i, u := range units {
+			res[i] = u.name
+		}
+		return res
+	}
+
+	// a single unit per section
+	units, err := planGenesisUnits(map[string]string{"brs": "brs", "ers": "ers", "evm": "evm"}, 1, 10, 0, 0)
+	require.NoError(err)
+	require.Equal([]string{"ers", "brs", "evm"}, names(units))
+	require.Equal(genesisUnit{name: "brs", from: 1, to: 10}, units[1])
+
+	// ranges of epochs
+	units, err = planGenesisUnits(map[string]string{"brs": "brs-1", "ers": "ers-1"}, 5000, 6000, 500, 0)
+	require.NoError(err)
+	require.Equal([]string{"ers-1", "brs-1", "ers-2", "brs-2", "ers-3", "brs-3"}, names(units))
+	require.Equal(genesisUnit{name: "brs-3", from: 6000, to: 6000}, units[5])
+
+	// extension of a previous file
+	units, err = planGenesisUnits(map[string]string{"brs": "brs", "ers": "ers", "evm": "evm"}, 6001, 6100, 0, 4)
+	require.NoError(err)
+	require.Equal([]string{"ers-4", "brs-4", "evm-4"}, names(units))
+
+	_, err = planGenesisUnits(map[string]string{"brs": "brs-1000"}, 1, 10, 5, 0)
+	require.Error(err)
+}
+
+func TestExportGenesisResumeExtend(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genesis")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	tmpPath := filepath.Join(dir, "tmp")
+
+	src := gossip.NewMemStore()
+	defer src.Close()
+	writeTestRecords(src, 6)
+	header := genesis.Header{
+		GenesisID:   hash.Hash(hash.FakeHash(1)),
+		NetworkID:   4003,
+		NetworkName: "test",
+	}
+	noEvm := func(io.Writer) error {
+		return errors.New("no EVM units are expected")
+	}
+	export := func(fn string, units []genesisUnit) {
+		fh, err := os.Create(fn)
+		require.NoError(err)
+		defer fh.Close()
+		require.NoError(exportGenesisUnits(fh, src, header, units, tmpPath, noEvm))
+	}
+	readGenesis := func(fn string) ([]idx.Epoch, []idx.Block, genesis.Hashes) {
+		f, err := os.Open(fn)
+		require.NoError(err)
+		store, hashes, err := genesisstore.OpenGenesisStore(f)
+		require.NoError(err)
+		defer store.Close()
+		require.Equal(header, store.Header())
+		var epochs []idx.Epoch
+		store.Epochs().ForEach(func(er ier.LlrIdxFullEpochRecord) bool {
+			require.Equal(src.GetFullEpochRecord(er.Idx).Hash(), er.Hash())
+			epochs = append(epochs, er.Idx)
+			return true
+		})
+		var blocks []idx.Block
+		store.Blocks().ForEach(func(br ibr.LlrIdxFullBlockRecord) bool {
+			require.Equal(src.GetFullBlockRecord(br.Idx).Hash(), br.Hash())
+			blocks = append(blocks, br.Idx)
+			return true
+		})
+		return epochs, blocks, hashes
+	}
+
+	sections := map[string]string{"brs": "brs", "ers": "ers"}
+	units, err := planGenesisUnits(sections, 1, 4, 2, 0)
+	require.NoError(err)
+	fullFn := filepath.Join(dir, "full.g")
+	export(fullFn, units)
+	full, err := ioutil.ReadFile(fullFn)
+	require.NoError(err)
+
+	// interrupt the export in the middle of the last unit
+	fn := filepath.Join(dir, "genesis.g")
+	require.NoError(ioutil.WriteFile(fn, full[:len(full)-10], 0600))
+	fh, err := os.OpenFile(fn, os.O_RDWR, 0600)
+	require.NoError(err)
+	complete, err := resumeGenesisExport(fh, units, header)
+	require.NoError(err)
+	require.Equal(3, complete)
+	require.NoError(exportGenesisUnits(fh, src, header, units[complete:], tmpPath, noEvm))
+	require.NoError(fh.Close())
+	resumed, err := ioutil.ReadFile(fn)
+	require.NoError(err)
+	require.Equal(full, resumed)
+
+	epochs, blocks, hashes := readGenesis(fn)
+	require.Equal([]idx.Epoch{4, 3, 2, 1}, epochs)
+	require.Equal([]idx.Block{8, 7, 6, 5, 4, 3, 2}, blocks)
+	require.Len(hashes, 4)
+
+	// a resumed export has to be the same
+	fh, err = os.OpenFile(fn, os.O_RDWR, 0600)
+	require.NoError(err)
+	other, err := planGenesisUnits(sections, 1, 4, 3, 0)
+	require.NoError(err)
+	_, err = resumeGenesisExport(fh, other, header)
+	require.Error(err)
+	require.NoError(fh.Close())
+
+	// extend the file with the next epochs
+	lastEpoch, next, err := readGenesisChainTip(fn, header)
+	require.NoError(err)
+	require.Equal(idx.Epoch(4), lastEpoch)
+	require.Equal(2, next)
+	units, err = planGenesisUnits(sections, lastEpoch+1, 6, 0, next)
+	require.NoError(err)
+	extFn := filepath.Join(dir, "ext.g")
+	export(extFn, units)
+
+	epochs, blocks, hashes = readGenesis(extFn)
+	require.Equal([]idx.Epoch{6, 5}, epochs)
+	require.Equal([]idx.Block{12, 11, 10, 9}, blocks)
+	require.Equal([]string{"brs-2", "ers-2"}, sortedNames(hashes))
+
+	_, _, err = readGenesisChainTip(fn, genesis.Header{NetworkID: 1})
+	require.Error(err)
+}
+
+func sortedNames(hashes genesis.Hashes) []string {
+	names := make([]string, 0, len(hashes))
+	for name := range hashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,164 @@
+package launcher
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/inter/ibr"
+	"github.com/Fantom-foundation/go-opera/inter/ier"
+	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
+	"github.com/Fantom-foundation/go-opera/utils/iodb"
+)
+
+// unitSummary describes the records of a genesis unit.
+type unitSummary struct {
+	records     uint64
+	first, last string
+}
+
+func describeBlockRecord(br ibr.LlrIdxFullBlockRecord) string {
+	return fmt.Sprintf("block=%d atropos=%s time=%s txs=%d gasUsed=%d",
+		br.Idx, br.Atropos.String(), br.Time.Time().UTC().Format("2006-01-02 15:04:05"), len(br.Txs), br.GasUsed)
+}
+
+func describeEpochRecord(er ier.LlrIdxFullEpochRecord) string {
+	return fmt.Sprintf("epoch=%d start=%s lastBlock=%d validators=%d stateRoot=%s",
+		er.Idx, er.EpochState.EpochStart.Time().UTC().Format("2006-01-02 15:04:05"), er.BlockState.LastBlock.Idx,
+		er.EpochState.Validators.Len(), er.BlockState.FinalizedStateRoot.String())
+}
+
+// summarizeUnit reads all the records of the unit, which verifies every piece of the unit against its hashes.
+func summarizeUnit(store *genesisstore.Store, name string) (unitSummary, error) {
+	summary := unitSummary{}
+	r, err := store.Section(name)
+	if err != nil {
+		return summary, err
+	}
+	add := func(record string) {
+		if summary.records == 0 {
+			summary.first = record
+		}
+		summary.last = record
+		summary.records++
+	}
+	switch {
+	case strings.HasPrefix(name, "brs"):
+		stream := rlp.NewStream(r, 0)
+		for {
+			br := ibr.LlrIdxFullBlockRecord{}
+			err = stream.Decode(&br)
+			if err == io.EOF {
+				return summary, nil
+			}
+			if err != nil {
+				return summary, err
+			}
+			add(describeBlockRecord(br))
+		}
+	case strings.HasPrefix(name, "ers"):
+		stream := rlp.NewStream(r, 0)
+		for {
+			er := ier.LlrIdxFullEpochRecord{}
+			err = stream.Decode(&er)
+			if err == io.EOF {
+				return summary, nil
+			}
+			if err != nil {
+				return summary, err
+			}
+			add(describeEpochRecord(er))
+		}
+	case strings.HasPrefix(name, "evm"):
+		it := iodb.NewIterator(r)
+		defer it.Release()
+		for it.Next() {
+			summary.records++
+		}
+		return summary, it.Error()
+	default:
+		_, err = io.Copy(ioutil.Discard, r)
+		return summary, err
+	}
+}
+
+func inspectGenesis(ctx *cli.Context) error {
+	return checkGenesisFile(ctx, false)
+}
+
+func verifyGenesis(ctx *cli.Context) error {
+	return checkGenesisFile(ctx, true)
+}
+
+// checkGenesisFile prints the genesis header and units metadata.
+// Blocks and epochs units are read to print their first and last records.
+// If full is true, EVM units are read too, so every piece of the file is verified.
+func checkGenesisFile(ctx *cli.Context, full bool) error {
+	if len(ctx.Args()) != 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	fn := ctx.Args().First()
+
+	f, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	units, err := genesisstore.ReadUnitsInfo(f)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to read genesis file: %v", err)
+	}
+	if len(units) == 0 {
+		_ = f.Close()
+		return errors.New("genesis file has no units")
+	}
+	store, _, err := genesisstore.OpenGenesisStore(f)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to read genesis file: %v", err)
+	}
+	defer store.Close()
+
+	header := store.Header()
+	fmt.Printf("Genesis ID:   %s\n", header.GenesisID.String())
+	fmt.Printf("Network ID:   %d\n", header.NetworkID)
+	fmt.Printf("Network name: %s\n", header.NetworkName)
+	fmt.Printf("Units:        %d\n", len(units))
+
+	var corrupted []string
+	for _, unit := range units {
+		fmt.Printf("- %s: hash=%s compressed=%s uncompressed=%s\n", unit.UnitName, unit.Hash.String(),
+			common.StorageSize(unit.CompressedSize), common.StorageSize(unit.UncompressedSize))
+		if strings.HasPrefix(unit.UnitName, "evm") && !full {
+			continue
+		}
+		summary, err := summarizeUnit(store, unit.UnitName)
+		if err != nil {
+			log.Error("Genesis unit is corrupted", "unit", unit.UnitName, "err", err)
+			corrupted = append(corrupted, unit.UnitName)
+			continue
+		}
+		fmt.Printf("    records: %d\n", summary.records)
+		if len(summary.first) != 0 {
+			fmt.Printf("    first:   %s\n", summary.first)
+			fmt.Printf("    last:    %s\n", summary.last)
+		}
+	}
+
+	if len(corrupted) != 0 {
+		return fmt.Errorf("genesis file is corrupted, invalid units: %s", strings.Join(corrupted, ","))
+	}
+	if full {
+		fmt.Println("Genesis file is verified")
+	}
+	return nil
+}
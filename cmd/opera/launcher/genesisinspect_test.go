@@ -0,0 +1,120 @@
+package launcher
+
+import (
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/Fantom-foundation/go-opera/gossip"
+	"github.com/Fantom-foundation/go-opera/opera/genesis"
+	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
+	"github.com/Fantom-foundation/go-opera/utils/iodb"
+)
+
+// writeTestGenesis exports a genesis file with blocks, epochs and EVM units
+func writeTestGenesis(t *testing.T, fn string) {
+	src := gossip.NewMemStore()
+	defer src.Close()
+	writeTestRecords(src, 3)
+
+	evmDB := memorydb.New()
+	for i := byte(1); i <= 5; i++ {
+		require.NoError(t, evmDB.Put([]byte{i}, []byte{i, i}))
+	}
+	exportEvm := func(w io.Writer) error {
+		it := evmDB.NewIterator(nil, nil)
+		defer it.Release()
+		return iodb.Write(w, it)
+	}
+
+	units, err := planGenesisUnits(map[string]string{"brs": "brs", "ers": "ers"}, 1, 3, 0, 0)
+	require.NoError(t, err)
+	units = append(units, genesisUnit{name: genesisstore.EvmSection(0)})
+	fh, err := os.Create(fn)
+	require.NoError(t, err)
+	defer fh.Close()
+	header := genesis.Header{
+		GenesisID:   hash.Hash(hash.FakeHash(1)),
+		NetworkID:   4003,
+		NetworkName: "test",
+	}
+	require.NoError(t, exportGenesisUnits(fh, src, header, units, filepath.Join(filepath.Dir(fn), "tmp"), exportEvm))
+}
+
+func genesisFileContext(fn string) *cli.Context {
+	set := flag.NewFlagSet("test", 0)
+	_ = set.Parse([]string{fn})
+	return cli.NewContext(nil, set, nil)
+}
+
+func TestInspectGenesis(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "genesis")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "test.g")
+	writeTestGenesis(t, fn)
+
+	f, err := os.Open(fn)
+	require.NoError(err)
+	units, err := genesisstore.ReadUnitsInfo(f)
+	require.NoError(err)
+	store, hashes, err := genesisstore.OpenGenesisStore(f)
+	require.NoError(err)
+	require.Len(units, 3)
+	offset := int64(0)
+	for _, unit := range units {
+		require.Equal(hashes[unit.UnitName], unit.Hash)
+		require.Equal(uint64(4003), unit.Header.NetworkID)
+		require.Greater(unit.Offset, offset)
+		require.NotZero(unit.CompressedSize)
+		require.NotZero(unit.UncompressedSize)
+		offset = unit.Offset + int64(unit.CompressedSize)
+	}
+
+	blocks, err := summarizeUnit(store, genesisstore.BlocksSection(0))
+	require.NoError(err)
+	// the records are exported from the last one, down to the last block of the first epoch
+	require.Equal(uint64(5), blocks.records)
+	require.Contains(blocks.first, "block=6 ")
+	require.Contains(blocks.last, "block=2 ")
+	require.Contains(blocks.last, "txs=1 gasUsed=21000")
+	epochs, err := summarizeUnit(store, genesisstore.EpochsSection(0))
+	require.NoError(err)
+	require.Equal(uint64(3), epochs.records)
+	require.Contains(epochs.first, "epoch=3 ")
+	require.Contains(epochs.first, "lastBlock=6 validators=2")
+	require.Contains(epochs.last, "epoch=1 ")
+	evm, err := summarizeUnit(store, genesisstore.EvmSection(0))
+	require.NoError(err)
+	require.Equal(uint64(5), evm.records)
+	require.Empty(evm.first)
+	require.NoError(store.Close())
+
+	require.NoError(inspectGenesis(genesisFileContext(fn)))
+	require.NoError(verifyGenesis(genesisFileContext(fn)))
+
+	// corrupt the EVM unit data, which is read only by the verification
+	data, err := ioutil.ReadFile(fn)
+	require.NoError(err)
+	evmUnit := units[2]
+	require.Equal(genesisstore.EvmSection(0), evmUnit.UnitName)
+	data[evmUnit.Offset+int64(evmUnit.CompressedSize)/2] ^= 0xff
+	require.NoError(ioutil.WriteFile(fn, data, 0600))
+
+	require.NoError(inspectGenesis(genesisFileContext(fn)))
+	require.EqualError(verifyGenesis(genesisFileContext(fn)), "genesis file is corrupted, invalid units: "+evmUnit.UnitName)
+
+	empty := filepath.Join(dir, "empty.g")
+	require.NoError(ioutil.WriteFile(empty, nil, 0600))
+	require.EqualError(inspectGenesis(genesisFileContext(empty)), "genesis file has no units")
+}
@@ -22,9 +22,12 @@ import (
 	"github.com/status-im/keycard-go/hexutils"
 	"gopkg.in/urfave/cli.v1"
 
+	"github.com/Fantom-foundation/go-opera/eventcheck"
 	"github.com/Fantom-foundation/go-opera/gossip"
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
 	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/ibr"
+	"github.com/Fantom-foundation/go-opera/inter/ier"
 	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
 	"github.com/Fantom-foundation/go-opera/utils/ioread"
 )
@@ -76,13 +79,25 @@ func importEvents(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 
-	// avoid P2P interaction, API calls and events emitting
 	genesisStore := mayGetGenesisStore(ctx)
 	cfg := makeAllConfigs(ctx)
+	isolateImportNode(cfg)
+
+	err := importEventsToNode(ctx, cfg, genesisStore, ctx.Args()...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// isolateImportNode avoids P2P interaction, API calls and events emitting
+func isolateImportNode(cfg *config) {
 	cfg.Opera.Protocol.EventsSemaphoreLimit.Size = math.MaxUint32
 	cfg.Opera.Protocol.EventsSemaphoreLimit.Num = math.MaxUint32
 	cfg.Emitter.Validator = emitter.ValidatorConfig{}
 	cfg.TxPool.Journal = ""
+	cfg.TxPool.Snapshot = ""
 	cfg.Node.IPCPath = ""
 	cfg.Node.HTTPHost = ""
 	cfg.Node.WSHost = ""
@@ -94,13 +109,6 @@ func importEvents(ctx *cli.Context) error {
 	cfg.Node.P2P.BootstrapNodesV5 = nil
 	cfg.Node.P2P.StaticNodes = nil
 	cfg.Node.P2P.TrustedNodes = nil
-
-	err := importEventsToNode(ctx, cfg, genesisStore, ctx.Args()...)
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func importEventsToNode(ctx *cli.Context, cfg *config, genesisStore *genesisstore.Store, args ...string) error {
@@ -237,3 +245,160 @@ func importEventsFile(srv *gossip.Service, fn string) error {
 
 	return nil
 }
+
+func importBlocks(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+
+	genesisStore := mayGetGenesisStore(ctx)
+	cfg := makeAllConfigs(ctx)
+	isolateImportNode(cfg)
+
+	node, svc, nodeClose := makeNode(ctx, cfg, genesisStore)
+	defer nodeClose()
+	startNode(ctx, node)
+
+	allowUnverified := ctx.Bool(ImportUnverifiedFlag.Name)
+	for _, fn := range ctx.Args() {
+		log.Info("Importing blocks from file", "file", fn)
+		if err := importBlocksFile(svc, fn, allowUnverified); err != nil {
+			log.Error("Import error", "file", fn, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func checkBlocksFileHeader(reader io.Reader) error {
+	headerAndVersion := make([]byte, len(blocksFileHeader)+len(blocksFileVersion))
+	err := ioread.ReadAll(reader, headerAndVersion)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(headerAndVersion[:len(blocksFileHeader)], blocksFileHeader) {
+		return errors.New("expected a blocks file, mismatched file header")
+	}
+	if !bytes.Equal(headerAndVersion[len(blocksFileHeader):], blocksFileVersion) {
+		got := hexutils.BytesToHex(headerAndVersion[len(blocksFileHeader):])
+		expected := hexutils.BytesToHex(blocksFileVersion)
+		return fmt.Errorf("wrong version of blocks file, got=%s, expected=%s", got, expected)
+	}
+	return nil
+}
+
+// blockRecordsImporter applies the block and epoch records
+type blockRecordsImporter interface {
+	ProcessFullBlockRecord(br ibr.LlrIdxFullBlockRecord) error
+	ProcessFullEpochRecord(er ier.LlrIdxFullEpochRecord) error
+	ImportFullBlockRecord(br ibr.LlrIdxFullBlockRecord) (verified bool, err error)
+	ImportFullEpochRecord(er ier.LlrIdxFullEpochRecord) (verified bool, err error)
+}
+
+// importBlocksFile applies the block and epoch records via the LLR path.
+// Records are verified against the LLR votes, the not decided records are rejected
+// unless allowUnverified is set, in which case they are written unverified.
+func importBlocksFile(srv blockRecordsImporter, fn string, allowUnverified bool) error {
+	// Watch for Ctrl-C while the import is running.
+	// If a signal is received, the import will stop.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+		defer reader.(*gzip.Reader).Close()
+	}
+
+	// Check file version and header
+	if err := checkBlocksFileHeader(reader); err != nil {
+		return err
+	}
+
+	stream := rlp.NewStream(reader, 0)
+
+	start, reported := time.Now(), time.Time{}
+	var (
+		imported   int
+		skipped    int
+		unverified int
+		last       idx.Block
+	)
+	count := func(verified bool, err error) error {
+		if err == eventcheck.ErrAlreadyProcessedBR || err == eventcheck.ErrAlreadyProcessedER {
+			skipped++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		imported++
+		if !verified {
+			unverified++
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-interrupt:
+			return fmt.Errorf("interrupted")
+		default:
+		}
+		kind, err := stream.Uint()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case blocksFileBlockRecord:
+			br := ibr.LlrIdxFullBlockRecord{}
+			if err := stream.Decode(&br); err != nil {
+				return err
+			}
+			if allowUnverified {
+				err = count(srv.ImportFullBlockRecord(br))
+			} else {
+				err = count(true, srv.ProcessFullBlockRecord(br))
+			}
+			if err != nil {
+				return fmt.Errorf("block %d: %v", br.Idx, err)
+			}
+			last = br.Idx
+		case blocksFileEpochRecord:
+			er := ier.LlrIdxFullEpochRecord{}
+			if err := stream.Decode(&er); err != nil {
+				return err
+			}
+			if allowUnverified {
+				err = count(srv.ImportFullEpochRecord(er))
+			} else {
+				err = count(true, srv.ProcessFullEpochRecord(er))
+			}
+			if err != nil {
+				return fmt.Errorf("epoch %d: %v", er.Idx, err)
+			}
+		default:
+			return fmt.Errorf("unknown record kind %d", kind)
+		}
+		if time.Since(reported) >= statsReportLimit {
+			log.Info("Importing blocks", "last", last, "imported", imported, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Blocks import is finished", "file", fn, "last", last, "imported", imported, "unverified", unverified, "skipped", skipped, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return nil
+}
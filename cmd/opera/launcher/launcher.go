@@ -25,13 +25,16 @@ import (
 	"github.com/Fantom-foundation/go-opera/cmd/opera/launcher/tracing"
 	"github.com/Fantom-foundation/go-opera/debug"
 	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/evmcore/txpolicy"
 	"github.com/Fantom-foundation/go-opera/flags"
 	"github.com/Fantom-foundation/go-opera/gossip"
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
+	"github.com/Fantom-foundation/go-opera/graphql"
 	"github.com/Fantom-foundation/go-opera/integration"
 	"github.com/Fantom-foundation/go-opera/opera/genesis"
 	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
 	"github.com/Fantom-foundation/go-opera/utils/errlock"
+	"github.com/Fantom-foundation/go-opera/utils/promexp"
 	"github.com/Fantom-foundation/go-opera/valkeystore"
 	_ "github.com/Fantom-foundation/go-opera/version"
 )
@@ -97,6 +100,8 @@ func initFlags() {
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolRejournalFlag,
+		TxPoolSnapshotFlag,
+		TxPoolResnapshotFlag,
 		utils.TxPoolPriceLimitFlag,
 		utils.TxPoolPriceBumpFlag,
 		utils.TxPoolAccountSlotsFlag,
@@ -121,10 +126,22 @@ func initFlags() {
 		validatorIDFlag,
 		validatorPubkeyFlag,
 		validatorPasswordFlag,
+		validatorSignerFlag,
+		validatorSignerAuthFlag,
+		validatorSignerCAFlag,
 		SyncModeFlag,
 		GCModeFlag,
 		DBPresetFlag,
 		DBMigrationModeFlag,
+		DBFreezerFlag,
+		SentryModeFlag,
+		SentryNodesFlag,
+		PrivateTxsPeersFlag,
+		PrivateTxsOriginatorsFlag,
+		HistoryEpochsFlag,
+		HealthAddrFlag,
+		HealthMinPeersFlag,
+		HealthMaxBlockAgeFlag,
 	}
 	legacyRpcFlags = []cli.Flag{
 		utils.NoUSBFlag,
@@ -158,6 +175,8 @@ func initFlags() {
 		RPCGlobalGasCapFlag,
 		RPCGlobalTxFeeCapFlag,
 		RPCGlobalTimeoutFlag,
+		TraceIndexFlag,
+		AddressTxIndexFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -175,6 +194,7 @@ func initFlags() {
 		utils.MetricsInfluxDBTokenFlag,
 		utils.MetricsInfluxDBBucketFlag,
 		utils.MetricsInfluxDBOrganizationFlag,
+		MetricsPrometheusAddrFlag,
 		tracing.EnableFlag,
 	}
 
@@ -221,6 +241,7 @@ func init() {
 		importCommand,
 		exportCommand,
 		checkCommand,
+		genesisCommand,
 		// See snapshot.go
 		snapshotCommand,
 		// See dbcmd.go
@@ -242,6 +263,9 @@ func init() {
 
 		// Start metrics export if enabled
 		utils.SetupMetrics(ctx)
+		if ctx.GlobalIsSet(MetricsPrometheusAddrFlag.Name) {
+			promexp.Setup(ctx.GlobalString(MetricsPrometheusAddrFlag.Name))
+		}
 		// Start system runtime metrics collection
 		go evmetrics.CollectProcessMetrics(3 * time.Second)
 		return nil
@@ -326,21 +350,42 @@ func makeNode(ctx *cli.Context, cfg *config, genesisStore *genesisstore.Store) (
 		log.Info("Unlocked fake validator account", "address", coinbase.Address.Hex())
 	}
 
-	// unlock validator key
-	if !valPubkey.Empty() {
-		err := unlockValidatorKey(ctx, valPubkey, valKeystore)
+	var signer valkeystore.SignerI
+	var remoteSigner *valkeystore.RemoteSigner
+	if endpoint := ctx.GlobalString(validatorSignerFlag.Name); endpoint != "" {
+		// sign by the remote signer, the validator key isn't on the node's disk
+		var err error
+		remoteSigner, err = makeRemoteSigner(ctx, endpoint, valPubkey)
 		if err != nil {
-			utils.Fatalf("Failed to unlock validator key: %v", err)
+			utils.Fatalf("Failed to connect the remote signer: %v", err)
+		}
+		signer = remoteSigner
+	} else {
+		// unlock validator key
+		if !valPubkey.Empty() {
+			err := unlockValidatorKey(ctx, valPubkey, valKeystore)
+			if err != nil {
+				utils.Fatalf("Failed to unlock validator key: %v", err)
+			}
 		}
+		signer = valkeystore.NewSigner(valKeystore)
 	}
-	signer := valkeystore.NewSigner(valKeystore)
 
 	// Create and register a gossip network service.
+	txPolicy, err := txpolicy.New(cfg.TxPolicy)
+	if err != nil {
+		utils.Fatalf("Invalid TxPolicy config: %v", err)
+	}
 	newTxPool := func(reader evmcore.StateReader) gossip.TxPool {
 		if cfg.TxPool.Journal != "" {
 			cfg.TxPool.Journal = stack.ResolvePath(cfg.TxPool.Journal)
 		}
-		return evmcore.NewTxPool(cfg.TxPool, reader.Config(), reader)
+		if cfg.TxPool.Snapshot != "" {
+			cfg.TxPool.Snapshot = stack.ResolvePath(cfg.TxPool.Snapshot)
+		}
+		pool := evmcore.NewTxPool(cfg.TxPool, reader.Config(), reader)
+		pool.SetPolicy(txPolicy)
+		return pool
 	}
 	haltCheck := func(oldEpoch, newEpoch idx.Epoch, age time.Time) bool {
 		stop := ctx.GlobalIsSet(ExitWhenAgeFlag.Name) && ctx.GlobalDuration(ExitWhenAgeFlag.Name) >= time.Since(age)
@@ -361,12 +406,24 @@ func makeNode(ctx *cli.Context, cfg *config, genesisStore *genesisstore.Store) (
 	if cfg.Emitter.Validator.ID != 0 {
 		svc.RegisterEmitter(emitter.NewEmitter(cfg.Emitter, svc.EmitterWorld(signer)))
 	}
+	svc.SetTxPolicyReloader(func() error {
+		return reloadTxPolicy(ctx, txPolicy)
+	})
 	err = engine.Bootstrap(svc.GetConsensusCallbacks())
 	if err != nil {
 		utils.Fatalf("Failed to bootstrap the engine: %v", err)
 	}
 
 	stack.RegisterAPIs(svc.APIs())
+	if ctx.GlobalBool(utils.GraphQLEnabledFlag.Name) {
+		if cfg.Node.HTTPHost == "" {
+			utils.Fatalf("GraphQL requires the HTTP-RPC server to be enabled with --%s", utils.HTTPEnabledFlag.Name)
+		}
+		err = graphql.New(stack, svc.EthAPI, cfg.Opera.FilterAPI, cfg.Node.GraphQLCors, cfg.Node.GraphQLVirtualHosts)
+		if err != nil {
+			utils.Fatalf("Failed to register the GraphQL service: %v", err)
+		}
+	}
 	stack.RegisterProtocols(svc.Protocols())
 	stack.RegisterLifecycle(svc)
 
@@ -377,6 +434,9 @@ func makeNode(ctx *cli.Context, cfg *config, genesisStore *genesisstore.Store) (
 		if closeDBs != nil {
 			_ = closeDBs()
 		}
+		if remoteSigner != nil {
+			remoteSigner.Close()
+		}
 	}
 }
 
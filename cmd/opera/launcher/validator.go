@@ -1,6 +1,9 @@
 package launcher
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/pkg/errors"
 	cli "gopkg.in/urfave/cli.v1"
 
@@ -29,6 +32,24 @@ var validatorPasswordFlag = cli.StringFlag{
 	Value: "",
 }
 
+var validatorSignerFlag = cli.StringFlag{
+	Name:  "validator.signer",
+	Usage: "IPC path or HTTPS URL of a remote signer holding the validator private key, instead of the local keystore",
+	Value: "",
+}
+
+var validatorSignerAuthFlag = cli.StringFlag{
+	Name:  "validator.signer.authfile",
+	Usage: "File with the auth token of the remote signer, required for HTTPS signers",
+	Value: "",
+}
+
+var validatorSignerCAFlag = cli.StringFlag{
+	Name:  "validator.signer.tls.ca",
+	Usage: "PEM file with the CA certificates of the remote signer, the system certificates are used if empty",
+	Value: "",
+}
+
 // setValidatorID retrieves the validator ID either from the directly specified
 // command line flags or from the keystore if CLI indexed.
 func setValidator(ctx *cli.Context, cfg *emitter.Config) error {
@@ -65,3 +86,22 @@ func setValidator(ctx *cli.Context, cfg *emitter.Config) error {
 	}
 	return nil
 }
+
+// setPrivateTxOriginators sets the validators which originate private transactions in turns
+func setPrivateTxOriginators(ctx *cli.Context, cfg *emitter.Config) error {
+	if !ctx.GlobalIsSet(PrivateTxsOriginatorsFlag.Name) {
+		return nil
+	}
+	cfg.PrivateTxOriginators = nil
+	for _, s := range strings.Split(ctx.GlobalString(PrivateTxsOriginatorsFlag.Name), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(s, 10, 32)
+		if err != nil || id == 0 {
+			return errors.Errorf("invalid private txs originator validator ID %s", s)
+		}
+		cfg.PrivateTxOriginators = append(cfg.PrivateTxOriginators, idx.ValidatorID(id))
+	}
+	return nil
+}
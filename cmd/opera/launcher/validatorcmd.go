@@ -3,13 +3,21 @@ package launcher
 import (
 	"crypto/ecdsa"
 	"crypto/rand"
+	"crypto/tls"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
 	"path"
 	"strings"
+	"syscall"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
@@ -18,6 +26,31 @@ import (
 )
 
 var (
+	signerHTTPFlag = cli.StringFlag{
+		Name:  "signer.http",
+		Usage: "Listening address of the HTTPS signer endpoint",
+	}
+	signerIPCFlag = cli.StringFlag{
+		Name:  "signer.ipcpath",
+		Usage: "Path of the signer IPC socket, which is accessible only by its owner",
+	}
+	signerAuthFlag = cli.StringFlag{
+		Name:  "signer.authfile",
+		Usage: "File with the auth token which the node has to present, required for HTTPS endpoint",
+	}
+	signerTLSCertFlag = cli.StringFlag{
+		Name:  "signer.tls.cert",
+		Usage: "PEM file with the TLS certificate of HTTPS endpoint",
+	}
+	signerTLSKeyFlag = cli.StringFlag{
+		Name:  "signer.tls.key",
+		Usage: "PEM file with the TLS private key of HTTPS endpoint",
+	}
+	signerGuardFlag = cli.StringFlag{
+		Name:  "signer.guard",
+		Usage: "File with the last signed events, which protects against double-signing (default: <datadir>/signer/guard.json)",
+	}
+
 	validatorCommand = cli.Command{
 		Name:     "validator",
 		Usage:    "Manage validators",
@@ -79,6 +112,36 @@ password to file or expose in any other way.
     opera validator convert
 
 Converts an account private key to a validator private key and saves in the validator keystore.
+`,
+			},
+			{
+				Name:   "signer",
+				Usage:  "Serve the validator key as a remote signer",
+				Action: utils.MigrateFlags(validatorSigner),
+				Flags: []cli.Flag{
+					DataDirFlag,
+					utils.KeyStoreDirFlag,
+					validatorPubkeyFlag,
+					validatorPasswordFlag,
+					signerHTTPFlag,
+					signerIPCFlag,
+					signerAuthFlag,
+					signerTLSCertFlag,
+					signerTLSKeyFlag,
+					signerGuardFlag,
+				},
+				Description: `
+    opera validator signer --validator.pubkey <pubkey> --signer.ipcpath <path>
+    opera validator signer --validator.pubkey <pubkey> --signer.http <addr> --signer.authfile <file> --signer.tls.cert <file> --signer.tls.key <file>
+
+Unlocks the validator key and signs the events of a node started with --validator.signer,
+so the private key isn't stored on the node's disk.
+
+The HTTPS endpoint serves only the requests with the auth token from --signer.authfile,
+which the node reads from --validator.signer.authfile.
+
+The signer refuses to sign two different events with the same epoch and sequence number.
+The last signed events are persisted in the guard file, don't share the key between several signers.
 `,
 			},
 		},
@@ -158,3 +221,90 @@ func validatorKeyConvert(ctx *cli.Context) error {
 	fmt.Println("\nYour key was converted and saved to " + valkeypath)
 	return nil
 }
+
+// validatorSigner serves the validator key as a remote signer until interrupted.
+func validatorSigner(ctx *cli.Context) error {
+	cfg := makeAllConfigs(ctx)
+	utils.SetNodeConfig(ctx, &cfg.Node)
+
+	httpAddr, ipcPath := ctx.String(signerHTTPFlag.Name), ctx.String(signerIPCFlag.Name)
+	if httpAddr == "" && ipcPath == "" {
+		utils.Fatalf("Either --%s or --%s is required", signerHTTPFlag.Name, signerIPCFlag.Name)
+	}
+	pubkey, err := validatorpk.FromString(ctx.GlobalString(validatorPubkeyFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to decode the validator pubkey: %v", err)
+	}
+	valKeystore := valkeystore.NewDefaultFileKeystore(path.Join(getValKeystoreDir(cfg.Node), "validator"))
+	if err := unlockValidatorKey(ctx, pubkey, valKeystore); err != nil {
+		utils.Fatalf("Failed to unlock validator key: %v", err)
+	}
+
+	guardPath := ctx.String(signerGuardFlag.Name)
+	if guardPath == "" {
+		guardPath = path.Join(cfg.Node.DataDir, "signer", "guard.json")
+	}
+	guard, err := valkeystore.NewSignGuard(guardPath)
+	if err != nil {
+		utils.Fatalf("Failed to open the sign guard: %v", err)
+	}
+	if last, ok := guard.Last(pubkey); ok {
+		log.Info("Loaded the last signed event", "pubkey", pubkey.String(), "epoch", last.Epoch, "seq", last.Seq)
+	}
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("validator", valkeystore.NewRemoteSignerAPI(valKeystore, guard)); err != nil {
+		return err
+	}
+
+	errc := make(chan error, 2)
+	if ipcPath != "" {
+		listener, err := net.Listen("unix", ipcPath)
+		if err != nil {
+			utils.Fatalf("Failed to listen on the IPC socket: %v", err)
+		}
+		defer listener.Close()
+		if err := os.Chmod(ipcPath, 0600); err != nil {
+			utils.Fatalf("Failed to restrict the IPC socket access: %v", err)
+		}
+		log.Info("Signer IPC endpoint opened", "path", ipcPath)
+		go func() {
+			errc <- server.ServeListener(listener)
+		}()
+	}
+	if httpAddr != "" {
+		authFile, certFile, keyFile := ctx.String(signerAuthFlag.Name), ctx.String(signerTLSCertFlag.Name), ctx.String(signerTLSKeyFlag.Name)
+		if authFile == "" || certFile == "" || keyFile == "" {
+			utils.Fatalf("HTTPS endpoint requires --%s, --%s and --%s", signerAuthFlag.Name, signerTLSCertFlag.Name, signerTLSKeyFlag.Name)
+		}
+		token, err := readTokenFile(authFile)
+		if err != nil {
+			utils.Fatalf("Failed to read the auth token: %v", err)
+		}
+		httpServer := &http.Server{
+			Addr:              httpAddr,
+			Handler:           valkeystore.NewRemoteSignerHandler(server, token),
+			ReadHeaderTimeout: valkeystore.RemoteSignerTimeout,
+			TLSConfig: &tls.Config{
+				MinVersion: tls.VersionTLS12,
+			},
+		}
+		defer httpServer.Close()
+		log.Info("Signer HTTPS endpoint opened", "addr", httpAddr)
+		go func() {
+			errc <- httpServer.ListenAndServeTLS(certFile, keyFile)
+		}()
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	select {
+	case <-interrupt:
+		log.Info("Signer is stopped")
+		return nil
+	case err := <-errc:
+		return err
+	}
+}
@@ -2,8 +2,13 @@ package launcher
 
 import (
 	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/url"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
@@ -74,3 +79,89 @@ func unlockValidatorKey(ctx *cli.Context, pubKey validatorpk.PubKey, valKeystore
 	// All trials expended to unlock account, bail out
 	return err
 }
+
+// readTokenFile reads the auth token from the file
+func readTokenFile(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(data))
+	if len(token) == 0 {
+		return "", fmt.Errorf("auth token file %s is empty", path)
+	}
+	return token, nil
+}
+
+// isLoopbackHost returns true if the host of the address is a loopback interface
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// makeRemoteSignerConfig makes the connection config of the remote signer from the CLI flags.
+// HTTP signers require the auth token and TLS, unless the signer is on the loopback interface.
+func makeRemoteSignerConfig(ctx *cli.Context, endpoint string) (valkeystore.RemoteSignerConfig, error) {
+	cfg := valkeystore.RemoteSignerConfig{}
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		// IPC socket is protected by the file permissions
+		return cfg, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return cfg, err
+	}
+	if u.Scheme != "https" && !isLoopbackHost(u.Hostname()) {
+		return cfg, errors.New("remote signer has to be connected over HTTPS")
+	}
+	authFile := ctx.GlobalString(validatorSignerAuthFlag.Name)
+	if authFile == "" {
+		return cfg, fmt.Errorf("auth token of the remote signer is required, set --%s", validatorSignerAuthFlag.Name)
+	}
+	cfg.AuthToken, err = readTokenFile(authFile)
+	if err != nil {
+		return cfg, err
+	}
+	if caFile := ctx.GlobalString(validatorSignerCAFlag.Name); caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return cfg, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return cfg, fmt.Errorf("no certificates in %s", caFile)
+		}
+		cfg.TLS = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return cfg, nil
+}
+
+func makeRemoteSigner(ctx *cli.Context, endpoint string, pubKey validatorpk.PubKey) (*valkeystore.RemoteSigner, error) {
+	cfg, err := makeRemoteSignerConfig(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := valkeystore.NewRemoteSigner(endpoint, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !pubKey.Empty() {
+		has, err := signer.Has(pubKey)
+		if err != nil {
+			signer.Close()
+			return nil, err
+		}
+		if !has {
+			signer.Close()
+			return nil, fmt.Errorf("validator key %s isn't available in the remote signer", pubKey.String())
+		}
+		log.Info("Using remote signer for validator key", "pubkey", pubKey.String(), "endpoint", endpoint)
+	}
+	return signer, nil
+}
@@ -2,10 +2,16 @@ package ethapi
 
 import (
 	"context"
+	"errors"
+	"math/big"
 
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
 )
 
 // PublicAbftAPI provides an API to access consensus related information.
@@ -27,18 +33,23 @@ func (s *PublicAbftAPI) GetValidators(ctx context.Context, epoch rpc.BlockNumber
 	if es == nil {
 		return nil, nil
 	}
+	profiles := es.ValidatorProfiles
+	if epoch == rpc.PendingBlockNumber {
+		profiles = bs.NextValidatorProfiles
+	}
+	return rpcMarshalValidators(es.Validators, profiles), nil
+}
+
+// rpcMarshalValidators converts the validators set to the RPC output.
+func rpcMarshalValidators(validators *pos.Validators, profiles iblockproc.ValidatorProfiles) map[hexutil.Uint64]interface{} {
 	res := map[hexutil.Uint64]interface{}{}
-	for _, vid := range es.Validators.IDs() {
-		profiles := es.ValidatorProfiles
-		if epoch == rpc.PendingBlockNumber {
-			profiles = bs.NextValidatorProfiles
-		}
+	for _, vid := range validators.IDs() {
 		res[hexutil.Uint64(vid)] = map[string]interface{}{
 			"weight": (*hexutil.Big)(profiles[vid].Weight),
 			"pubkey": profiles[vid].PubKey.String(),
 		}
 	}
-	return res, nil
+	return res
 }
 
 // GetDowntime returns validator's downtime.
@@ -73,3 +84,135 @@ func (s *PublicAbftAPI) GetOriginatedEpochFee(ctx context.Context, validatorID h
 	}
 	return (*hexutil.Big)(v), nil
 }
+
+// blocksFee returns the fee paid for the transactions of the blocks, like it's originated by the validators.
+// It returns nil if the blocks are pruned or the receipts of a block are missing.
+func (s *PublicAbftAPI) blocksFee(ctx context.Context, from, to idx.Block) (*big.Int, error) {
+	fee := new(big.Int)
+	for n := from; n <= to; n++ {
+		block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(n))
+		if errors.Is(err, evmstore.ErrPrunedHistory) {
+			return nil, nil
+		}
+		if err != nil || block == nil {
+			return nil, err
+		}
+		receipts, err := s.b.GetReceiptsByNumber(ctx, rpc.BlockNumber(n))
+		if errors.Is(err, evmstore.ErrTxIndexDisabled) || errors.Is(err, evmstore.ErrPrunedHistory) {
+			return nil, nil
+		}
+		if err != nil || len(receipts) != len(block.Transactions) {
+			return nil, err
+		}
+		for i, tx := range block.Transactions {
+			fee.Add(fee, new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), tx.GasPrice()))
+		}
+	}
+	return fee, nil
+}
+
+// GetEpoch returns the timing, fees and validators performance of the epoch.
+// * When epoch is -2 the current epoch is returned, with the metrics collected so far.
+// * When epoch is -1 the latest sealed epoch is returned.
+// The metrics which aren't recorded for the epoch are null.
+// The total fee of a sealed epoch without the metrics (e.g. an epoch received via LLR or genesis)
+// is calculated from the receipts of the epoch blocks. It's null if the blocks are pruned
+// or the transactions index is disabled.
+func (s *PublicAbftAPI) GetEpoch(ctx context.Context, epoch rpc.BlockNumber) (map[string]interface{}, error) {
+	current := s.b.CurrentEpoch(ctx)
+	var n idx.Epoch
+	switch epoch {
+	case rpc.PendingBlockNumber:
+		n = current
+	case rpc.LatestBlockNumber:
+		n = current - 1
+	default:
+		if epoch < 0 {
+			return nil, errors.New("invalid epoch number")
+		}
+		n = idx.Epoch(epoch)
+	}
+	if n == 0 || n > current {
+		return nil, nil
+	}
+
+	startBs, startEs, err := s.b.GetEpochBlockState(ctx, rpc.BlockNumber(n))
+	if err != nil {
+		return nil, err
+	}
+	if startEs == nil {
+		return nil, nil
+	}
+	var endBs *iblockproc.BlockState
+	var endEs *iblockproc.EpochState
+	if n < current {
+		endBs, endEs, err = s.b.GetEpochBlockState(ctx, rpc.BlockNumber(n+1))
+		if err != nil {
+			return nil, err
+		}
+	}
+	metrics, err := s.b.GetEpochMetrics(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+
+	validators := rpcMarshalValidators(startEs.Validators, startEs.ValidatorProfiles)
+	totalWeight := new(big.Int)
+	for _, vid := range startEs.Validators.IDs() {
+		totalWeight.Add(totalWeight, startEs.ValidatorProfiles[vid].Weight)
+	}
+	// originated fee is accumulated through the epochs, so subtract the value at the epoch start
+	originatedFee := func(vid idx.ValidatorID, accumulated *big.Int) *hexutil.Big {
+		fee := new(big.Int).Set(accumulated)
+		fee.Sub(fee, startBs.GetValidatorState(vid, startEs.Validators).Originated)
+		return (*hexutil.Big)(fee)
+	}
+	var totalFee *big.Int
+	if metrics != nil {
+		totalFee = new(big.Int)
+		for _, m := range metrics.Validators {
+			if !startEs.Validators.Exists(m.ID) {
+				continue
+			}
+			v := validators[hexutil.Uint64(m.ID)].(map[string]interface{})
+			v["uptime"] = hexutil.Uint64(m.Uptime)
+			v["offlineBlocks"] = hexutil.Uint64(m.Missed.BlocksNum)
+			v["offlineTime"] = hexutil.Uint64(m.Missed.Period)
+			fee := originatedFee(m.ID, m.Originated)
+			v["originatedFee"] = fee
+			totalFee.Add(totalFee, fee.ToInt())
+		}
+	} else if endEs != nil {
+		for _, vid := range startEs.Validators.IDs() {
+			if !endEs.Validators.Exists(vid) {
+				continue
+			}
+			v := validators[hexutil.Uint64(vid)].(map[string]interface{})
+			v["originatedFee"] = originatedFee(vid, endBs.GetValidatorState(vid, endEs.Validators).Originated)
+		}
+		totalFee, err = s.blocksFee(ctx, startBs.LastBlock.Idx+1, endBs.LastBlock.Idx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := map[string]interface{}{
+		"epoch":       hexutil.Uint64(n),
+		"start":       hexutil.Uint64(startEs.EpochStart),
+		"end":         nil,
+		"firstBlock":  hexutil.Uint64(startBs.LastBlock.Idx + 1),
+		"lastBlock":   nil,
+		"gasUsed":     nil,
+		"totalFee":    (*hexutil.Big)(totalFee),
+		"totalWeight": (*hexutil.Big)(totalWeight),
+		"validators":  validators,
+	}
+	if endEs != nil {
+		res["end"] = hexutil.Uint64(endEs.EpochStart)
+		res["lastBlock"] = hexutil.Uint64(endBs.LastBlock.Idx)
+	}
+	if metrics != nil {
+		res["gasUsed"] = hexutil.Uint64(metrics.EpochGas)
+	}
+	return res, nil
+}
@@ -0,0 +1,118 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/drivertype"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+)
+
+// setTestEpoch sets the state at the epoch start, the validators have the accumulated originated fees
+func (b *testBackend) setTestEpoch(epoch idx.Epoch, lastBlock idx.Block, originated ...int64) {
+	ids := make([]idx.ValidatorID, len(originated))
+	profiles := make(iblockproc.ValidatorProfiles)
+	states := make([]iblockproc.ValidatorBlockState, len(originated))
+	for i := range originated {
+		ids[i] = idx.ValidatorID(i + 1)
+		profiles[ids[i]] = drivertype.Validator{Weight: big.NewInt(1)}
+		states[i].Originated = big.NewInt(originated[i])
+	}
+	b.epochStates[epoch] = testEpochState{
+		bs: &iblockproc.BlockState{
+			LastBlock:       iblockproc.BlockCtx{Idx: lastBlock, Time: inter.Timestamp(lastBlock)},
+			ValidatorStates: states,
+		},
+		es: &iblockproc.EpochState{
+			Epoch:             epoch,
+			EpochStart:        inter.Timestamp(lastBlock),
+			Validators:        pos.EqualWeightValidators(ids, 1),
+			ValidatorProfiles: profiles,
+		},
+	}
+}
+
+func testFeeTx(nonce uint64, gasPrice int64) *types.Transaction {
+	return types.NewTransaction(nonce, common.Address{1}, new(big.Int), 21000, big.NewInt(gasPrice), nil)
+}
+
+func TestGetEpochTotalFee(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	b := newTestBackend()
+	b.epoch = 4
+	// epochs 2 and 3 are sealed, epoch 2 is filled via LLR, so it has no metrics
+	b.setTestEpoch(2, 10, 100, 200)
+	b.setTestEpoch(3, 12, 100+21000*7, 200+21000*3)
+	b.setTestEpoch(4, 14, 100+21000*7, 200+21000*3+21000*5)
+	b.addBlock(11, 21000, testFeeTx(0, 2), testFeeTx(1, 5))
+	b.addBlock(12, 21000, testFeeTx(2, 3))
+	b.addBlock(13, 21000, testFeeTx(3, 5))
+	b.metrics[3] = &iblockproc.EpochMetrics{
+		Epoch:    3,
+		EpochGas: 21000,
+		Validators: []iblockproc.ValidatorEpochMetrics{
+			{ID: 1, Originated: big.NewInt(100 + 21000*7)},
+			{ID: 2, Originated: big.NewInt(200 + 21000*3 + 21000*5)},
+		},
+	}
+	api := NewPublicAbftAPI(b)
+
+	res, err := api.GetEpoch(ctx, 2)
+	require.NoError(err)
+	require.Equal(hexutil.Uint64(11), res["firstBlock"])
+	require.Equal(hexutil.Uint64(12), res["lastBlock"])
+	require.Equal(big.NewInt(21000*10), res["totalFee"].(*hexutil.Big).ToInt())
+	require.Nil(res["gasUsed"])
+	validators := res["validators"].(map[hexutil.Uint64]interface{})
+	require.Equal(big.NewInt(21000*7), validators[1].(map[string]interface{})["originatedFee"].(*hexutil.Big).ToInt())
+	require.Equal(big.NewInt(21000*3), validators[2].(map[string]interface{})["originatedFee"].(*hexutil.Big).ToInt())
+
+	// the fee of epoch with metrics is calculated from them
+	res, err = api.GetEpoch(ctx, rpc.LatestBlockNumber)
+	require.NoError(err)
+	require.Equal(hexutil.Uint64(3), res["epoch"])
+	require.Equal(big.NewInt(21000*5), res["totalFee"].(*hexutil.Big).ToInt())
+	require.Equal(hexutil.Uint64(21000), res["gasUsed"])
+
+	// the fee is unknown if the receipts aren't available
+	for _, err := range []error{
+		fmt.Errorf("%w (enable TxIndex)", evmstore.ErrTxIndexDisabled),
+		fmt.Errorf("%w: the earliest available block is 12", evmstore.ErrPrunedHistory),
+	} {
+		b.receiptsErr = err
+		res, err = api.GetEpoch(ctx, 2)
+		require.NoError(err)
+		require.NotNil(res)
+		require.Nil(res["totalFee"])
+	}
+	// other errors aren't hidden
+	b.receiptsErr = errors.New("test error")
+	_, err = api.GetEpoch(ctx, 2)
+	require.EqualError(err, "test error")
+	b.receiptsErr = nil
+
+	// the fee is unknown if the blocks are pruned
+	delete(b.blocks, 11)
+	res, err = api.GetEpoch(ctx, 2)
+	require.NoError(err)
+	require.Nil(res["totalFee"])
+
+	res, err = api.GetEpoch(ctx, 5)
+	require.NoError(err)
+	require.Nil(res)
+}
@@ -0,0 +1,142 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+)
+
+const (
+	defaultAddressTxsLimit = 100
+	maxAddressTxsLimit     = 1000
+)
+
+// AddressTxsArgs represents the arguments of a transactions history request.
+type AddressTxsArgs struct {
+	FromBlock *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`
+	After     *AddressTxCursor `json:"after"`
+	Limit     *hexutil.Uint64  `json:"limit"`
+}
+
+// AddressTxCursor is a position of a transaction, used for pagination.
+type AddressTxCursor struct {
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+}
+
+// AddressTxsResult is a page of transactions history.
+// Next is the cursor to pass as After to get the next page, or nil if there are no more transactions.
+type AddressTxsResult struct {
+	Transactions []*RPCTransaction `json:"transactions"`
+	Next         *AddressTxCursor  `json:"next"`
+}
+
+// invalidParamsError is returned for invalid arguments of a request
+type invalidParamsError struct{ message string }
+
+func (e *invalidParamsError) Error() string { return e.message }
+
+// ErrorCode returns the JSON-RPC error code of invalid params
+func (e *invalidParamsError) ErrorCode() int { return -32602 }
+
+// PublicAddressTxsAPI provides an API to access transactions history of accounts.
+type PublicAddressTxsAPI struct {
+	b Backend
+}
+
+// NewPublicAddressTxsAPI creates a new transactions history API.
+func NewPublicAddressTxsAPI(b Backend) *PublicAddressTxsAPI {
+	return &PublicAddressTxsAPI{b}
+}
+
+// GetTransactionsByAddress returns a page of transactions sent from or to the address in the block range,
+// in the chain order.
+func (s *PublicAddressTxsAPI) GetTransactionsByAddress(ctx context.Context, addr common.Address, args AddressTxsArgs) (*AddressTxsResult, error) {
+	fromBlock := rpc.EarliestBlockNumber
+	if args.FromBlock != nil {
+		fromBlock = *args.FromBlock
+	}
+	from, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(fromBlock))
+	if err != nil {
+		return nil, err
+	}
+	toBlock := rpc.LatestBlockNumber
+	if args.ToBlock != nil {
+		toBlock = *args.ToBlock
+	}
+	to, err := s.b.ResolveRpcBlockNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(toBlock))
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, errors.New("invalid block range")
+	}
+
+	limit := defaultAddressTxsLimit
+	if args.Limit != nil {
+		if *args.Limit == 0 {
+			return nil, &invalidParamsError{"limit must be positive"}
+		}
+		limit = maxAddressTxsLimit
+		if *args.Limit < maxAddressTxsLimit {
+			limit = int(*args.Limit)
+		}
+	}
+
+	start := evmstore.AddressTx{
+		Block: from,
+	}
+	if args.After != nil {
+		after := evmstore.AddressTx{
+			Block:       idx.Block(args.After.BlockNumber),
+			BlockOffset: uint32(args.After.TransactionIndex) + 1,
+		}
+		if after.Block > start.Block || (after.Block == start.Block && after.BlockOffset > start.BlockOffset) {
+			start = after
+		}
+	}
+
+	// request one more position to find out whether there's a next page
+	positions, err := s.b.GetAddressTxs(ctx, addr, start, to, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	res := &AddressTxsResult{
+		Transactions: make([]*RPCTransaction, 0, len(positions)),
+	}
+	if len(positions) > limit {
+		positions = positions[:limit]
+		last := positions[len(positions)-1]
+		res.Next = &AddressTxCursor{
+			BlockNumber:      hexutil.Uint64(last.Block),
+			TransactionIndex: hexutil.Uint64(last.BlockOffset),
+		}
+	}
+
+	var block *evmcore.EvmBlock
+	for _, pos := range positions {
+		if block == nil || idx.Block(block.NumberU64()) != pos.Block {
+			block, err = s.b.BlockByNumber(ctx, rpc.BlockNumber(pos.Block))
+			if err != nil {
+				return nil, err
+			}
+			if block == nil {
+				return nil, errors.New("block not found")
+			}
+		}
+		tx := newRPCTransactionFromBlockIndex(block, uint64(pos.BlockOffset))
+		if tx == nil {
+			return nil, errors.New("transaction not found")
+		}
+		res.Transactions = append(res.Transactions, tx)
+	}
+	return res, nil
+}
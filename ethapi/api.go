@@ -228,12 +228,166 @@ func (s *PublicTxPoolAPI) ContentFrom(addr common.Address) map[string]map[string
 }
 
 // Status returns the number of pending and queued transaction in the pool.
-func (s *PublicTxPoolAPI) Status() map[string]hexutil.Uint {
-	pending, queue := s.b.Stats()
-	return map[string]hexutil.Uint{
-		"pending": hexutil.Uint(pending),
-		"queued":  hexutil.Uint(queue),
+// If txHash is specified, then it returns the inclusion diagnostics of the pool transaction instead.
+func (s *PublicTxPoolAPI) Status(ctx context.Context, txHash *common.Hash) (interface{}, error) {
+	if txHash == nil {
+		pending, queue := s.b.Stats()
+		return map[string]hexutil.Uint{
+			"pending": hexutil.Uint(pending),
+			"queued":  hexutil.Uint(queue),
+		}, nil
+	}
+	return s.txStatus(ctx, *txHash)
+}
+
+// TxEmitterStatus tells whether a local emitter would originate a pool transaction
+type TxEmitterStatus struct {
+	Validator hexutil.Uint64  `json:"validator"`
+	Originate bool            `json:"originate"`
+	Reason    string          `json:"reason,omitempty"`
+	Turn      *hexutil.Uint64 `json:"turn,omitempty"`
+	NextTurn  *hexutil.Uint64 `json:"nextTurn,omitempty"`
+}
+
+// TxOrigination describes whether a local emitter would originate a transaction
+type TxOrigination struct {
+	Validator idx.ValidatorID
+	// Originate is true if the transaction would be originated into the next event
+	Originate bool
+	// Reason explains why the transaction isn't originated
+	Reason string
+	// Turn is the validator whose turn it is to originate the transaction, if known
+	Turn idx.ValidatorID
+	// NextTurn is the estimated start of the next emitter's turn, if the current turn isn't the emitter's
+	NextTurn *time.Time
+}
+
+// TxPoolTxStatus is the inclusion diagnostics of a pool transaction
+type TxPoolTxStatus struct {
+	Hash   common.Hash    `json:"hash"`
+	From   common.Address `json:"from"`
+	Nonce  hexutil.Uint64 `json:"nonce"`
+	Status string         `json:"status"`
+	Local  bool           `json:"local"`
+
+	StateNonce    hexutil.Uint64   `json:"stateNonce"`
+	NoncePosition hexutil.Uint     `json:"noncePosition"`
+	NonceGap      hexutil.Uint64   `json:"nonceGap"`
+	SenderPending []hexutil.Uint64 `json:"senderPending"`
+	SenderQueued  []hexutil.Uint64 `json:"senderQueued"`
+	AccountSlots  hexutil.Uint64   `json:"accountSlots"`
+	AccountQueue  hexutil.Uint64   `json:"accountQueue"`
+
+	GasPrice             *hexutil.Big `json:"gasPrice"`
+	GasFeeCap            *hexutil.Big `json:"maxFeePerGas"`
+	GasTipCap            *hexutil.Big `json:"maxPriorityFeePerGas"`
+	MinGasPrice          *hexutil.Big `json:"minGasPrice"`
+	EffectiveMinGasPrice *hexutil.Big `json:"effectiveMinGasPrice"`
+	SuggestedGasPrice    *hexutil.Big `json:"suggestedGasPrice"`
+	PoolMinTip           *hexutil.Big `json:"poolMinTip"`
+
+	ReplacementGasFeeCap *hexutil.Big `json:"replacementMaxFeePerGas"`
+	ReplacementGasTipCap *hexutil.Big `json:"replacementMaxPriorityFeePerGas"`
+
+	Emitters []TxEmitterStatus `json:"emitters"`
+	Problems []string          `json:"problems"`
+}
+
+func toUint64s(nonces []uint64) []hexutil.Uint64 {
+	res := make([]hexutil.Uint64, len(nonces))
+	for i, n := range nonces {
+		res[i] = hexutil.Uint64(n)
+	}
+	return res
+}
+
+func (s *PublicTxPoolAPI) txStatus(ctx context.Context, txHash common.Hash) (*TxPoolTxStatus, error) {
+	info := s.b.TxPoolTxInfo(txHash)
+	if info == nil {
+		return nil, errors.New("transaction isn't in the pool")
+	}
+	tx := info.Tx
+	res := &TxPoolTxStatus{
+		Hash:                 txHash,
+		From:                 info.From,
+		Nonce:                hexutil.Uint64(tx.Nonce()),
+		Status:               "pending",
+		Local:                info.Local,
+		StateNonce:           hexutil.Uint64(info.StateNonce),
+		NonceGap:             hexutil.Uint64(info.NonceGap),
+		SenderPending:        toUint64s(info.Pending),
+		SenderQueued:         toUint64s(info.Queued),
+		AccountSlots:         hexutil.Uint64(info.AccountSlots),
+		AccountQueue:         hexutil.Uint64(info.AccountQueue),
+		GasFeeCap:            (*hexutil.Big)(tx.GasFeeCap()),
+		GasTipCap:            (*hexutil.Big)(tx.GasTipCap()),
+		PoolMinTip:           (*hexutil.Big)(info.MinTip),
+		ReplacementGasFeeCap: (*hexutil.Big)(info.ReplacementFeeCap),
+		ReplacementGasTipCap: (*hexutil.Big)(info.ReplacementTipCap),
+		Emitters:             []TxEmitterStatus{},
+		Problems:             []string{},
+	}
+	if info.Status == evmcore.TxStatusQueued {
+		res.Status = "queued"
+	}
+	for _, nonce := range append(info.Pending, info.Queued...) {
+		if nonce < tx.Nonce() {
+			res.NoncePosition++
+		}
 	}
+
+	// compare with the current prices
+	minGasPrice := s.b.MinGasPrice()
+	effectiveMinGasPrice := s.b.EffectiveMinGasPrice(ctx)
+	suggested := s.b.SuggestGasTipCap(ctx, gasprice.AsDefaultCertainty)
+	suggested.Add(suggested, minGasPrice)
+	gasPrice := math.BigMin(new(big.Int).Add(tx.GasTipCap(), minGasPrice), tx.GasFeeCap())
+	res.GasPrice = (*hexutil.Big)(gasPrice)
+	res.MinGasPrice = (*hexutil.Big)(minGasPrice)
+	res.EffectiveMinGasPrice = (*hexutil.Big)(effectiveMinGasPrice)
+	res.SuggestedGasPrice = (*hexutil.Big)(suggested)
+	if tx.GasFeeCap().Cmp(minGasPrice) < 0 {
+		res.Problems = append(res.Problems, "max fee per gas is below the min gas price")
+	} else if gasPrice.Cmp(effectiveMinGasPrice) < 0 {
+		res.Problems = append(res.Problems, "gas price is below the effective min gas price")
+	} else if gasPrice.Cmp(suggested) < 0 {
+		res.Problems = append(res.Problems, "gas price is below the suggested gas price, inclusion may be delayed")
+	}
+
+	// check nonces
+	if info.Status == evmcore.TxStatusQueued {
+		if info.NonceGap != 0 {
+			res.Problems = append(res.Problems, fmt.Sprintf("%d nonces are missing before the transaction", info.NonceGap))
+		} else if uint64(len(info.Pending)) >= info.AccountSlots {
+			res.Problems = append(res.Problems, "sender has more pending transactions than account slots")
+		} else {
+			res.Problems = append(res.Problems, "transaction isn't executable, e.g. sender balance is insufficient")
+		}
+	}
+
+	// check the local emitters
+	originated := false
+	for _, o := range s.b.TxOrigination(tx) {
+		es := TxEmitterStatus{
+			Validator: hexutil.Uint64(o.Validator),
+			Originate: o.Originate,
+			Reason:    o.Reason,
+		}
+		if o.Turn != 0 {
+			turn := hexutil.Uint64(o.Turn)
+			es.Turn = &turn
+		}
+		if o.NextTurn != nil {
+			nextTurn := hexutil.Uint64(o.NextTurn.Unix())
+			es.NextTurn = &nextTurn
+		}
+		originated = originated || o.Originate
+		res.Emitters = append(res.Emitters, es)
+	}
+	if info.Status == evmcore.TxStatusPending && len(res.Emitters) != 0 && !originated {
+		res.Problems = append(res.Problems, "local emitter wouldn't originate the transaction now")
+	}
+	return res, nil
 }
 
 // Inspect retrieves the content of the transaction pool and flattens it into an
@@ -1683,6 +1837,43 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	}
 	receipt := receipts[index]
 
+	bigblock := new(big.Int).SetUint64(blockNumber)
+	signer := gsignercache.Wrap(types.MakeSigner(s.b.ChainConfig(), bigblock))
+	return marshalReceipt(receipt, tx, header, index, signer), nil
+}
+
+// GetBlockReceipts returns the receipts of all the transactions of the given block.
+func (s *PublicTransactionPoolAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	number, err := s.b.ResolveRpcBlockNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	block, err := s.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := s.b.GetReceiptsByNumber(ctx, rpc.BlockNumber(number))
+	if err != nil {
+		return nil, err
+	}
+	// the receipts may be missing or out of sync with the block transactions
+	if receipts.Len() != len(block.Transactions) {
+		return nil, fmt.Errorf("receipts length mismatch: %d vs %d", receipts.Len(), len(block.Transactions))
+	}
+
+	header := block.Header()
+	signer := gsignercache.Wrap(types.MakeSigner(s.b.ChainConfig(), block.Number))
+	result := make([]map[string]interface{}, len(receipts))
+	for i, receipt := range receipts {
+		result[i] = marshalReceipt(receipt, block.Transactions[i], header, uint64(i), signer)
+	}
+	return result, nil
+}
+
+// marshalReceipt converts the receipt into the RPC representation.
+func marshalReceipt(receipt *types.Receipt, tx *types.Transaction, header *evmcore.EvmHeader, index uint64, signer types.Signer) map[string]interface{} {
+	hash := tx.Hash()
+	blockNumber := header.Number.Uint64()
 	for _, l := range receipt.Logs {
 		l.TxHash = hash
 		l.BlockHash = header.Hash
@@ -1690,8 +1881,6 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	}
 
 	// Derive the sender.
-	bigblock := new(big.Int).SetUint64(blockNumber)
-	signer := gsignercache.Wrap(types.MakeSigner(s.b.ChainConfig(), bigblock))
 	from, _ := internaltx.Sender(signer, tx)
 
 	fields := map[string]interface{}{
@@ -1728,7 +1917,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if tx.To() == nil {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
-	return fields, nil
+	return fields
 }
 
 // sign is a helper function that signs a transaction with the private key of the given address.
@@ -1832,6 +2021,45 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(ctx context.Context, encod
 	return SubmitTransaction(ctx, s.b, tx)
 }
 
+// PrivateTransactionArgs represents the arguments of eth_sendPrivateTransaction.
+type PrivateTransactionArgs struct {
+	// Tx is the signed raw transaction
+	Tx hexutil.Bytes `json:"tx"`
+	// MaxBlockNumber is the last block the transaction may be included into,
+	// the node default lifetime is used if it's absent
+	MaxBlockNumber *hexutil.Uint64 `json:"maxBlockNumber"`
+}
+
+// SendPrivateTransaction will add the signed transaction to the transaction pool without
+// announcing it to peers, which protects it from front-running. The transaction is originated
+// only by the local validator or by the validators which the node is configured to forward
+// private transactions to. It's dropped from the pool once MaxBlockNumber is reached.
+func (s *PublicTransactionPoolAPI) SendPrivateTransaction(ctx context.Context, args PrivateTransactionArgs) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(args.Tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := checkTxFee(tx.GasPrice(), tx.Gas(), s.b.RPCTxFeeCap()); err != nil {
+		return common.Hash{}, err
+	}
+	if !s.b.UnprotectedAllowed() && !tx.Protected() {
+		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
+		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+	}
+	var expiry idx.Block
+	if args.MaxBlockNumber != nil {
+		if *args.MaxBlockNumber == 0 {
+			return common.Hash{}, errors.New("maxBlockNumber has to be positive")
+		}
+		expiry = idx.Block(*args.MaxBlockNumber)
+	}
+	if err := s.b.SendPrivateTx(ctx, tx, expiry); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted private transaction", "hash", tx.Hash().Hex(), "nonce", tx.Nonce())
+	return tx.Hash(), nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccack256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
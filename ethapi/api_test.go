@@ -0,0 +1,65 @@
+package ethapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBlockReceipts(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	b := newTestTracingBackend()
+	txs := types.Transactions{
+		signTestTx(t, b, 0, storeAddr, common.LeftPadBytes([]byte{1}, 32)),
+		signTestTx(t, b, 1, revertAddr, nil),
+	}
+	block, err := b.processBlock(1, txs...)
+	require.NoError(err)
+	_, err = b.processBlock(2)
+	require.NoError(err)
+	api := NewPublicTransactionPoolAPI(b, new(AddrLocker))
+
+	receipts, err := api.GetBlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(1))
+	require.NoError(err)
+	require.Len(receipts, 2)
+	for i, receipt := range receipts {
+		// every receipt matches the one of the single transaction
+		single, err := api.GetTransactionReceipt(ctx, txs[i].Hash())
+		require.NoError(err)
+		require.Equal(single, receipt)
+
+		require.Equal(block.Hash, receipt["blockHash"])
+		require.Equal(hexutil.Uint64(1), receipt["blockNumber"])
+		require.Equal(txs[i].Hash(), receipt["transactionHash"])
+		require.Equal(hexutil.Uint64(i), receipt["transactionIndex"])
+		require.Equal(testAddr, receipt["from"])
+		require.Equal(hexutil.Uint64(b.receipts[1][i].GasUsed), receipt["gasUsed"])
+		// base fee plus the tip
+		require.Equal(hexutil.Uint64(2), receipt["effectiveGasPrice"])
+	}
+	require.Equal(hexutil.Uint(types.ReceiptStatusSuccessful), receipts[0]["status"])
+	require.Equal(hexutil.Uint(types.ReceiptStatusFailed), receipts[1]["status"])
+	require.Equal(receipts[0]["gasUsed"].(hexutil.Uint64)+receipts[1]["gasUsed"].(hexutil.Uint64), receipts[1]["cumulativeGasUsed"])
+
+	byHash, err := api.GetBlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash, false))
+	require.NoError(err)
+	require.Equal(receipts, byHash)
+
+	// an empty block has an empty list of receipts
+	empty, err := api.GetBlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(2))
+	require.NoError(err)
+	require.NotNil(empty)
+	require.Empty(empty)
+
+	_, err = api.GetBlockReceipts(ctx, rpc.BlockNumberOrHashWithNumber(3))
+	require.EqualError(err, "block not found")
+	_, err = api.GetBlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(common.Hash{0xff}, false))
+	require.EqualError(err, "block not found")
+}
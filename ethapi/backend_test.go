@@ -0,0 +1,218 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+type testEpochState struct {
+	bs *iblockproc.BlockState
+	es *iblockproc.EpochState
+}
+
+// testBackend implements the Backend methods used by the tests, the other methods panic
+type testBackend struct {
+	Backend
+
+	epoch       idx.Epoch
+	epochStates map[idx.Epoch]testEpochState
+	metrics     map[idx.Epoch]*iblockproc.EpochMetrics
+	blocks      map[idx.Block]*evmcore.EvmBlock
+	receipts    map[idx.Block]types.Receipts
+	receiptsErr error
+
+	chainConfig *params.ChainConfig
+	stateDB     state.Database
+	roots       map[idx.Block]common.Hash
+}
+
+func newTestBackend() *testBackend {
+	return &testBackend{
+		epochStates: make(map[idx.Epoch]testEpochState),
+		metrics:     make(map[idx.Epoch]*iblockproc.EpochMetrics),
+		blocks:      make(map[idx.Block]*evmcore.EvmBlock),
+		receipts:    make(map[idx.Block]types.Receipts),
+		stateDB:     state.NewDatabase(rawdb.NewMemoryDatabase()),
+		roots:       make(map[idx.Block]common.Hash),
+	}
+}
+
+// newTestStateBackend creates a backend with EVM state, the genesis block state is initialized by the genesis func
+func newTestStateBackend(genesis func(statedb *state.StateDB)) *testBackend {
+	b := newTestBackend()
+	rules := opera.FakeNetRules()
+	b.chainConfig = rules.EvmChainConfig([]opera.UpgradeHeight{{Upgrades: rules.Upgrades}})
+	statedb, _ := state.New(common.Hash{}, b.stateDB, nil)
+	genesis(statedb)
+	b.addBlock(0, 0)
+	b.roots[0], _ = statedb.Commit(true)
+	return b
+}
+
+// processBlock applies the transactions on top of the previous block's state, like the blocks processing does.
+// The transactions skipped by the processor aren't included into the block.
+func (b *testBackend) processBlock(n idx.Block, txs ...*types.Transaction) (*evmcore.EvmBlock, error) {
+	statedb, err := state.New(b.roots[n-1], b.stateDB, nil)
+	if err != nil {
+		return nil, err
+	}
+	header := &evmcore.EvmHeader{
+		Number:     big.NewInt(int64(n)),
+		Hash:       common.Hash{byte(n)},
+		ParentHash: b.blocks[n-1].Hash,
+		Time:       inter.Timestamp(n) * inter.Timestamp(1e9),
+		GasLimit:   1e9,
+		BaseFee:    big.NewInt(1),
+	}
+	usedGas := uint64(0)
+	receipts, _, skipped, err := evmcore.NewStateProcessor(b.chainConfig, &chainReader{context.Background(), b}).
+		Process(evmcore.NewEvmBlock(header, txs), statedb, opera.DefaultVMConfig, &usedGas, func(*types.Log, *state.StateDB) {})
+	if err != nil {
+		return nil, err
+	}
+	header.GasUsed = usedGas
+	header.Root, err = statedb.Commit(true)
+	if err != nil {
+		return nil, err
+	}
+	block := evmcore.NewEvmBlock(header, inter.FilterSkippedTxs(txs, skipped))
+	b.blocks[n] = block
+	b.receipts[n] = receipts
+	b.roots[n] = header.Root
+	return block, nil
+}
+
+// addBlock adds a block with the transactions, each of them uses the gas
+func (b *testBackend) addBlock(n idx.Block, gasUsed uint64, txs ...*types.Transaction) *evmcore.EvmBlock {
+	block := &evmcore.EvmBlock{
+		EvmHeader: evmcore.EvmHeader{
+			Number:  big.NewInt(int64(n)),
+			BaseFee: new(big.Int),
+		},
+		Transactions: txs,
+	}
+	receipts := make(types.Receipts, len(txs))
+	for i, tx := range txs {
+		receipts[i] = &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			TxHash:            tx.Hash(),
+			GasUsed:           gasUsed,
+			CumulativeGasUsed: gasUsed * uint64(i+1),
+			BlockNumber:       big.NewInt(int64(n)),
+			TransactionIndex:  uint(i),
+		}
+	}
+	b.blocks[n] = block
+	b.receipts[n] = receipts
+	return block
+}
+
+func (b *testBackend) CurrentEpoch(ctx context.Context) idx.Epoch {
+	return b.epoch
+}
+
+func (b *testBackend) GetEpochBlockState(ctx context.Context, epoch rpc.BlockNumber) (*iblockproc.BlockState, *iblockproc.EpochState, error) {
+	s := b.epochStates[idx.Epoch(epoch)]
+	return s.bs, s.es, nil
+}
+
+func (b *testBackend) GetEpochMetrics(ctx context.Context, epoch idx.Epoch) (*iblockproc.EpochMetrics, error) {
+	return b.metrics[epoch], nil
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	return b.blocks[idx.Block(number)], nil
+}
+
+func (b *testBackend) GetReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (types.Receipts, error) {
+	if b.receiptsErr != nil {
+		return nil, b.receiptsErr
+	}
+	return b.receipts[idx.Block(number)], nil
+}
+
+func (b *testBackend) BlockByHash(ctx context.Context, hash common.Hash) (*evmcore.EvmBlock, error) {
+	for _, block := range b.blocks {
+		if block.Hash == hash {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error) {
+	block, _ := b.BlockByNumber(ctx, number)
+	return block.Header(), nil
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return b.chainConfig
+}
+
+func (b *testBackend) RPCGasCap() uint64 {
+	return 1e8
+}
+
+func (b *testBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *evmcore.EvmHeader, error) {
+	var block *evmcore.EvmBlock
+	if number, ok := blockNrOrHash.Number(); ok {
+		block, _ = b.BlockByNumber(ctx, number)
+	} else if hash, ok := blockNrOrHash.Hash(); ok {
+		block, _ = b.BlockByHash(ctx, hash)
+	}
+	if block == nil {
+		return nil, nil, nil
+	}
+	statedb, err := state.New(b.roots[idx.Block(block.NumberU64())], b.stateDB, nil)
+	return statedb, block.Header(), err
+}
+
+func (b *testBackend) GetEVM(ctx context.Context, msg evmcore.Message, state *state.StateDB, header *evmcore.EvmHeader, vmConfig *vm.Config) (*vm.EVM, func() error, error) {
+	if vmConfig == nil {
+		vmConfig = &opera.DefaultVMConfig
+	}
+	txContext := evmcore.NewEVMTxContext(msg)
+	context := evmcore.NewEVMBlockContext(header, &chainReader{ctx, b}, nil)
+	return vm.NewEVM(context, txContext, state, b.chainConfig, *vmConfig), func() error { return nil }, nil
+}
+
+func (b *testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
+	for n, block := range b.blocks {
+		for i, tx := range block.Transactions {
+			if tx.Hash() == txHash {
+				return tx, uint64(n), uint64(i), nil
+			}
+		}
+	}
+	return nil, 0, 0, nil
+}
+
+func (b *testBackend) ResolveRpcBlockNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (idx.Block, error) {
+	if number, ok := blockNrOrHash.Number(); ok {
+		if _, ok := b.blocks[idx.Block(number)]; !ok {
+			return 0, errors.New("block not found")
+		}
+		return idx.Block(number), nil
+	}
+	hash, _ := blockNrOrHash.Hash()
+	block, _ := b.BlockByHash(ctx, hash)
+	if block == nil {
+		return 0, errors.New("block not found")
+	}
+	return idx.Block(block.NumberU64()), nil
+}
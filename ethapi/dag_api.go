@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -13,6 +14,13 @@ import (
 	"github.com/Fantom-foundation/go-opera/inter"
 )
 
+// Sizes of the queues of a subscriber. Subscribers which fall behind by more items are dropped,
+// so slow subscribers don't delay the events processing and epoch sealing.
+const (
+	newEventsBuffer = 1024
+	newEpochsBuffer = 16
+)
+
 // PublicDAGChainAPI provides an API to access the directed acyclic graph chain.
 // It offers only methods that operate on public data that is freely available to anyone.
 type PublicDAGChainAPI struct {
@@ -61,6 +69,135 @@ func (s *PublicDAGChainAPI) GetHeads(ctx context.Context, epoch rpc.BlockNumber)
 	return inter.EventIDsToHex(res), nil
 }
 
+// NewEvents creates a subscription that is triggered each time an event is connected to the DAG.
+func (s *PublicDAGChainAPI) NewEvents(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	events := make(chan *inter.EventPayload, newEventsBuffer)
+	eventsSub := s.b.SubscribeNewEventsNotify(events)
+	queue, stop := make(chan interface{}, newEventsBuffer), make(chan struct{})
+	go func() {
+		defer eventsSub.Unsubscribe()
+		for {
+			select {
+			case e := <-events:
+				if !enqueueNotification(queue, e) {
+					log.Warn("Dropping slow events subscriber", "id", rpcSub.ID)
+					return
+				}
+			case <-eventsSub.Err():
+				return
+			case <-stop:
+				return
+			}
+		}
+	}()
+	go notifyQueued(notifier, rpcSub, queue, stop, func(item interface{}) (interface{}, error) {
+		return inter.RPCMarshalEventPayload(item.(*inter.EventPayload), true, false)
+	})
+
+	return rpcSub, nil
+}
+
+// NewEpochs creates a subscription that is triggered each time an epoch is sealed.
+// The notification contains the validators and timing of the sealed epoch.
+func (s *PublicDAGChainAPI) NewEpochs(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	epochs := make(chan idx.Epoch, newEpochsBuffer)
+	epochsSub := s.b.SubscribeNewEpochsNotify(epochs)
+	queue, stop := make(chan interface{}, newEpochsBuffer), make(chan struct{})
+	go func() {
+		defer epochsSub.Unsubscribe()
+		for {
+			select {
+			case newEpoch := <-epochs:
+				if !enqueueNotification(queue, newEpoch) {
+					log.Warn("Dropping slow epochs subscriber", "id", rpcSub.ID)
+					return
+				}
+			case <-epochsSub.Err():
+				return
+			case <-stop:
+				return
+			}
+		}
+	}()
+	go notifyQueued(notifier, rpcSub, queue, stop, func(item interface{}) (interface{}, error) {
+		return s.sealedEpoch(context.Background(), item.(idx.Epoch))
+	})
+
+	return rpcSub, nil
+}
+
+// enqueueNotification adds the item to the subscriber queue without blocking.
+// Returns false if the queue is full, then the queue is closed and the subscriber is dropped.
+func enqueueNotification(queue chan interface{}, item interface{}) bool {
+	select {
+	case queue <- item:
+		return true
+	default:
+		close(queue)
+		return false
+	}
+}
+
+// notifyQueued sends the queued items to the subscriber until the queue is closed or the subscription ends.
+// The feed is read by another goroutine into the queue, because the feed senders may hold
+// the consensus engine lock, which must never wait for a slow subscriber.
+func notifyQueued(notifier *rpc.Notifier, rpcSub *rpc.Subscription, queue chan interface{}, stop chan struct{}, marshal func(interface{}) (interface{}, error)) {
+	defer close(stop)
+	for {
+		select {
+		case item, ok := <-queue:
+			if !ok {
+				return
+			}
+			fields, err := marshal(item)
+			if err != nil {
+				log.Warn("Failed to marshal notification", "id", rpcSub.ID, "err", err)
+				continue
+			}
+			_ = notifier.Notify(rpcSub.ID, fields)
+		case <-rpcSub.Err():
+			return
+		case <-notifier.Closed():
+			return
+		}
+	}
+}
+
+// sealedEpoch returns the RPC representation of the epoch sealed before newEpoch.
+func (s *PublicDAGChainAPI) sealedEpoch(ctx context.Context, newEpoch idx.Epoch) (map[string]interface{}, error) {
+	_, es, err := s.b.GetEpochBlockState(ctx, rpc.BlockNumber(newEpoch))
+	if err != nil {
+		return nil, err
+	}
+	_, sealedEs, err := s.b.GetEpochBlockState(ctx, rpc.BlockNumber(newEpoch-1))
+	if err != nil {
+		return nil, err
+	}
+	if es == nil || sealedEs == nil {
+		return nil, errors.New("epoch state not found")
+	}
+	return map[string]interface{}{
+		"epoch":      hexutil.Uint64(sealedEs.Epoch),
+		"start":      hexutil.Uint64(es.PrevEpochStart),
+		"end":        hexutil.Uint64(es.EpochStart),
+		"validators": rpcMarshalValidators(sealedEs.Validators, sealedEs.ValidatorProfiles),
+	}, nil
+}
+
 // GetEpochStats returns epoch statistics.
 // * When epoch is -2 the statistics for latest epoch is returned.
 // * When epoch is -1 the statistics for latest sealed epoch is returned.
@@ -0,0 +1,201 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+const (
+	// maxBundleCalls is the max number of calls in a single simulated bundle.
+	maxBundleCalls = 256
+	// bundleTimeout is the time limit for a whole bundle execution.
+	bundleTimeout = 10 * time.Second
+)
+
+// BlockOverrides is a set of header fields to override the block context
+// the calls are executed in.
+type BlockOverrides struct {
+	Number   *hexutil.Big    `json:"number"`
+	Time     *hexutil.Uint64 `json:"time"`
+	BaseFee  *hexutil.Big    `json:"baseFee"`
+	GasLimit *hexutil.Uint64 `json:"gasLimit"`
+	Coinbase *common.Address `json:"coinbase"`
+}
+
+// Apply returns a copy of the header with the overridden fields.
+func (o *BlockOverrides) Apply(header *evmcore.EvmHeader) *evmcore.EvmHeader {
+	h := *header
+	if o == nil {
+		return &h
+	}
+	if o.Number != nil {
+		h.Number = new(big.Int).Set(o.Number.ToInt())
+	}
+	if o.Time != nil {
+		// time is in seconds, as in the block RPC representation
+		h.Time = inter.FromUnix(int64(*o.Time))
+	}
+	if o.BaseFee != nil {
+		h.BaseFee = new(big.Int).Set(o.BaseFee.ToInt())
+	}
+	if o.GasLimit != nil {
+		h.GasLimit = uint64(*o.GasLimit)
+	}
+	if o.Coinbase != nil {
+		h.Coinbase = *o.Coinbase
+	}
+	return &h
+}
+
+// BundleCallResult is the result of a single call of a simulated bundle.
+type BundleCallResult struct {
+	ReturnData hexutil.Bytes  `json:"returnData"`
+	Logs       []*types.Log   `json:"logs"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Error      string         `json:"error,omitempty"`
+	Revert     hexutil.Bytes  `json:"revert,omitempty"`
+}
+
+// BundleResult is the result of a simulated bundle of calls.
+type BundleResult struct {
+	BlockNumber hexutil.Uint64      `json:"blockNumber"`
+	Results     []*BundleCallResult `json:"results"`
+	GasUsed     hexutil.Uint64      `json:"gasUsed"`
+}
+
+// CallBundle executes the given ordered calls on top of the state of the given block,
+// carrying the state changes forward from a call to the next one.
+//
+// Additionally, the caller can override the accounts state and the block context fields.
+//
+// Note, this function doesn't make any changes in the state/blockchain and is
+// useful to preview results of a sequence of dependent transactions.
+func (s *PublicBlockChainAPI) CallBundle(ctx context.Context, args []TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides) (*BundleResult, error) {
+	return DoCallBundle(ctx, s.b, args, blockNrOrHash, overrides, blockOverrides, bundleTimeout, s.b.RPCGasCap())
+}
+
+// DoCallBundle executes the calls sequentially within a single state.
+// The globalGasCap limits the total gas of all the calls.
+func DoCallBundle(ctx context.Context, b Backend, args []TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *StateOverride, blockOverrides *BlockOverrides, timeout time.Duration, globalGasCap uint64) (*BundleResult, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM bundle finished", "runtime", time.Since(start)) }(time.Now())
+
+	if len(args) == 0 {
+		return nil, errors.New("empty bundle")
+	}
+	if len(args) > maxBundleCalls {
+		return nil, fmt.Errorf("too many calls in bundle, the limit is %d", maxBundleCalls)
+	}
+
+	statedb, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	if err := overrides.Apply(statedb); err != nil {
+		return nil, err
+	}
+	header = blockOverrides.Apply(header)
+
+	// Setup context so it may be cancelled the bundle has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	// Make sure the context is cancelled when the bundle has completed
+	// this makes sure resources are cleaned up.
+	defer cancel()
+
+	gasLeft := globalGasCap
+	if gasLeft == 0 {
+		gasLeft = math.MaxUint64
+	}
+	res := &BundleResult{
+		BlockNumber: hexutil.Uint64(header.Number.Uint64()),
+		Results:     make([]*BundleCallResult, 0, len(args)),
+	}
+	for i, callArgs := range args {
+		if gasLeft == 0 {
+			return nil, fmt.Errorf("call %d: bundle gas cap %d exceeded", i, globalGasCap)
+		}
+		msg, err := callArgs.ToMessage(gasLeft, header.BaseFee)
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		vmConfig := opera.DefaultVMConfig
+		vmConfig.NoBaseFee = true
+		evm, vmError, err := b.GetEVM(ctx, msg, statedb, header, &vmConfig)
+		if err != nil {
+			return nil, err
+		}
+		// Wait for the context to be done and cancel the evm. Even if the
+		// EVM has finished, cancelling may be done (repeatedly)
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-ctx.Done():
+				evm.Cancel()
+			case <-done:
+			}
+		}()
+
+		// Use a synthetic hash to collect the logs of the call
+		callHash := common.BigToHash(big.NewInt(int64(i + 1)))
+		statedb.Prepare(callHash, i)
+
+		result, err := evmcore.ApplyMessage(evm, msg, new(evmcore.GasPool).AddGas(math.MaxUint64))
+		close(done)
+		if err := vmError(); err != nil {
+			return nil, err
+		}
+		// If the timer caused an abort, return an appropriate error message
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("call %d: err: %w (supplied gas %d)", i, err, msg.Gas())
+		}
+
+		callRes := &BundleCallResult{
+			ReturnData: result.Return(),
+			Logs:       statedb.GetLogs(callHash, header.Hash),
+			GasUsed:    hexutil.Uint64(result.UsedGas),
+		}
+		for _, l := range callRes.Logs {
+			l.TxHash = common.Hash{}
+			l.BlockNumber = header.Number.Uint64()
+		}
+		if callRes.Logs == nil {
+			callRes.Logs = []*types.Log{}
+		}
+		if result.Err != nil {
+			callRes.Error = result.Err.Error()
+			if len(result.Revert()) > 0 {
+				callRes.Error = newRevertError(result).Error()
+				callRes.Revert = result.Revert()
+			}
+		}
+		res.Results = append(res.Results, callRes)
+		res.GasUsed += hexutil.Uint64(result.UsedGas)
+		gasLeft -= result.UsedGas
+
+		// Finalize the state so any modifications are visible to the next call
+		statedb.Finalise(true)
+	}
+	return res, nil
+}
@@ -0,0 +1,91 @@
+package ethapi
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// stores the first calldata word in slot 0, returns the slot if there's no calldata
+	loadStoreCode = hexutil.Bytes(common.FromHex("0x36600f5760005460005260206000f35b60003560005500"))
+	// reverts with the 42 word
+	revertDataCode = hexutil.Bytes(common.FromHex("0x602a60005260206000fd"))
+)
+
+func TestCallBundle(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	b := newTestTracingBackend()
+	_, err := b.processBlock(1, signTestTx(t, b, 0, storeAddr, common.LeftPadBytes([]byte{1}, 32)))
+	require.NoError(err)
+	_, err = b.processBlock(2)
+	require.NoError(err)
+	api := NewPublicBlockChainAPI(b)
+
+	from := common.Address{0x99}
+	loadStoreAddr := common.Address{0x53}
+	overrides := &StateOverride{
+		storeAddr:     {Code: &loadStoreCode},
+		loadStoreAddr: {Code: &loadStoreCode},
+		revertAddr:    {Code: &revertDataCode},
+	}
+	call := func(to common.Address, data []byte) TransactionArgs {
+		input := hexutil.Bytes(data)
+		return TransactionArgs{From: &from, To: &to, Data: &input}
+	}
+	word := func(v byte) []byte {
+		return common.LeftPadBytes([]byte{v}, 32)
+	}
+
+	// the bundle is executed on top of the empty block,
+	// the reverted call doesn't stop the bundle and the state changes are carried forward
+	res, err := api.CallBundle(ctx, []TransactionArgs{
+		call(storeAddr, nil),
+		call(loadStoreAddr, word(7)),
+		call(revertAddr, nil),
+		call(loadStoreAddr, nil),
+	}, rpc.BlockNumberOrHashWithNumber(2), overrides, nil)
+	require.NoError(err)
+	require.Equal(hexutil.Uint64(2), res.BlockNumber)
+	require.Len(res.Results, 4)
+	// the state of the previous block is visible
+	require.Equal(hexutil.Bytes(word(1)), res.Results[0].ReturnData)
+	require.Empty(res.Results[1].Error)
+	require.Equal("execution reverted", res.Results[2].Error)
+	require.Equal(hexutil.Bytes(word(42)), res.Results[2].Revert)
+	require.Empty(res.Results[3].Error)
+	require.Equal(hexutil.Bytes(word(7)), res.Results[3].ReturnData)
+	gasUsed := hexutil.Uint64(0)
+	for _, r := range res.Results {
+		require.NotZero(r.GasUsed)
+		require.NotNil(r.Logs)
+		gasUsed += r.GasUsed
+	}
+	require.Equal(gasUsed, res.GasUsed)
+
+	// the bundle doesn't modify the state
+	res, err = api.CallBundle(ctx, []TransactionArgs{call(loadStoreAddr, nil)}, rpc.BlockNumberOrHashWithNumber(2), overrides, nil)
+	require.NoError(err)
+	require.Equal(hexutil.Bytes(word(0)), res.Results[0].ReturnData)
+
+	// the block context is overridden
+	number := hexutil.Big(*big.NewInt(100))
+	res, err = api.CallBundle(ctx, []TransactionArgs{call(storeAddr, nil)}, rpc.BlockNumberOrHashWithNumber(2), overrides, &BlockOverrides{Number: &number})
+	require.NoError(err)
+	require.Equal(hexutil.Uint64(100), res.BlockNumber)
+
+	// an unknown block has no result
+	res, err = api.CallBundle(ctx, []TransactionArgs{call(storeAddr, nil)}, rpc.BlockNumberOrHashWithHash(common.Hash{0xff}, false), nil, nil)
+	require.NoError(err)
+	require.Nil(res)
+
+	_, err = api.CallBundle(ctx, nil, rpc.BlockNumberOrHashWithNumber(2), nil, nil)
+	require.EqualError(err, "empty bundle")
+}
@@ -0,0 +1,439 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+const (
+	// defaultTraceTimeout is the amount of time a single transaction can execute
+	// by default before being forcefully aborted.
+	defaultTraceTimeout = 5 * time.Second
+)
+
+// TraceConfig holds extra parameters to trace functions.
+type TraceConfig struct {
+	*vm.LogConfig
+	Tracer  *string
+	Timeout *string
+}
+
+// TraceCallConfig is the config for traceCall API. It holds one more
+// field to override the state for tracing.
+type TraceCallConfig struct {
+	*vm.LogConfig
+	Tracer         *string
+	Timeout        *string
+	StateOverrides *StateOverride
+}
+
+// txTraceResult is the result of a single transaction trace.
+type txTraceResult struct {
+	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
+	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+}
+
+// TraceTransaction returns the structured logs created during the execution of EVM
+// and returns them as a JSON object.
+func (api *PublicDebugAPI) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+	tx, blockNumber, index, err := api.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s not found", hash.Hex())
+	}
+	// It shouldn't happen in practice.
+	if blockNumber == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	block, err := api.b.BlockByNumber(ctx, rpc.BlockNumber(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNumber)
+	}
+	// the preceding transactions are replayed without tracing
+	results, err := api.traceBlockTxs(ctx, block, int(index), int(index)+1, config)
+	if err != nil {
+		return nil, err
+	}
+	if results[0].Error != "" {
+		return nil, errors.New(results[0].Error)
+	}
+	return results[0].Result, nil
+}
+
+// TraceBlockByNumber returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *PublicDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
+	block, err := api.b.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return api.traceBlock(ctx, block, config)
+}
+
+// TraceBlockByHash returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *PublicDebugAPI) TraceBlockByHash(ctx context.Context, hash common.Hash, config *TraceConfig) ([]*txTraceResult, error) {
+	block, err := api.b.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %s not found", hash.Hex())
+	}
+	return api.traceBlock(ctx, block, config)
+}
+
+// TraceCall lets you trace a given eth_call. It collects the structured logs
+// created during the execution of EVM if the given transaction was added on
+// top of the provided block and returns them as a JSON object.
+func (api *PublicDebugAPI) TraceCall(ctx context.Context, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
+	statedb, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	// Apply the customized state rules if required.
+	if config != nil {
+		if err := config.StateOverrides.Apply(statedb); err != nil {
+			return nil, err
+		}
+	}
+	msg, err := args.ToMessage(api.b.RPCGasCap(), header.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+
+	var traceConfig *TraceConfig
+	if config != nil {
+		traceConfig = &TraceConfig{
+			LogConfig: config.LogConfig,
+			Tracer:    config.Tracer,
+			Timeout:   config.Timeout,
+		}
+	}
+	// The call isn't required to pay the base fee, like eth_call
+	return api.traceTx(ctx, msg, new(tracers.Context), header, statedb, traceConfig, true)
+}
+
+// traceBlock configures a new tracer according to the provided configuration, and
+// executes all the transactions contained within. The return value will be one item
+// per transaction, dependent on the requested tracer.
+func (api *PublicDebugAPI) traceBlock(ctx context.Context, block *evmcore.EvmBlock, config *TraceConfig) ([]*txTraceResult, error) {
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	return api.traceBlockTxs(ctx, block, 0, len(block.Transactions), config)
+}
+
+// stateAtBlockStart returns the EVM state the block's transactions were applied on.
+func (api *PublicDebugAPI) stateAtBlockStart(ctx context.Context, block *evmcore.EvmBlock) (*state.StateDB, error) {
+	statedb, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(block.NumberU64()-1)))
+	if err != nil {
+		return nil, err
+	}
+	if statedb == nil {
+		return nil, fmt.Errorf("state of block #%d not found", block.NumberU64()-1)
+	}
+	return statedb, nil
+}
+
+// traceBlockTxs replays the first `to` transactions of the block by the state processor,
+// in the same way as the block was processed, and traces the transactions starting from `from`.
+// The return value is one item per traced transaction.
+func (api *PublicDebugAPI) traceBlockTxs(ctx context.Context, block *evmcore.EvmBlock, from, to int, config *TraceConfig) ([]*txTraceResult, error) {
+	statedb, err := api.stateAtBlockStart(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	timeout := defaultTraceTimeout
+	if config != nil && config.Timeout != nil {
+		if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+			return nil, err
+		}
+	}
+	tracer := &blockTracer{
+		ctx:       ctx,
+		config:    config,
+		blockHash: block.Hash,
+		from:      from,
+		timeout:   timeout,
+		results:   make([]*txTraceResult, 0, to-from),
+	}
+	vmConfig := opera.DefaultVMConfig
+	vmConfig.Debug = true
+	vmConfig.Tracer = tracer
+
+	processor := evmcore.NewStateProcessor(api.b.ChainConfig(), &chainReader{ctx, api.b})
+	usedGas := uint64(0)
+	_, _, skipped, err := processor.Process(evmcore.NewEvmBlock(block.Header(), block.Transactions[:to]), statedb, vmConfig, &usedGas, func(*types.Log, *state.StateDB) {})
+	tracer.stopTimeout()
+	if err != nil {
+		return nil, err
+	}
+	if tracer.err != nil {
+		return nil, tracer.err
+	}
+	if tracer.aborted {
+		return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+	}
+	if len(skipped) != 0 {
+		tx := block.Transactions[skipped[0]]
+		return nil, fmt.Errorf("transaction %s of block #%d cannot be replayed", tx.Hash().Hex(), block.NumberU64())
+	}
+	return tracer.results, nil
+}
+
+// blockTracer is an evmcore.TxTracer which creates a new tracer for every traced transaction
+// processed by the state processor, and collects the results.
+type blockTracer struct {
+	ctx       context.Context
+	config    *TraceConfig
+	blockHash common.Hash
+	// from is the index of the first traced transaction, the preceding ones are only applied
+	from    int
+	timeout time.Duration
+
+	// tracer is the tracer of the current transaction, nil if it isn't traced
+	tracer   vm.Tracer
+	deadline context.Context
+	cancel   context.CancelFunc
+	// aborted is set if a transaction execution reached the timeout
+	aborted bool
+
+	results []*txTraceResult
+	err     error
+}
+
+// StartTx creates the tracer of the transaction.
+func (t *blockTracer) StartTx(tx *types.Transaction, index int) {
+	t.tracer = nil
+	if index < t.from || t.err != nil {
+		return
+	}
+	tracer, err := newTracer(t.config, &tracers.Context{
+		BlockHash: t.blockHash,
+		TxIndex:   index,
+		TxHash:    tx.Hash(),
+	})
+	if err != nil {
+		t.err = err
+		return
+	}
+	t.tracer = tracer
+}
+
+// FinishTx formats the trace result of the transaction.
+func (t *blockTracer) FinishTx(tx *types.Transaction, receipt *types.Receipt) {
+	t.stopTimeout()
+	if t.tracer == nil || receipt == nil {
+		return
+	}
+	var output []byte
+	if logger, ok := t.tracer.(*vm.StructLogger); ok {
+		output = logger.Output()
+	}
+	res, err := formatTrace(t.tracer, receipt.GasUsed, receipt.Status == types.ReceiptStatusFailed, output)
+	if err != nil {
+		t.results = append(t.results, &txTraceResult{Error: err.Error()})
+	} else {
+		t.results = append(t.results, &txTraceResult{Result: res})
+	}
+	t.tracer = nil
+}
+
+// CaptureStart starts the timeout of the traced transaction.
+func (t *blockTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	if t.tracer == nil {
+		return
+	}
+	// Handle timeouts and RPC cancellations
+	deadlineCtx, cancel := context.WithTimeout(t.ctx, t.timeout)
+	t.deadline, t.cancel = deadlineCtx, cancel
+	tracer := t.tracer
+	go func() {
+		<-deadlineCtx.Done()
+		if deadlineCtx.Err() == context.DeadlineExceeded {
+			if jst, ok := tracer.(*tracers.Tracer); ok {
+				jst.Stop(errors.New("execution timeout"))
+			}
+			env.Cancel()
+		}
+	}()
+	t.tracer.CaptureStart(env, from, to, create, input, gas, value)
+}
+
+// stopTimeout stops the timeout of the current transaction, and checks whether it was reached.
+func (t *blockTracer) stopTimeout() {
+	if t.cancel == nil {
+		return
+	}
+	if t.deadline.Err() == context.DeadlineExceeded {
+		t.aborted = true
+	}
+	t.cancel()
+	t.deadline, t.cancel = nil, nil
+}
+
+func (t *blockTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	if t.tracer != nil {
+		t.tracer.CaptureState(env, pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (t *blockTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.tracer != nil {
+		t.tracer.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+func (t *blockTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	if t.tracer != nil {
+		t.tracer.CaptureExit(output, gasUsed, err)
+	}
+}
+
+func (t *blockTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	if t.tracer != nil {
+		t.tracer.CaptureFault(env, pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+func (t *blockTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) {
+	if t.tracer != nil {
+		t.tracer.CaptureEnd(output, gasUsed, d, err)
+	}
+}
+
+// chainReader provides the block headers to the EVM, e.g. for the BLOCKHASH opcode
+type chainReader struct {
+	ctx context.Context
+	b   Backend
+}
+
+// GetHeader returns the header of the block, or nil if it isn't found.
+func (r *chainReader) GetHeader(hash common.Hash, number uint64) *evmcore.EvmHeader {
+	header, err := r.b.HeaderByNumber(r.ctx, rpc.BlockNumber(number))
+	if err != nil || header == nil || header.Hash != hash && hash != (common.Hash{}) {
+		return nil
+	}
+	return header
+}
+
+// traceTx configures a new tracer according to the provided configuration, and
+// executes the given message in the provided environment. The return value will
+// be tracer dependent. If noBaseFee is true, the message isn't required to pay the base fee.
+func (api *PublicDebugAPI) traceTx(ctx context.Context, msg evmcore.Message, txctx *tracers.Context, header *evmcore.EvmHeader, statedb *state.StateDB, config *TraceConfig, noBaseFee bool) (interface{}, error) {
+	var (
+		tracer  vm.Tracer
+		err     error
+		timeout = defaultTraceTimeout
+	)
+	if config != nil && config.Timeout != nil {
+		if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+			return nil, err
+		}
+	}
+	if tracer, err = newTracer(config, txctx); err != nil {
+		return nil, err
+	}
+
+	// Run the transaction with tracing enabled, keeping the Opera-specific precompiles
+	vmConfig := opera.DefaultVMConfig
+	vmConfig.Debug = true
+	vmConfig.Tracer = tracer
+	vmConfig.NoBaseFee = noBaseFee
+	evm, vmError, err := api.b.GetEVM(ctx, msg, statedb, header, &vmConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handle timeouts and RPC cancellations
+	deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	go func() {
+		<-deadlineCtx.Done()
+		if deadlineCtx.Err() == context.DeadlineExceeded {
+			if jst, ok := tracer.(*tracers.Tracer); ok {
+				jst.Stop(errors.New("execution timeout"))
+			}
+			evm.Cancel()
+		}
+	}()
+
+	// Call Prepare to clear out the statedb access list
+	statedb.Prepare(txctx.TxHash, txctx.TxIndex)
+
+	result, err := evmcore.ApplyMessage(evm, msg, new(evmcore.GasPool).AddGas(msg.Gas()))
+	if err := vmError(); err != nil {
+		return nil, err
+	}
+	if evm.Cancelled() {
+		return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %w", err)
+	}
+
+	// If the result contains a revert reason, return it.
+	output := result.Return()
+	if len(result.Revert()) > 0 {
+		output = result.Revert()
+	}
+	return formatTrace(tracer, result.UsedGas, result.Failed(), output)
+}
+
+// newTracer constructs the tracer according to the provided configuration,
+// either the JavaScript tracer (a built-in one by its name, or a custom code) or the struct logger.
+func newTracer(config *TraceConfig, txctx *tracers.Context) (vm.Tracer, error) {
+	switch {
+	case config != nil && config.Tracer != nil:
+		tracer, err := tracers.New(*config.Tracer, txctx)
+		if err != nil {
+			return nil, err
+		}
+		return tracer, nil
+	case config == nil:
+		return vm.NewStructLogger(nil), nil
+	default:
+		return vm.NewStructLogger(config.LogConfig), nil
+	}
+}
+
+// formatTrace returns the tracer output depending on the tracer type.
+func formatTrace(tracer vm.Tracer, gas uint64, failed bool, output []byte) (interface{}, error) {
+	switch tracer := tracer.(type) {
+	case *vm.StructLogger:
+		return &ExecutionResult{
+			Gas:         gas,
+			Failed:      failed,
+			ReturnValue: fmt.Sprintf("%x", output),
+			StructLogs:  FormatLogs(tracer.StructLogs()),
+		}, nil
+
+	case *tracers.Tracer:
+		return tracer.GetResult()
+
+	default:
+		return nil, fmt.Errorf("unsupported tracer type %T", tracer)
+	}
+}
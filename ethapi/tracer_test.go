@@ -0,0 +1,176 @@
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr    = crypto.PubkeyToAddress(testKey.PublicKey)
+	storeAddr   = common.Address{0x51}
+	revertAddr  = common.Address{0x52}
+	testBalance = new(big.Int).Mul(big.NewInt(1e18), big.NewInt(1e6))
+)
+
+// newTestTracingBackend creates a backend with the sender account,
+// a contract which stores the first calldata word and a contract which always reverts
+func newTestTracingBackend() *testBackend {
+	return newTestStateBackend(func(statedb *state.StateDB) {
+		statedb.SetBalance(testAddr, testBalance)
+		// PUSH1 0 CALLDATALOAD PUSH1 0 SSTORE STOP
+		statedb.SetCode(storeAddr, common.FromHex("0x6000356000550000"))
+		// PUSH1 0 PUSH1 0 REVERT
+		statedb.SetCode(revertAddr, common.FromHex("0x60006000fd"))
+	})
+}
+
+func signTestTx(t *testing.T, b *testBackend, nonce uint64, to common.Address, data []byte) *types.Transaction {
+	tx, err := types.SignNewTx(testKey, types.LatestSignerForChainID(b.chainConfig.ChainID), &types.DynamicFeeTx{
+		ChainID:   b.chainConfig.ChainID,
+		Nonce:     nonce,
+		To:        &to,
+		Gas:       100000,
+		GasFeeCap: big.NewInt(10),
+		GasTipCap: big.NewInt(1),
+		Data:      data,
+	})
+	require.NoError(t, err)
+	return tx
+}
+
+func TestTraceTransaction(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	b := newTestTracingBackend()
+	txs := types.Transactions{
+		signTestTx(t, b, 0, storeAddr, common.LeftPadBytes([]byte{1}, 32)),
+		signTestTx(t, b, 1, storeAddr, common.LeftPadBytes([]byte{2}, 32)),
+		signTestTx(t, b, 2, revertAddr, nil),
+	}
+	block, err := b.processBlock(1, txs...)
+	require.NoError(err)
+	require.Len(block.Transactions, 3)
+	api := NewPublicDebugAPI(b)
+
+	// the preceding transactions are replayed, so the nonce of the traced one is valid
+	// and the gas matches the processing, as the slot was written by the previous transaction
+	res, err := api.TraceTransaction(ctx, txs[1].Hash(), nil)
+	require.NoError(err)
+	trace := res.(*ExecutionResult)
+	require.False(trace.Failed)
+	require.Equal(b.receipts[1][1].GasUsed, trace.Gas)
+	var sstore *StructLogRes
+	for i, l := range trace.StructLogs {
+		if l.Op == "SSTORE" {
+			sstore = &trace.StructLogs[i]
+		}
+	}
+	require.NotNil(sstore)
+	require.Equal(common.LeftPadBytes([]byte{2}, 32), common.FromHex((*sstore.Storage)[common.Hash{}.Hex()[2:]]))
+
+	// a built-in tracer
+	tracer := "callTracer"
+	res, err = api.TraceTransaction(ctx, txs[2].Hash(), &TraceConfig{Tracer: &tracer})
+	require.NoError(err)
+	var call struct {
+		Type  string         `json:"type"`
+		From  common.Address `json:"from"`
+		To    common.Address `json:"to"`
+		Error string         `json:"error"`
+	}
+	require.NoError(json.Unmarshal(res.(json.RawMessage), &call))
+	require.Equal("CALL", call.Type)
+	require.Equal(testAddr, call.From)
+	require.Equal(revertAddr, call.To)
+	require.Equal("execution reverted", call.Error)
+
+	// a bad tracer is an error
+	bad := "{"
+	_, err = api.TraceTransaction(ctx, txs[2].Hash(), &TraceConfig{Tracer: &bad})
+	require.Error(err)
+
+	// the timeout aborts the tracing
+	timeout := "0s"
+	_, err = api.TraceTransaction(ctx, txs[1].Hash(), &TraceConfig{Timeout: &timeout})
+	require.EqualError(err, "execution aborted (timeout = 0s)")
+
+	_, err = api.TraceTransaction(ctx, common.Hash{1}, nil)
+	require.EqualError(err, "transaction "+common.Hash{1}.Hex()+" not found")
+}
+
+func TestTraceBlock(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	b := newTestTracingBackend()
+	txs := types.Transactions{
+		signTestTx(t, b, 0, storeAddr, common.LeftPadBytes([]byte{1}, 32)),
+		signTestTx(t, b, 1, revertAddr, nil),
+	}
+	block, err := b.processBlock(1, txs...)
+	require.NoError(err)
+	api := NewPublicDebugAPI(b)
+
+	results, err := api.TraceBlockByNumber(ctx, 1, nil)
+	require.NoError(err)
+	require.Len(results, 2)
+	for i, res := range results {
+		require.Empty(res.Error)
+		trace := res.Result.(*ExecutionResult)
+		require.Equal(b.receipts[1][i].GasUsed, trace.Gas)
+		require.Equal(b.receipts[1][i].Status == types.ReceiptStatusFailed, trace.Failed)
+	}
+	require.True(results[1].Result.(*ExecutionResult).Failed)
+	// the block and single transactions are traced in the same way
+	for i, tx := range txs {
+		single, err := api.TraceTransaction(ctx, tx.Hash(), nil)
+		require.NoError(err)
+		require.Equal(results[i].Result, single)
+	}
+
+	byHash, err := api.TraceBlockByHash(ctx, block.Hash, nil)
+	require.NoError(err)
+	require.Equal(results, byHash)
+
+	_, err = api.TraceBlockByNumber(ctx, 0, nil)
+	require.EqualError(err, "genesis is not traceable")
+	_, err = api.TraceBlockByNumber(ctx, 2, nil)
+	require.Error(err)
+}
+
+func TestTraceCall(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	b := newTestTracingBackend()
+	_, err := b.processBlock(1, signTestTx(t, b, 0, storeAddr, common.LeftPadBytes([]byte{1}, 32)))
+	require.NoError(err)
+	api := NewPublicDebugAPI(b)
+
+	// the call doesn't pay the base fee
+	from := common.Address{0x99}
+	data := hexutil.Bytes(common.LeftPadBytes([]byte{3}, 32))
+	res, err := api.TraceCall(ctx, TransactionArgs{From: &from, To: &storeAddr, Data: &data}, rpc.BlockNumberOrHashWithNumber(1), nil)
+	require.NoError(err)
+	require.False(res.(*ExecutionResult).Failed)
+
+	// the state overrides are applied
+	code := hexutil.Bytes(common.FromHex("0x60006000fd"))
+	res, err = api.TraceCall(ctx, TransactionArgs{From: &from, To: &storeAddr, Data: &data}, rpc.BlockNumberOrHashWithNumber(1), &TraceCallConfig{
+		StateOverrides: &StateOverride{storeAddr: {Code: &code}},
+	})
+	require.NoError(err)
+	require.True(res.(*ExecutionResult).Failed)
+}
@@ -31,6 +31,16 @@ import (
 	"github.com/Fantom-foundation/go-opera/utils/signers/internaltx"
 )
 
+// TxTracer is a vm.Tracer which is additionally notified about boundaries
+// of the processed transactions.
+type TxTracer interface {
+	vm.Tracer
+	// StartTx is called before the transaction is applied.
+	StartTx(tx *types.Transaction, index int)
+	// FinishTx is called after the transaction is applied, receipt is nil if tx is skipped.
+	FinishTx(tx *types.Transaction, receipt *types.Receipt)
+}
+
 // StateProcessor is a basic Processor, which takes care of transitioning
 // state from one point to another.
 //
@@ -71,6 +81,7 @@ func (p *StateProcessor) Process(
 		blockHash    = block.Hash
 		blockNumber  = block.Number
 		signer       = gsignercache.Wrap(types.MakeSigner(p.config, header.Number))
+		txTracer, _  = cfg.Tracer.(TxTracer)
 	)
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions {
@@ -80,7 +91,13 @@ func (p *StateProcessor) Process(
 		}
 
 		statedb.Prepare(tx.Hash(), i)
+		if txTracer != nil {
+			txTracer.StartTx(tx, i)
+		}
 		receipt, _, skip, err = applyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv, onNewLog)
+		if txTracer != nil {
+			txTracer.FinishTx(tx, receipt)
+		}
 		if skip {
 			skipped = append(skipped, uint32(i))
 			err = nil
@@ -87,6 +87,10 @@ var (
 	// than some meaningful limit a user might use. This is not a consensus error
 	// making the transaction invalid, rather a DOS protection.
 	ErrOversizedData = errors.New("oversized data")
+
+	// ErrPrivateTxExpired is returned if the expiry block of a private transaction
+	// is already reached.
+	ErrPrivateTxExpired = errors.New("private transaction expired")
 )
 
 var (
@@ -152,6 +156,9 @@ type TxPoolConfig struct {
 	Journal   string           // Journal of local transactions to survive node restarts
 	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
 
+	Snapshot   string        // Snapshot of remote transactions to survive node restarts, disabled if empty
+	Resnapshot time.Duration // Time interval to regenerate the snapshot of remote transactions
+
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
 
@@ -169,6 +176,8 @@ var DefaultTxPoolConfig = TxPoolConfig{
 	Journal:   "transactions.rlp",
 	Rejournal: time.Hour,
 
+	Resnapshot: 10 * time.Minute,
+
 	PriceLimit: 1,
 	PriceBump:  10,
 
@@ -188,6 +197,10 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 		log.Warn("Sanitizing invalid txpool journal time", "provided", conf.Rejournal, "updated", time.Second)
 		conf.Rejournal = time.Second
 	}
+	if conf.Resnapshot < time.Second {
+		log.Warn("Sanitizing invalid txpool snapshot time", "provided", conf.Resnapshot, "updated", time.Second)
+		conf.Resnapshot = time.Second
+	}
 	if conf.PriceLimit < 1 {
 		log.Warn("Sanitizing invalid txpool price limit", "provided", conf.PriceLimit, "updated", DefaultTxPoolConfig.PriceLimit)
 		conf.PriceLimit = DefaultTxPoolConfig.PriceLimit
@@ -219,6 +232,16 @@ func (config *TxPoolConfig) sanitize() TxPoolConfig {
 	return conf
 }
 
+// TxPolicy is an admission policy of the node operator, which is evaluated after
+// the transaction passed all the other validity checks.
+type TxPolicy interface {
+	// Check returns an error if the transaction must be rejected.
+	// The baseFee is the minimum gas price, which is subtracted from the gas price of legacy transactions to get the tip.
+	Check(tx *types.Transaction, from common.Address, local bool, baseFee *big.Int) error
+	// Accepted is called after the checked transaction is added into the pool
+	Accepted(tx *types.Transaction, from common.Address, local bool)
+}
+
 // TxPool contains all currently known transactions. Transactions
 // enter the pool when they are received from the network or submitted
 // locally. They exit the pool when they are included in the blockchain.
@@ -246,6 +269,10 @@ type TxPool struct {
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
+	snap    *txSnapshot // Snapshot of remote transactions to back up to disk
+	policy  TxPolicy    // Admission policy of the node operator
+
+	private map[common.Hash]uint64 // Expiry blocks of private transactions, which aren't announced to peers
 
 	pending map[common.Address]*txList   // All currently processable transactions
 	queue   map[common.Address]*txList   // Queued but non-processable transactions
@@ -290,6 +317,7 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain State
 		reorgDoneCh:     make(chan chan struct{}),
 		reorgShutdownCh: make(chan struct{}),
 		gasPrice:        new(big.Int).SetUint64(config.PriceLimit),
+		private:         make(map[common.Hash]uint64),
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
@@ -314,6 +342,14 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain State
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If the snapshot is enabled, restore remote transactions from disk
+	if config.Snapshot != "" {
+		pool.snap = newTxSnapshot(config.Snapshot)
+
+		if err := pool.snap.load(pool.AddRemotes); err != nil {
+			log.Warn("Failed to load transaction pool snapshot", "err", err)
+		}
+	}
 
 	// Subscribe events from blockchain and start the main event loop.
 	pool.chainHeadSub = pool.chain.SubscribeNewBlock(pool.chainHeadCh)
@@ -335,12 +371,14 @@ func (pool *TxPool) loop() {
 		report  = time.NewTicker(statsReportInterval)
 		evict   = time.NewTicker(evictionInterval)
 		journal = time.NewTicker(pool.config.Rejournal)
+		snap    = time.NewTicker(pool.config.Resnapshot)
 		// Track the previous head headers for transaction reorgs
 		head = pool.chain.CurrentBlock()
 	)
 	defer report.Stop()
 	defer evict.Stop()
 	defer journal.Stop()
+	defer snap.Stop()
 
 	for {
 		select {
@@ -396,6 +434,12 @@ func (pool *TxPool) loop() {
 				}
 				pool.mu.Unlock()
 			}
+
+		// Handle remote transaction snapshot regeneration
+		case <-snap.C:
+			if pool.snap != nil {
+				pool.writeSnapshot()
+			}
 		}
 	}
 }
@@ -412,9 +456,36 @@ func (pool *TxPool) Stop() {
 	if pool.journal != nil {
 		pool.journal.close()
 	}
+	if pool.snap != nil {
+		pool.writeSnapshot()
+	}
 	log.Info("Transaction pool stopped")
 }
 
+// writeSnapshot dumps the pending and queued transactions which aren't covered
+// by the local journal into the snapshot. Private transactions aren't persisted.
+func (pool *TxPool) writeSnapshot() {
+	pool.mu.Lock()
+	pending := make(map[common.Address]types.Transactions)
+	for addr, list := range pool.pending {
+		if pool.journal == nil || !pool.locals.contains(addr) {
+			pending[addr] = pool.withoutPrivate(list.Flatten())
+		}
+	}
+	queued := make(map[common.Address]types.Transactions)
+	for addr, list := range pool.queue {
+		if pool.journal == nil || !pool.locals.contains(addr) {
+			queued[addr] = pool.withoutPrivate(list.Flatten())
+		}
+	}
+	pool.mu.Unlock()
+
+	// pending transactions go first to restore them in the nonce order
+	if err := pool.snap.write(pending, queued); err != nil {
+		log.Warn("Failed to write transaction pool snapshot", "err", err)
+	}
+}
+
 // SubscribeNewTxsNotify registers a subscription of NewTxsNotify and
 // starts sending event to the given channel.
 func (pool *TxPool) SubscribeNewTxsNotify(ch chan<- NewTxsNotify) notify.Subscription {
@@ -429,6 +500,78 @@ func (pool *TxPool) GasPrice() *big.Int {
 	return new(big.Int).Set(pool.gasPrice)
 }
 
+// SetPolicy sets the admission policy of new transactions.
+// Transactions which are already in the pool aren't affected.
+func (pool *TxPool) SetPolicy(policy TxPolicy) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	pool.policy = policy
+}
+
+// AddPrivate enqueues a private transaction into the pool. Private transactions
+// are excluded from the pool content and from the new transactions notifications,
+// so they're never announced to peers. They are dropped after the expiry block is reached.
+func (pool *TxPool) AddPrivate(tx *types.Transaction, expiry uint64) error {
+	if pool.chain.CurrentBlock().NumberU64() >= expiry {
+		return ErrPrivateTxExpired
+	}
+	hash := tx.Hash()
+	pool.mu.Lock()
+	if pool.all.Get(hash) != nil {
+		pool.mu.Unlock()
+		return ErrAlreadyKnown
+	}
+	pool.private[hash] = expiry
+	pool.mu.Unlock()
+
+	err := pool.addTxs([]*types.Transaction{tx}, false, true)[0]
+	if err != nil {
+		pool.mu.Lock()
+		delete(pool.private, hash)
+		pool.mu.Unlock()
+	}
+	return err
+}
+
+// IsPrivate returns true if the pool transaction was submitted privately.
+func (pool *TxPool) IsPrivate(hash common.Hash) bool {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	_, ok := pool.private[hash]
+	return ok
+}
+
+// withoutPrivate filters out the private transactions. The slice is modified in place.
+// The caller must hold pool.mu.
+func (pool *TxPool) withoutPrivate(txs types.Transactions) types.Transactions {
+	if len(pool.private) == 0 {
+		return txs
+	}
+	public := txs[:0]
+	for _, tx := range txs {
+		if _, ok := pool.private[tx.Hash()]; !ok {
+			public = append(public, tx)
+		}
+	}
+	return public
+}
+
+// dropExpiredPrivate removes the private transactions whose expiry block is reached,
+// and forgets the private transactions which aren't in the pool anymore.
+// The caller must hold pool.mu.
+func (pool *TxPool) dropExpiredPrivate(head uint64) {
+	for hash, expiry := range pool.private {
+		if pool.all.Get(hash) == nil {
+			delete(pool.private, hash)
+		} else if head >= expiry {
+			pool.removeTx(hash, true)
+			delete(pool.private, hash)
+		}
+	}
+}
+
 // SetGasPrice updates the minimum price required by the transaction pool for a
 // new transaction, and drops all transactions below this threshold.
 func (pool *TxPool) SetGasPrice(price *big.Int) {
@@ -492,34 +635,40 @@ func (pool *TxPool) stats() (int, int) {
 
 // Content retrieves the data content of the transaction pool, returning all the
 // pending as well as queued transactions, grouped by account and sorted by nonce.
+// Private transactions are excluded.
 func (pool *TxPool) Content() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
 	pending := make(map[common.Address]types.Transactions)
 	for addr, list := range pool.pending {
-		pending[addr] = list.Flatten()
+		if txs := pool.withoutPrivate(list.Flatten()); len(txs) != 0 {
+			pending[addr] = txs
+		}
 	}
 	queued := make(map[common.Address]types.Transactions)
 	for addr, list := range pool.queue {
-		queued[addr] = list.Flatten()
+		if txs := pool.withoutPrivate(list.Flatten()); len(txs) != 0 {
+			queued[addr] = txs
+		}
 	}
 	return pending, queued
 }
 
 // ContentFrom retrieves the data content of the transaction pool, returning the
 // pending as well as queued transactions of this address, grouped by nonce.
+// Private transactions are excluded.
 func (pool *TxPool) ContentFrom(addr common.Address) (types.Transactions, types.Transactions) {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
 
 	var pending types.Transactions
 	if list, ok := pool.pending[addr]; ok {
-		pending = list.Flatten()
+		pending = pool.withoutPrivate(list.Flatten())
 	}
 	var queued types.Transactions
 	if list, ok := pool.queue[addr]; ok {
-		queued = list.Flatten()
+		queued = pool.withoutPrivate(list.Flatten())
 	}
 	return pending, queued
 }
@@ -566,8 +715,22 @@ func (pool *TxPool) PendingSlice() types.Transactions {
 	return pending
 }
 
+// SampleHashes returns hashes of random transactions, excluding private transactions
 func (pool *TxPool) SampleHashes(max int) []common.Hash {
-	return pool.all.SampleHashes(max)
+	hashes := pool.all.SampleHashes(max)
+
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	if len(pool.private) == 0 {
+		return hashes
+	}
+	public := hashes[:0]
+	for _, h := range hashes {
+		if _, ok := pool.private[h]; !ok {
+			public = append(public, h)
+		}
+	}
+	return public
 }
 
 // Locals retrieves the accounts currently considered local by the pool.
@@ -578,17 +741,17 @@ func (pool *TxPool) Locals() []common.Address {
 	return pool.locals.flatten()
 }
 
-// local retrieves all currently known local transactions, grouped by origin
-// account and sorted by nonce. The returned transaction set is a copy and can be
+// local retrieves all currently known local transactions except for the private ones,
+// grouped by origin account and sorted by nonce. The returned transaction set is a copy and can be
 // freely modified by calling code.
 func (pool *TxPool) local() map[common.Address]types.Transactions {
 	txs := make(map[common.Address]types.Transactions)
 	for addr := range pool.locals.accounts {
 		if pending := pool.pending[addr]; pending != nil {
-			txs[addr] = append(txs[addr], pending.Flatten()...)
+			txs[addr] = append(txs[addr], pool.withoutPrivate(pending.Flatten())...)
 		}
 		if queued := pool.queue[addr]; queued != nil {
-			txs[addr] = append(txs[addr], queued.Flatten()...)
+			txs[addr] = append(txs[addr], pool.withoutPrivate(queued.Flatten())...)
 		}
 	}
 	return txs
@@ -665,6 +828,12 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if tx.Gas() < intrGas {
 		return ErrIntrinsicGas
 	}
+	// Ensure the transaction is admitted by the node policy
+	if pool.policy != nil {
+		if err := pool.policy.Check(tx, from, local, pool.chain.MinGasPrice()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -692,6 +861,15 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (replaced bool, err e
 		invalidTxMeter.Mark(1)
 		return false, err
 	}
+	// Notify the node policy only about the transactions which are actually added
+	if pool.policy != nil {
+		defer func() {
+			if err == nil {
+				from, _ := types.Sender(pool.signer, tx) // already validated
+				pool.policy.Accepted(tx, from, isLocal)
+			}
+		}()
+	}
 	// If the transaction pool is full, discard underpriced transactions
 	if uint64(pool.all.Slots()+numSlots(tx)) > pool.config.GlobalSlots+pool.config.GlobalQueue {
 		// If the new transaction is underpriced, don't accept it
@@ -810,6 +988,10 @@ func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
 	if pool.journal == nil || !pool.locals.contains(from) {
 		return
 	}
+	// Private transactions don't survive restarts
+	if _, ok := pool.private[tx.Hash()]; ok {
+		return
+	}
 	if err := pool.journal.insert(tx); err != nil {
 		log.Warn("Failed to journal local transaction", "err", err)
 	}
@@ -985,6 +1167,94 @@ func (pool *TxPool) Status(hashes []common.Hash) []TxStatus {
 	return status
 }
 
+// TxInfo describes a pool transaction relative to the other transactions of its sender.
+type TxInfo struct {
+	Tx     *types.Transaction
+	From   common.Address
+	Status TxStatus
+	Local  bool
+	// StateNonce is the sender nonce in the current pool state
+	StateNonce uint64
+	// Pending and Queued are nonces of the sender transactions in the pool, sorted
+	Pending []uint64
+	Queued  []uint64
+	// NonceGap is the number of missing nonces before the transaction
+	NonceGap uint64
+	// AccountSlots and AccountQueue are the per-account limits of the pool
+	AccountSlots uint64
+	AccountQueue uint64
+	// MinTip is the min gas tip accepted by the pool
+	MinTip *big.Int
+	// ReplacementFeeCap and ReplacementTipCap are the min prices of a transaction with
+	// the same nonce to replace this transaction
+	ReplacementFeeCap *big.Int
+	ReplacementTipCap *big.Int
+}
+
+// TxInfo returns the pool state of a transaction, or nil if it isn't in the pool.
+func (pool *TxPool) TxInfo(hash common.Hash) *TxInfo {
+	tx := pool.Get(hash)
+	if tx == nil {
+		return nil
+	}
+	from, _ := types.Sender(pool.signer, tx) // already validated
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	info := &TxInfo{
+		Tx:           tx,
+		From:         from,
+		Local:        pool.locals.contains(from),
+		StateNonce:   pool.currentState.GetNonce(from),
+		AccountSlots: pool.config.AccountSlots,
+		AccountQueue: pool.config.AccountQueue,
+		MinTip:       new(big.Int).Set(pool.gasPrice),
+	}
+	known := make(map[uint64]bool)
+	if list := pool.pending[from]; list != nil {
+		for _, t := range list.Flatten() {
+			info.Pending = append(info.Pending, t.Nonce())
+			known[t.Nonce()] = true
+		}
+		if list.txs.Get(tx.Nonce()) != nil {
+			info.Status = TxStatusPending
+		}
+	}
+	if list := pool.queue[from]; list != nil {
+		for _, t := range list.Flatten() {
+			info.Queued = append(info.Queued, t.Nonce())
+			known[t.Nonce()] = true
+		}
+		if list.txs.Get(tx.Nonce()) != nil {
+			info.Status = TxStatusQueued
+		}
+	}
+	if info.Status == TxStatusUnknown {
+		// the tx was removed between pool.Get and obtaining the lock
+		return nil
+	}
+	for nonce := info.StateNonce; nonce < tx.Nonce(); nonce++ {
+		if !known[nonce] {
+			info.NonceGap++
+		}
+	}
+	info.ReplacementFeeCap = replacementPrice(tx.GasFeeCap(), pool.config.PriceBump)
+	info.ReplacementTipCap = replacementPrice(tx.GasTipCap(), pool.config.PriceBump)
+	return info
+}
+
+// replacementPrice returns the min price which is accepted by txList.Add to replace a transaction with the old price
+func replacementPrice(old *big.Int, priceBump uint64) *big.Int {
+	threshold := new(big.Int).Mul(big.NewInt(100+int64(priceBump)), old)
+	threshold.Div(threshold, big.NewInt(100))
+	if threshold.Cmp(old) <= 0 {
+		// the new price has to be strictly higher
+		threshold.Add(old, common.Big1)
+	}
+	return threshold
+}
+
 // Get returns a transaction if it is contained in the pool and nil otherwise.
 func (pool *TxPool) Get(hash common.Hash) *types.Transaction {
 	return pool.all.Get(hash)
@@ -1184,6 +1454,15 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 	// Check for pending transactions for every account that sent new ones
 	promoted := pool.promoteExecutables(promoteAddrs)
 
+	// Drop private transactions which cannot be included anymore
+	if reset != nil {
+		head := reset.newHead
+		if head == nil {
+			head = pool.chain.CurrentBlock().Header()
+		}
+		pool.dropExpiredPrivate(head.Number.Uint64())
+	}
+
 	// If a new block appeared, validate the pool of pending transactions. This will
 	// remove any transaction that has been included in the block or was invalidated
 	// because of another transaction (e.g. higher gas price).
@@ -1224,7 +1503,13 @@ func (pool *TxPool) runReorg(done chan struct{}, reset *txpoolResetRequest, dirt
 		for _, set := range events {
 			txs = append(txs, set.Flatten()...)
 		}
-		pool.txFeed.Send(NewTxsNotify{txs})
+		// Private transactions aren't announced to subsystems
+		pool.mu.RLock()
+		txs = pool.withoutPrivate(txs)
+		pool.mu.RUnlock()
+		if len(txs) > 0 {
+			pool.txFeed.Send(NewTxsNotify{txs})
+		}
 	}
 }
 
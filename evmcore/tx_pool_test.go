@@ -24,6 +24,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -2372,6 +2373,68 @@ func testTransactionJournaling(t *testing.T, nolocals bool) {
 	pool.Stop()
 }
 
+// Tests that remote transactions are restored from the pool snapshot after a restart,
+// and that the restored transactions are revalidated against the current state.
+func TestTransactionSnapshotting(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("failed to create temporary dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	config := testTxPoolConfig
+	config.Journal = filepath.Join(dir, "transactions.rlp")
+	config.Snapshot = filepath.Join(dir, "txpool.rlp")
+
+	pool := NewTxPool(config, params.TestChainConfig, blockchain)
+
+	local, _ := crypto.GenerateKey()
+	remote, _ := crypto.GenerateKey()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(local.PublicKey), big.NewInt(1000000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+
+	if err := pool.AddLocal(pricedTransaction(0, 100000, big.NewInt(1), local)); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	for _, nonce := range []uint64{0, 1, 2, 4} {
+		if err := pool.addRemoteSync(pricedTransaction(nonce, 100000, big.NewInt(1), remote)); err != nil {
+			t.Fatalf("failed to add remote transaction: %v", err)
+		}
+	}
+	pending, queued := pool.Stats()
+	if pending != 4 || queued != 1 {
+		t.Fatalf("transactions mismatched: have %d/%d, want %d/%d", pending, queued, 4, 1)
+	}
+	// Terminate the old pool, bump the remote nonce, create a new pool and ensure relevant transaction survive
+	pool.Stop()
+	statedb.SetNonce(crypto.PubkeyToAddress(remote.PublicKey), 1)
+	blockchain = &testBlockChain{statedb, 1000000, new(event.Feed)}
+
+	pool = NewTxPool(config, params.TestChainConfig, blockchain)
+	defer pool.Stop()
+	<-pool.requestReset(nil, nil)
+
+	pending, queued = pool.Stats()
+	if pending != 3 || queued != 1 {
+		t.Fatalf("transactions mismatched: have %d/%d, want %d/%d", pending, queued, 3, 1)
+	}
+	if !pool.locals.contains(crypto.PubkeyToAddress(local.PublicKey)) {
+		t.Fatalf("local account isn't restored from the journal")
+	}
+	if pool.locals.contains(crypto.PubkeyToAddress(remote.PublicKey)) {
+		t.Fatalf("remote account is restored as local")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // TestTransactionStatusCheck tests that the pool can correctly retrieve the
 // pending status of individual transactions.
 func TestTransactionStatusCheck(t *testing.T) {
@@ -2565,3 +2628,118 @@ func BenchmarkInsertRemoteWithAllLocals(b *testing.B) {
 		pool.Stop()
 	}
 }
+
+// Tests that the pool state of a transaction is reported relative to the sender transactions.
+func TestTransactionInfo(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(100000000))
+
+	txs := []*types.Transaction{
+		pricedTransaction(0, 100000, big.NewInt(10), key),
+		pricedTransaction(1, 100000, big.NewInt(10), key),
+		pricedTransaction(4, 100000, big.NewInt(10), key),
+	}
+	for _, err := range pool.AddRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("failed to add transaction: %v", err)
+		}
+	}
+
+	info := pool.TxInfo(txs[1].Hash())
+	if info == nil {
+		t.Fatalf("transaction info is missing")
+	}
+	if info.Status != TxStatusPending || info.From != from || info.NonceGap != 0 {
+		t.Errorf("pending transaction info mismatch: status %d, from %s, gap %d", info.Status, info.From.Hex(), info.NonceGap)
+	}
+	if len(info.Pending) != 2 || len(info.Queued) != 1 || info.Queued[0] != 4 {
+		t.Errorf("sender nonces mismatch: pending %v, queued %v", info.Pending, info.Queued)
+	}
+	if info.ReplacementFeeCap.Cmp(big.NewInt(11)) != 0 || info.ReplacementTipCap.Cmp(big.NewInt(11)) != 0 {
+		t.Errorf("replacement price mismatch: have %v/%v, want 11", info.ReplacementFeeCap, info.ReplacementTipCap)
+	}
+
+	info = pool.TxInfo(txs[2].Hash())
+	if info == nil || info.Status != TxStatusQueued || info.NonceGap != 2 {
+		t.Errorf("queued transaction info mismatch: %+v", info)
+	}
+	if pool.TxInfo(common.Hash{1}) != nil {
+		t.Errorf("unknown transaction info isn't nil")
+	}
+	if replacementPrice(big.NewInt(1), 10).Cmp(big.NewInt(2)) != 0 {
+		t.Errorf("replacement price of a wei-level transaction has to be strictly higher")
+	}
+}
+
+// Tests that private transactions aren't exposed to the subsystems and peers,
+// and that they're dropped once the expiry block is reached.
+func TestTransactionPrivate(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupTxPool()
+	defer pool.Stop()
+
+	events := make(chan NewTxsNotify, 32)
+	sub := pool.txFeed.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(100000000))
+
+	public := pricedTransaction(0, 100000, big.NewInt(10), key)
+	private := pricedTransaction(1, 100000, big.NewInt(10), key)
+	if err := pool.addRemoteSync(public); err != nil {
+		t.Fatalf("failed to add public transaction: %v", err)
+	}
+	if err := pool.AddPrivate(pricedTransaction(2, 100000, big.NewInt(10), key), 1); err != ErrPrivateTxExpired {
+		t.Fatalf("expired private transaction error mismatch: have %v, want %v", err, ErrPrivateTxExpired)
+	}
+	if err := pool.AddPrivate(private, 5); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	if err := pool.AddPrivate(public, 5); err != ErrAlreadyKnown {
+		t.Fatalf("known transaction error mismatch: have %v, want %v", err, ErrAlreadyKnown)
+	}
+	if err := validateEvents(events, 1); err != nil {
+		t.Fatalf("public transaction event firing failed: %v", err)
+	}
+	if !pool.IsPrivate(private.Hash()) || pool.IsPrivate(public.Hash()) {
+		t.Fatalf("private transactions mismatch")
+	}
+	if pending, _ := pool.Stats(); pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	// the private transaction is available only for the local emitter
+	if pending, _ := pool.Pending(false); len(pending[from]) != 2 {
+		t.Fatalf("pending transactions of sender mismatched: have %d, want %d", len(pending[from]), 2)
+	}
+	if pending, _ := pool.Content(); len(pending[from]) != 1 || pending[from][0] != public {
+		t.Fatalf("private transaction is exposed in the content")
+	}
+	if pending, _ := pool.ContentFrom(from); len(pending) != 1 || pending[0] != public {
+		t.Fatalf("private transaction is exposed in the sender content")
+	}
+	if hashes := pool.SampleHashes(10); len(hashes) != 1 || hashes[0] != public.Hash() {
+		t.Fatalf("private transaction is exposed in the sampled hashes")
+	}
+	// the private transaction is dropped once the expiry block is reached
+	<-pool.requestReset(nil, &EvmHeader{Number: big.NewInt(4)})
+	if !pool.Has(private.Hash()) {
+		t.Fatalf("private transaction is dropped before the expiry block")
+	}
+	<-pool.requestReset(nil, &EvmHeader{Number: big.NewInt(5)})
+	if pool.Has(private.Hash()) || pool.IsPrivate(private.Hash()) {
+		t.Fatalf("private transaction isn't dropped after the expiry block")
+	}
+	if !pool.Has(public.Hash()) {
+		t.Fatalf("public transaction is dropped")
+	}
+	if err := validateTxPoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
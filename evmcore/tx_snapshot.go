@@ -0,0 +1,110 @@
+package evmcore
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// txSnapshot is a dump of the pool transactions which aren't covered by the local journal,
+// it allows remote transactions to survive node restarts.
+// Unlike the journal, the snapshot is written entirely at once and isn't appended.
+type txSnapshot struct {
+	path string // Filesystem path to store the transactions at
+}
+
+func newTxSnapshot(path string) *txSnapshot {
+	return &txSnapshot{
+		path: path,
+	}
+}
+
+// load parses the snapshot from disk and passes its transactions to the add callback in batches.
+// Transactions are revalidated by the callback, so stale ones are dropped.
+func (s *txSnapshot) load(add func([]*types.Transaction) []error) error {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return nil
+	}
+	input, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	stream := rlp.NewStream(bufio.NewReader(input), 0)
+	total, dropped := 0, 0
+	loadBatch := func(txs types.Transactions) {
+		for _, err := range add(txs) {
+			if err != nil {
+				log.Debug("Failed to add snapshotted transaction", "err", err)
+				dropped++
+			}
+		}
+	}
+	var (
+		failure error
+		batch   types.Transactions
+	)
+	for {
+		tx := new(types.Transaction)
+		if err = stream.Decode(tx); err != nil {
+			if err != io.EOF {
+				failure = err
+			}
+			if batch.Len() > 0 {
+				loadBatch(batch)
+			}
+			break
+		}
+		total++
+
+		if batch = append(batch, tx); batch.Len() > 1024 {
+			loadBatch(batch)
+			batch = batch[:0]
+		}
+	}
+	log.Info("Loaded transaction pool snapshot", "transactions", total, "dropped", dropped)
+
+	return failure
+}
+
+// write replaces the snapshot with the given transactions.
+// Transactions of every account are expected to be sorted by nonce.
+func (s *txSnapshot) write(all ...map[common.Address]types.Transactions) error {
+	replacement, err := os.OpenFile(s.path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	output := bufio.NewWriter(replacement)
+	written, accounts := 0, 0
+	for _, txsOf := range all {
+		for _, txs := range txsOf {
+			for _, tx := range txs {
+				if err = rlp.Encode(output, tx); err != nil {
+					replacement.Close()
+					return err
+				}
+			}
+			written += len(txs)
+			accounts++
+		}
+	}
+	if err = output.Flush(); err != nil {
+		replacement.Close()
+		return err
+	}
+	if err = replacement.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(s.path+".new", s.path); err != nil {
+		return err
+	}
+	log.Info("Written transaction pool snapshot", "transactions", written, "accounts", accounts)
+
+	return nil
+}
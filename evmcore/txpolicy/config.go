@@ -0,0 +1,80 @@
+package txpolicy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Config is the txpool admission policy of the node operator.
+// Empty config admits all the transactions.
+type Config struct {
+	// ExemptLocals is true if local transactions (including the ones submitted via RPC) aren't checked
+	ExemptLocals bool
+	// Exempt are senders which aren't checked by the policy
+	Exempt []common.Address `toml:",omitempty"`
+	// DenySenders are senders whose transactions are rejected
+	DenySenders []common.Address `toml:",omitempty"`
+	// DenyRecipients are destinations whose transactions are rejected
+	DenyRecipients []common.Address `toml:",omitempty"`
+	// RateLimits limit the rate of transactions to specific destinations
+	RateLimits []RateLimit `toml:",omitempty"`
+	// MinTips require a higher tip for specific senders, destinations or methods
+	MinTips []MinTip `toml:",omitempty"`
+}
+
+// RateLimit limits the rate of transactions to a destination
+type RateLimit struct {
+	To common.Address
+	// TxsPerSecond is the average number of admitted transactions per second
+	TxsPerSecond float64
+	// Burst is the max number of transactions admitted at once
+	Burst uint64
+}
+
+// MinTip requires a min gas tip for the matching transactions.
+// Empty fields match any transaction.
+type MinTip struct {
+	From *common.Address `toml:",omitempty"`
+	To   *common.Address `toml:",omitempty"`
+	// Selector is the 4-byte method selector of the call, e.g. 0xa9059cbb
+	Selector string `toml:",omitempty"`
+	// Tip is the min gas tip in wei
+	Tip uint64
+}
+
+func parseSelector(s string) ([4]byte, error) {
+	selector := [4]byte{}
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return selector, err
+	}
+	if len(b) != len(selector) {
+		return selector, errors.New("selector has to be 4 bytes")
+	}
+	copy(selector[:], b)
+	return selector, nil
+}
+
+// Validate checks the config
+func (c *Config) Validate() error {
+	for _, l := range c.RateLimits {
+		if l.TxsPerSecond <= 0 {
+			return fmt.Errorf("rate limit of %s: TxsPerSecond has to be positive", l.To.Hex())
+		}
+		if l.Burst == 0 {
+			return fmt.Errorf("rate limit of %s: Burst has to be positive", l.To.Hex())
+		}
+	}
+	for i, t := range c.MinTips {
+		if len(t.Selector) == 0 {
+			continue
+		}
+		if _, err := parseSelector(t.Selector); err != nil {
+			return fmt.Errorf("min tip #%d: invalid selector %s: %v", i, t.Selector, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+// Package txpolicy implements a rule-based txpool admission policy of the node operator.
+package txpolicy
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RejectedErrorCode is the JSON-RPC error code of transactions rejected by the policy
+const RejectedErrorCode = -32003
+
+// RejectedError is returned for transactions rejected by the policy
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return "transaction rejected by node policy: " + e.Reason
+}
+
+// ErrorCode returns the JSON-RPC error code
+func (e *RejectedError) ErrorCode() int {
+	return RejectedErrorCode
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type minTip struct {
+	from     *common.Address
+	to       *common.Address
+	selector *[4]byte
+	tip      *big.Int
+}
+
+type rules struct {
+	cfg        Config
+	exempt     map[common.Address]bool
+	denyFrom   map[common.Address]bool
+	denyTo     map[common.Address]bool
+	rateLimits map[common.Address]RateLimit
+	minTips    []minTip
+}
+
+// Policy evaluates the rules of the config. It's safe for concurrent use.
+type Policy struct {
+	rules   *rules
+	buckets map[common.Address]*bucket
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+func toSet(addrs []common.Address) map[common.Address]bool {
+	set := make(map[common.Address]bool, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = true
+	}
+	return set
+}
+
+func compile(cfg Config) (*rules, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	r := &rules{
+		cfg:        cfg,
+		exempt:     toSet(cfg.Exempt),
+		denyFrom:   toSet(cfg.DenySenders),
+		denyTo:     toSet(cfg.DenyRecipients),
+		rateLimits: make(map[common.Address]RateLimit, len(cfg.RateLimits)),
+	}
+	for _, l := range cfg.RateLimits {
+		r.rateLimits[l.To] = l
+	}
+	for _, t := range cfg.MinTips {
+		mt := minTip{
+			from: t.From,
+			to:   t.To,
+			tip:  new(big.Int).SetUint64(t.Tip),
+		}
+		if len(t.Selector) != 0 {
+			selector, _ := parseSelector(t.Selector)
+			mt.selector = &selector
+		}
+		r.minTips = append(r.minTips, mt)
+	}
+	return r, nil
+}
+
+// New creates a policy from the config
+func New(cfg Config) (*Policy, error) {
+	r, err := compile(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Policy{
+		rules:   r,
+		buckets: make(map[common.Address]*bucket),
+		now:     time.Now,
+	}, nil
+}
+
+// Reload replaces the policy rules. The rate limits of destinations are reset.
+// The current rules are kept if the config is invalid.
+func (p *Policy) Reload(cfg Config) error {
+	r, err := compile(cfg)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rules = r
+	p.buckets = make(map[common.Address]*bucket)
+	return nil
+}
+
+// Config returns the current config of the policy
+func (p *Policy) Config() Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rules.cfg
+}
+
+func (m *minTip) matches(tx *types.Transaction, from common.Address) bool {
+	if m.from != nil && *m.from != from {
+		return false
+	}
+	if m.to != nil && (tx.To() == nil || *m.to != *tx.To()) {
+		return false
+	}
+	if m.selector != nil && (len(tx.Data()) < len(m.selector) || !bytes.Equal(m.selector[:], tx.Data()[:len(m.selector)])) {
+		return false
+	}
+	return true
+}
+
+// refill returns the destination bucket with the tokens accumulated since the last update
+func (p *Policy) refill(to common.Address, limit RateLimit) *bucket {
+	now := p.now()
+	b := p.buckets[to]
+	if b == nil {
+		b = &bucket{
+			tokens:  float64(limit.Burst),
+			updated: now,
+		}
+		p.buckets[to] = b
+	}
+	if now.After(b.updated) {
+		b.tokens += now.Sub(b.updated).Seconds() * limit.TxsPerSecond
+		if b.tokens > float64(limit.Burst) {
+			b.tokens = float64(limit.Burst)
+		}
+		b.updated = now
+	}
+	return b
+}
+
+func (r *rules) exempted(from common.Address, local bool) bool {
+	return (local && r.cfg.ExemptLocals) || r.exempt[from]
+}
+
+// Check returns an error if the transaction must be rejected.
+// The tips are compared after subtracting the base fee, which is the minimum gas price in Opera.
+// The rate limits aren't consumed until the transaction is Accepted.
+// It implements evmcore.TxPolicy.
+func (p *Policy) Check(tx *types.Transaction, from common.Address, local bool, baseFee *big.Int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	r := p.rules
+	if r.exempted(from, local) {
+		return nil
+	}
+	if r.denyFrom[from] {
+		return &RejectedError{fmt.Sprintf("sender %s is denied", from.Hex())}
+	}
+	if tx.To() != nil && r.denyTo[*tx.To()] {
+		return &RejectedError{fmt.Sprintf("recipient %s is denied", tx.To().Hex())}
+	}
+	if len(r.minTips) != 0 {
+		tip, err := tx.EffectiveGasTip(baseFee)
+		if err != nil {
+			tip = new(big.Int)
+		}
+		for i := range r.minTips {
+			mt := &r.minTips[i]
+			if mt.matches(tx, from) && tip.Cmp(mt.tip) < 0 {
+				return &RejectedError{fmt.Sprintf("gas tip is below the required %s wei", mt.tip.String())}
+			}
+		}
+	}
+	if tx.To() != nil {
+		if limit, ok := r.rateLimits[*tx.To()]; ok && p.refill(*tx.To(), limit).tokens < 1 {
+			return &RejectedError{fmt.Sprintf("rate limit of recipient %s is exceeded", tx.To().Hex())}
+		}
+	}
+	return nil
+}
+
+// Accepted takes a token from the rate limit of the transaction recipient.
+// It's called after the transaction passed the Check and was added into the pool,
+// so the transactions rejected by the pool don't consume the rate.
+// It implements evmcore.TxPolicy.
+func (p *Policy) Accepted(tx *types.Transaction, from common.Address, local bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	r := p.rules
+	if r.exempted(from, local) || tx.To() == nil {
+		return
+	}
+	if limit, ok := r.rateLimits[*tx.To()]; ok {
+		p.refill(*tx.To(), limit).tokens--
+	}
+}
@@ -0,0 +1,170 @@
+package txpolicy
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice   = common.Address{1}
+	bob     = common.Address{2}
+	token   = common.Address{3}
+	faucet  = common.Address{4}
+	transfr = hexutil.MustDecode("0xa9059cbb")
+)
+
+func newTx(to *common.Address, tip int64, data []byte) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		To:        to,
+		Gas:       100000,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(tip + 1000),
+		Data:      data,
+	})
+}
+
+func newLegacyTx(to *common.Address, gasPrice int64, data []byte) *types.Transaction {
+	return types.NewTransaction(0, *to, new(big.Int), 100000, big.NewInt(gasPrice), data)
+}
+
+// admit checks the transaction and accepts it if it passed, like the txpool does
+func admit(p *Policy, tx *types.Transaction, from common.Address) error {
+	if err := p.Check(tx, from, false, nil); err != nil {
+		return err
+	}
+	p.Accepted(tx, from, false)
+	return nil
+}
+
+func requireRejected(t *testing.T, err error) {
+	require.Error(t, err)
+	var rejected *RejectedError
+	require.True(t, errors.As(err, &rejected))
+	require.Equal(t, RejectedErrorCode, rejected.ErrorCode())
+}
+
+func TestPolicyDenyLists(t *testing.T) {
+	require := require.New(t)
+
+	p, err := New(Config{
+		Exempt:         []common.Address{bob},
+		DenySenders:    []common.Address{alice, bob},
+		DenyRecipients: []common.Address{token},
+	})
+	require.NoError(err)
+
+	requireRejected(t, p.Check(newTx(&faucet, 0, nil), alice, false, nil))
+	requireRejected(t, p.Check(newTx(&token, 0, nil), faucet, false, nil))
+	require.NoError(p.Check(newTx(&faucet, 0, nil), faucet, false, nil))
+	require.NoError(p.Check(newTx(nil, 0, nil), faucet, false, nil))
+	// exempt senders aren't checked
+	require.NoError(p.Check(newTx(&token, 0, nil), bob, false, nil))
+	// local transactions are checked unless ExemptLocals is set
+	requireRejected(t, p.Check(newTx(&faucet, 0, nil), alice, true, nil))
+	cfg := p.Config()
+	cfg.ExemptLocals = true
+	require.NoError(p.Reload(cfg))
+	require.NoError(p.Check(newTx(&faucet, 0, nil), alice, true, nil))
+	requireRejected(t, p.Check(newTx(&faucet, 0, nil), alice, false, nil))
+}
+
+func TestPolicyMinTips(t *testing.T) {
+	require := require.New(t)
+
+	p, err := New(Config{
+		MinTips: []MinTip{
+			{To: &token, Selector: "0xa9059cbb", Tip: 100},
+			{From: &alice, Tip: 10},
+		},
+	})
+	require.NoError(err)
+
+	call := append(append([]byte{}, transfr...), 0xff)
+	requireRejected(t, p.Check(newTx(&token, 99, call), faucet, false, nil))
+	require.NoError(p.Check(newTx(&token, 100, call), faucet, false, nil))
+	// other methods and destinations don't match the selector rule
+	require.NoError(p.Check(newTx(&token, 0, []byte{0xa9, 0x05}), faucet, false, nil))
+	require.NoError(p.Check(newTx(&faucet, 0, call), faucet, false, nil))
+	// sender rule
+	requireRejected(t, p.Check(newTx(&faucet, 9, nil), alice, false, nil))
+	require.NoError(p.Check(newTx(&faucet, 10, nil), alice, false, nil))
+
+	// the tip is what remains after the base fee
+	baseFee := big.NewInt(1000)
+	require.NoError(p.Check(newTx(&token, 100, call), faucet, false, baseFee))
+	requireRejected(t, p.Check(newTx(&token, 100, call), faucet, false, big.NewInt(1050)))
+	requireRejected(t, p.Check(newTx(&token, 100, call), faucet, false, big.NewInt(2000)))
+	// legacy transactions pay the whole gas price above the base fee as the tip
+	requireRejected(t, p.Check(newLegacyTx(&token, 1099, call), faucet, false, baseFee))
+	requireRejected(t, p.Check(newLegacyTx(&faucet, 1009, nil), alice, false, baseFee))
+	require.NoError(p.Check(newLegacyTx(&faucet, 1010, nil), alice, false, baseFee))
+}
+
+func TestPolicyRateLimits(t *testing.T) {
+	require := require.New(t)
+
+	p, err := New(Config{
+		RateLimits:     []RateLimit{{To: faucet, TxsPerSecond: 0.5, Burst: 2}},
+		DenyRecipients: []common.Address{token},
+	})
+	require.NoError(err)
+	now := time.Unix(1000, 0)
+	p.now = func() time.Time { return now }
+
+	require.NoError(admit(p, newTx(&faucet, 0, nil), alice))
+	require.NoError(admit(p, newTx(&faucet, 0, nil), bob))
+	requireRejected(t, admit(p, newTx(&faucet, 0, nil), alice))
+	// other destinations aren't limited
+	require.NoError(admit(p, newTx(&bob, 0, nil), alice))
+
+	now = now.Add(time.Second)
+	requireRejected(t, admit(p, newTx(&faucet, 0, nil), alice))
+	now = now.Add(time.Second)
+	require.NoError(admit(p, newTx(&faucet, 0, nil), alice))
+
+	// the bucket doesn't grow beyond the burst
+	now = now.Add(time.Hour)
+	require.NoError(admit(p, newTx(&faucet, 0, nil), alice))
+	require.NoError(admit(p, newTx(&faucet, 0, nil), alice))
+	requireRejected(t, admit(p, newTx(&faucet, 0, nil), alice))
+
+	// checked transactions don't consume the rate until they are accepted
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		require.NoError(p.Check(newTx(&faucet, 0, nil), alice, false, nil))
+	}
+	require.NoError(admit(p, newTx(&faucet, 0, nil), alice))
+	require.NoError(admit(p, newTx(&faucet, 0, nil), alice))
+	requireRejected(t, p.Check(newTx(&faucet, 0, nil), alice, false, nil))
+
+	// reload resets the buckets
+	require.NoError(p.Reload(p.Config()))
+	require.NoError(admit(p, newTx(&faucet, 0, nil), alice))
+}
+
+func TestPolicyReload(t *testing.T) {
+	require := require.New(t)
+
+	p, err := New(Config{})
+	require.NoError(err)
+	require.NoError(p.Check(newTx(&token, 0, nil), alice, false, nil))
+
+	require.NoError(p.Reload(Config{DenySenders: []common.Address{alice}}))
+	requireRejected(t, p.Check(newTx(&token, 0, nil), alice, false, nil))
+
+	// invalid config keeps the current rules
+	require.Error(p.Reload(Config{MinTips: []MinTip{{Selector: "0x01", Tip: 1}}}))
+	require.Error(p.Reload(Config{RateLimits: []RateLimit{{To: token, TxsPerSecond: 1}}}))
+	requireRejected(t, p.Check(newTx(&token, 0, nil), alice, false, nil))
+	require.Equal([]common.Address{alice}, p.Config().DenySenders)
+
+	_, err = New(Config{RateLimits: []RateLimit{{To: token, Burst: 1}}})
+	require.Error(err)
+}
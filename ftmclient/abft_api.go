@@ -0,0 +1,196 @@
+package ftmclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+)
+
+// Validator is a validator of an epoch.
+type Validator struct {
+	ID     idx.ValidatorID
+	Weight *big.Int
+	PubKey validatorpk.PubKey
+}
+
+// ValidatorEpochMetrics is a validator of an epoch along with its performance in the epoch.
+// The metrics which aren't recorded for the epoch are nil.
+type ValidatorEpochMetrics struct {
+	Validator
+	Uptime        *inter.Timestamp
+	OfflineBlocks *idx.Block
+	OfflineTime   *inter.Timestamp
+	OriginatedFee *big.Int
+}
+
+// Downtime is a validator's downtime.
+type Downtime struct {
+	OfflineBlocks idx.Block
+	OfflineTime   inter.Timestamp
+}
+
+// EpochInfo is the timing, fees and validators performance of an epoch.
+// The values which aren't known or recorded for the epoch are nil.
+type EpochInfo struct {
+	Epoch       idx.Epoch
+	Start       inter.Timestamp
+	End         *inter.Timestamp
+	FirstBlock  idx.Block
+	LastBlock   *idx.Block
+	GasUsed     *uint64
+	TotalFee    *big.Int
+	TotalWeight *big.Int
+	Validators  map[idx.ValidatorID]*ValidatorEpochMetrics
+}
+
+type rpcValidator struct {
+	Weight        *hexutil.Big       `json:"weight"`
+	PubKey        validatorpk.PubKey `json:"pubkey"`
+	Uptime        *hexutil.Uint64    `json:"uptime"`
+	OfflineBlocks *hexutil.Uint64    `json:"offlineBlocks"`
+	OfflineTime   *hexutil.Uint64    `json:"offlineTime"`
+	OriginatedFee *hexutil.Big       `json:"originatedFee"`
+}
+
+func (v *rpcValidator) validator(id hexutil.Uint64) Validator {
+	return Validator{
+		ID:     idx.ValidatorID(id),
+		Weight: v.Weight.ToInt(),
+		PubKey: v.PubKey,
+	}
+}
+
+func toValidators(raw map[hexutil.Uint64]*rpcValidator) map[idx.ValidatorID]*Validator {
+	res := make(map[idx.ValidatorID]*Validator, len(raw))
+	for id, v := range raw {
+		validator := v.validator(id)
+		res[idx.ValidatorID(id)] = &validator
+	}
+	return res
+}
+
+type rpcEpochInfo struct {
+	Epoch       hexutil.Uint64                   `json:"epoch"`
+	Start       hexutil.Uint64                   `json:"start"`
+	End         *hexutil.Uint64                  `json:"end"`
+	FirstBlock  hexutil.Uint64                   `json:"firstBlock"`
+	LastBlock   *hexutil.Uint64                  `json:"lastBlock"`
+	GasUsed     *hexutil.Uint64                  `json:"gasUsed"`
+	TotalFee    *hexutil.Big                     `json:"totalFee"`
+	TotalWeight *hexutil.Big                     `json:"totalWeight"`
+	Validators  map[hexutil.Uint64]*rpcValidator `json:"validators"`
+}
+
+// GetValidators returns the validators of the epoch.
+// * When epoch is nil the validators of the current epoch are returned.
+// * When epoch is -1 the validators of the current epoch are returned with the weights updated within the epoch.
+func (ec *Client) GetValidators(ctx context.Context, epoch *big.Int) (map[idx.ValidatorID]*Validator, error) {
+	var raw map[hexutil.Uint64]*rpcValidator
+	err := ec.c.CallContext(ctx, &raw, "abft_getValidators", toBlockNumArg(epoch))
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+
+	return toValidators(raw), nil
+}
+
+// GetDowntime returns the validator's downtime.
+func (ec *Client) GetDowntime(ctx context.Context, validatorID idx.ValidatorID) (*Downtime, error) {
+	var raw struct {
+		OfflineBlocks hexutil.Uint64 `json:"offlineBlocks"`
+		OfflineTime   hexutil.Uint64 `json:"offlineTime"`
+	}
+	err := ec.c.CallContext(ctx, &raw, "abft_getDowntime", hexutil.Uint(validatorID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Downtime{
+		OfflineBlocks: idx.Block(raw.OfflineBlocks),
+		OfflineTime:   inter.Timestamp(raw.OfflineTime),
+	}, nil
+}
+
+// GetEpochUptime returns the validator's uptime in the current epoch.
+func (ec *Client) GetEpochUptime(ctx context.Context, validatorID idx.ValidatorID) (inter.Timestamp, error) {
+	var raw hexutil.Uint64
+	err := ec.c.CallContext(ctx, &raw, "abft_getEpochUptime", hexutil.Uint(validatorID))
+	if err != nil {
+		return 0, err
+	}
+
+	return inter.Timestamp(raw), nil
+}
+
+// GetOriginatedEpochFee returns the validator's fee originated in the current epoch.
+func (ec *Client) GetOriginatedEpochFee(ctx context.Context, validatorID idx.ValidatorID) (*big.Int, error) {
+	var raw *hexutil.Big
+	err := ec.c.CallContext(ctx, &raw, "abft_getOriginatedEpochFee", hexutil.Uint(validatorID))
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+
+	return raw.ToInt(), nil
+}
+
+// GetEpoch returns the timing, fees and validators performance of the epoch.
+// * When epoch is nil the latest sealed epoch is returned.
+// * When epoch is -1 the current epoch is returned, with the metrics collected so far.
+func (ec *Client) GetEpoch(ctx context.Context, epoch *big.Int) (*EpochInfo, error) {
+	var raw *rpcEpochInfo
+	err := ec.c.CallContext(ctx, &raw, "abft_getEpoch", toBlockNumArg(epoch))
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+
+	info := &EpochInfo{
+		Epoch:       idx.Epoch(raw.Epoch),
+		Start:       inter.Timestamp(raw.Start),
+		FirstBlock:  idx.Block(raw.FirstBlock),
+		GasUsed:     (*uint64)(raw.GasUsed),
+		TotalFee:    raw.TotalFee.ToInt(),
+		TotalWeight: raw.TotalWeight.ToInt(),
+		Validators:  make(map[idx.ValidatorID]*ValidatorEpochMetrics, len(raw.Validators)),
+	}
+	if raw.End != nil {
+		end := inter.Timestamp(*raw.End)
+		info.End = &end
+	}
+	if raw.LastBlock != nil {
+		lastBlock := idx.Block(*raw.LastBlock)
+		info.LastBlock = &lastBlock
+	}
+	for id, v := range raw.Validators {
+		m := &ValidatorEpochMetrics{
+			Validator:     v.validator(id),
+			OriginatedFee: v.OriginatedFee.ToInt(),
+		}
+		if v.Uptime != nil {
+			uptime := inter.Timestamp(*v.Uptime)
+			m.Uptime = &uptime
+		}
+		if v.OfflineBlocks != nil {
+			blocks := idx.Block(*v.OfflineBlocks)
+			m.OfflineBlocks = &blocks
+		}
+		if v.OfflineTime != nil {
+			period := inter.Timestamp(*v.OfflineTime)
+			m.OfflineTime = &period
+		}
+		info.Validators[idx.ValidatorID(id)] = m
+	}
+
+	return info, nil
+}
@@ -0,0 +1,235 @@
+package ftmclient
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/ethapi"
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+)
+
+var testPubKey = validatorpk.PubKey{
+	Type: validatorpk.Types.Secp256k1,
+	Raw:  common.FromHex("0x04c5a3e2ab3b4b1a6df6b5bdc4a1e8f1d23f0a4a4e4cdbd3c8b3ee6ed0c5f4c7d1a1e3b7f0d9a4c6b2e5f8a7c1d3e6b9f2a5c8d1e4f7a0b3c6d9e2f5a8b1c4d7"),
+}
+
+type testAbftAPI struct{}
+
+func (testAbftAPI) GetValidators(epoch rpc.BlockNumber) map[hexutil.Uint64]interface{} {
+	if epoch > 2 {
+		return nil
+	}
+	return map[hexutil.Uint64]interface{}{
+		1: map[string]interface{}{
+			"weight": (*hexutil.Big)(big.NewInt(100)),
+			"pubkey": testPubKey.String(),
+		},
+	}
+}
+
+func (testAbftAPI) GetEpoch(epoch rpc.BlockNumber) map[string]interface{} {
+	return map[string]interface{}{
+		"epoch":       hexutil.Uint64(2),
+		"start":       hexutil.Uint64(1000),
+		"end":         nil,
+		"firstBlock":  hexutil.Uint64(5),
+		"lastBlock":   nil,
+		"gasUsed":     hexutil.Uint64(21000),
+		"totalFee":    (*hexutil.Big)(big.NewInt(7)),
+		"totalWeight": (*hexutil.Big)(big.NewInt(100)),
+		"validators": map[hexutil.Uint64]interface{}{
+			1: map[string]interface{}{
+				"weight":        (*hexutil.Big)(big.NewInt(100)),
+				"pubkey":        testPubKey.String(),
+				"uptime":        hexutil.Uint64(500),
+				"offlineBlocks": hexutil.Uint64(1),
+				"offlineTime":   hexutil.Uint64(3),
+				"originatedFee": (*hexutil.Big)(big.NewInt(7)),
+			},
+		},
+	}
+}
+
+// testTxPoolBackend serves the pool of a single transaction to the real txpool API
+type testTxPoolBackend struct {
+	ethapi.Backend
+	tx   *types.Transaction
+	from common.Address
+}
+
+func (b testTxPoolBackend) CurrentBlock() *evmcore.EvmBlock {
+	return evmcore.NewEvmBlock(&evmcore.EvmHeader{Number: big.NewInt(1), BaseFee: big.NewInt(1)}, nil)
+}
+
+func (b testTxPoolBackend) TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions) {
+	return map[common.Address]types.Transactions{b.from: {b.tx}}, map[common.Address]types.Transactions{}
+}
+
+func (b testTxPoolBackend) Stats() (int, int) {
+	return 1, 0
+}
+
+func (b testTxPoolBackend) TxPoolTxInfo(txHash common.Hash) *evmcore.TxInfo {
+	if txHash != b.tx.Hash() {
+		return nil
+	}
+	return &evmcore.TxInfo{
+		Tx:                b.tx,
+		From:              b.from,
+		Status:            evmcore.TxStatusQueued,
+		StateNonce:        3,
+		Pending:           []uint64{3},
+		Queued:            []uint64{5},
+		NonceGap:          1,
+		AccountSlots:      16,
+		AccountQueue:      64,
+		MinTip:            big.NewInt(0),
+		ReplacementFeeCap: big.NewInt(2),
+		ReplacementTipCap: big.NewInt(2),
+	}
+}
+
+func (b testTxPoolBackend) MinGasPrice() *big.Int {
+	return big.NewInt(1)
+}
+
+func (b testTxPoolBackend) EffectiveMinGasPrice(ctx context.Context) *big.Int {
+	return big.NewInt(1)
+}
+
+func (b testTxPoolBackend) SuggestGasTipCap(ctx context.Context, certainty uint64) *big.Int {
+	return big.NewInt(0)
+}
+
+func (b testTxPoolBackend) TxOrigination(tx *types.Transaction) []ethapi.TxOrigination {
+	nextTurn := time.Unix(1000, 0)
+	return []ethapi.TxOrigination{{
+		Validator: 1,
+		Reason:    "not the emitter's turn",
+		Turn:      2,
+		NextTurn:  &nextTurn,
+	}}
+}
+
+type testDagAPI struct{}
+
+func (testDagAPI) NewEpochs(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		_ = notifier.Notify(rpcSub.ID, map[string]interface{}{
+			"epoch": hexutil.Uint64(3),
+			"start": hexutil.Uint64(10),
+			"end":   hexutil.Uint64(20),
+			"validators": map[hexutil.Uint64]interface{}{
+				2: map[string]interface{}{
+					"weight": (*hexutil.Big)(big.NewInt(50)),
+					"pubkey": testPubKey.String(),
+				},
+			},
+		})
+	}()
+	return rpcSub, nil
+}
+
+func newTestClient(t *testing.T, pool testTxPoolBackend) *Client {
+	server := rpc.NewServer()
+	t.Cleanup(server.Stop)
+	require.NoError(t, server.RegisterName("abft", testAbftAPI{}))
+	require.NoError(t, server.RegisterName("txpool", ethapi.NewPublicTxPoolAPI(pool)))
+	require.NoError(t, server.RegisterName("dag", testDagAPI{}))
+	return NewClient(rpc.DialInProc(server))
+}
+
+func TestClientTypedMethods(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	tx, err := types.SignNewTx(key, types.NewLondonSigner(big.NewInt(4003)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(4003),
+		Nonce:     5,
+		To:        &common.Address{2},
+		Gas:       21000,
+		GasFeeCap: big.NewInt(2),
+		GasTipCap: big.NewInt(1),
+	})
+	require.NoError(err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	client := newTestClient(t, testTxPoolBackend{tx: tx, from: from})
+
+	validators, err := client.GetValidators(ctx, big.NewInt(2))
+	require.NoError(err)
+	require.Equal(map[idx.ValidatorID]*Validator{
+		1: {ID: 1, Weight: big.NewInt(100), PubKey: testPubKey},
+	}, validators)
+
+	info, err := client.GetEpoch(ctx, big.NewInt(2))
+	require.NoError(err)
+	require.Equal(idx.Epoch(2), info.Epoch)
+	require.Equal(inter.Timestamp(1000), info.Start)
+	require.Nil(info.End)
+	require.Nil(info.LastBlock)
+	require.Equal(uint64(21000), *info.GasUsed)
+	require.Equal(big.NewInt(7), info.TotalFee)
+	v := info.Validators[1]
+	require.NotNil(v)
+	require.Equal(testPubKey, v.PubKey)
+	require.Equal(inter.Timestamp(500), *v.Uptime)
+	require.Equal(idx.Block(1), *v.OfflineBlocks)
+	require.Equal(inter.Timestamp(3), *v.OfflineTime)
+	require.Equal(big.NewInt(7), v.OriginatedFee)
+
+	pending, queued, err := client.TxPoolContent(ctx)
+	require.NoError(err)
+	require.Empty(queued)
+	require.Equal(tx.Hash(), pending[from][5].Hash())
+
+	poolStatus, err := client.TxPoolStatus(ctx)
+	require.NoError(err)
+	require.Equal(&TxPoolStatus{Pending: 1}, poolStatus)
+
+	status, err := client.TxPoolTxStatus(ctx, tx.Hash())
+	require.NoError(err)
+	require.Equal(from, status.From)
+	require.Equal(uint64(5), status.Nonce)
+	require.Equal("queued", status.Status)
+	require.Equal([]uint64{3}, status.SenderPending)
+	require.Equal([]uint64{5}, status.SenderQueued)
+	require.Equal(uint(1), status.NoncePosition)
+	require.Equal(big.NewInt(2), status.GasPrice)
+	require.Equal(big.NewInt(2), status.ReplacementGasFeeCap)
+	require.Equal([]string{"1 nonces are missing before the transaction"}, status.Problems)
+	nextTurn := time.Unix(1000, 0)
+	require.Equal([]TxEmitterStatus{{
+		Validator: 1,
+		Reason:    "not the emitter's turn",
+		Turn:      2,
+		NextTurn:  &nextTurn,
+	}}, status.Emitters)
+
+	_, err = client.TxPoolTxStatus(ctx, common.Hash{1})
+	require.Error(err)
+
+	epochs := make(chan *SealedEpoch)
+	sub, err := client.SubscribeNewEpochs(ctx, epochs)
+	require.NoError(err)
+	defer sub.Unsubscribe()
+	sealed := <-epochs
+	require.Equal(idx.Epoch(3), sealed.Epoch)
+	require.Equal(inter.Timestamp(20), sealed.End)
+	require.Equal(big.NewInt(50), sealed.Validators[2].Weight)
+}
@@ -2,9 +2,11 @@ package ftmclient
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -52,8 +54,7 @@ func (ec *Client) GetEventPayload(ctx context.Context, h hash.Event, inclTx bool
 }
 
 // GetHeads returns IDs of all the epoch events with no descendants.
-// * When epoch is -2 the heads for latest epoch are returned.
-// * When epoch is -1 the heads for latest sealed epoch are returned.
+// Only the heads of the current epoch are available, it's requested when epoch is -1.
 func (ec *Client) GetHeads(ctx context.Context, epoch *big.Int) (hash.Events, error) {
 	var raw []interface{}
 	err := ec.c.CallContext(ctx, &raw, "dag_getHeads", toBlockNumArg(epoch))
@@ -65,8 +66,7 @@ func (ec *Client) GetHeads(ctx context.Context, epoch *big.Int) (hash.Events, er
 }
 
 // GetEpochStats returns epoch statistics.
-// * When epoch is -2 the statistics for latest epoch is returned.
-// * When epoch is -1 the statistics for latest sealed epoch is returned.
+// Only the statistics of the latest sealed epoch are available, it's requested when epoch is nil.
 func (ec *Client) GetEpochStats(ctx context.Context, epoch *big.Int) (map[string]interface{}, error) {
 	var raw map[string]interface{}
 	err := ec.c.CallContext(ctx, &raw, "dag_getEpochStats", toBlockNumArg(epoch))
@@ -79,6 +79,66 @@ func (ec *Client) GetEpochStats(ctx context.Context, epoch *big.Int) (map[string
 	return raw, nil
 }
 
+// EventPayload is a Lachesis event along with its transaction hashes.
+type EventPayload struct {
+	inter.EventI
+	Txs []common.Hash
+}
+
+// UnmarshalJSON decodes the event from the dag_getEventPayload RPC output.
+func (e *EventPayload) UnmarshalJSON(input []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+	var txs struct {
+		Transactions []common.Hash `json:"transactions"`
+	}
+	if err := json.Unmarshal(input, &txs); err != nil {
+		return err
+	}
+	e.EventI = inter.RPCUnmarshalEvent(raw)
+	e.Txs = txs.Transactions
+	return nil
+}
+
+// SealedEpoch is the timing and validators of a sealed epoch.
+type SealedEpoch struct {
+	Epoch      idx.Epoch
+	Start      inter.Timestamp
+	End        inter.Timestamp
+	Validators map[idx.ValidatorID]*Validator
+}
+
+// UnmarshalJSON decodes the epoch from the dag_subscribe newEpochs notification.
+func (e *SealedEpoch) UnmarshalJSON(input []byte) error {
+	var raw struct {
+		Epoch      hexutil.Uint64                   `json:"epoch"`
+		Start      hexutil.Uint64                   `json:"start"`
+		End        hexutil.Uint64                   `json:"end"`
+		Validators map[hexutil.Uint64]*rpcValidator `json:"validators"`
+	}
+	if err := json.Unmarshal(input, &raw); err != nil {
+		return err
+	}
+	e.Epoch = idx.Epoch(raw.Epoch)
+	e.Start = inter.Timestamp(raw.Start)
+	e.End = inter.Timestamp(raw.End)
+	e.Validators = toValidators(raw.Validators)
+	return nil
+}
+
+// SubscribeNewEvents subscribes to notifications about the events connected to the DAG.
+func (ec *Client) SubscribeNewEvents(ctx context.Context, ch chan<- *EventPayload) (ethereum.Subscription, error) {
+	return ec.c.Subscribe(ctx, "dag", ch, "newEvents")
+}
+
+// SubscribeNewEpochs subscribes to notifications about the sealed epochs.
+func (ec *Client) SubscribeNewEpochs(ctx context.Context, ch chan<- *SealedEpoch) (ethereum.Subscription, error) {
+	return ec.c.Subscribe(ctx, "dag", ch, "newEpochs")
+}
+
+// toBlockNumArg converts nil to "latest" and -1 to "pending", like the ethclient does for blocks
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
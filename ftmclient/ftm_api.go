@@ -0,0 +1,66 @@
+package ftmclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+// CurrentEpoch returns the current epoch number.
+func (ec *Client) CurrentEpoch(ctx context.Context) (idx.Epoch, error) {
+	var raw hexutil.Uint64
+	err := ec.c.CallContext(ctx, &raw, "ftm_currentEpoch")
+	if err != nil {
+		return 0, err
+	}
+
+	return idx.Epoch(raw), nil
+}
+
+// GetRules returns the network rules of the epoch.
+// When epoch is nil or -1 the rules of the current epoch are returned.
+func (ec *Client) GetRules(ctx context.Context, epoch *big.Int) (*opera.Rules, error) {
+	var rules *opera.Rules
+	err := ec.c.CallContext(ctx, &rules, "ftm_getRules", toBlockNumArg(epoch))
+	if err != nil {
+		return nil, err
+	} else if rules == nil {
+		return nil, ethereum.NotFound
+	}
+
+	return rules, nil
+}
+
+// GetEpochBlock returns the last block of the previous epoch, i.e. the block height at the epoch start.
+// * When epoch is nil the block for the current epoch is returned.
+// * When epoch is -1 the latest block is returned.
+func (ec *Client) GetEpochBlock(ctx context.Context, epoch *big.Int) (idx.Block, error) {
+	var raw hexutil.Uint64
+	err := ec.c.CallContext(ctx, &raw, "ftm_getEpochBlock", toBlockNumArg(epoch))
+	if err != nil {
+		return 0, err
+	}
+
+	return idx.Block(raw), nil
+}
+
+// BlocksTransactionTimes returns the number of transactions per block time
+// within maxBlocks blocks until the untilBlock.
+func (ec *Client) BlocksTransactionTimes(ctx context.Context, untilBlock *big.Int, maxBlocks uint64) (map[uint64]uint, error) {
+	var raw map[hexutil.Uint64]hexutil.Uint
+	err := ec.c.CallContext(ctx, &raw, "debug_blocksTransactionTimes", toBlockNumArg(untilBlock), hexutil.Uint64(maxBlocks))
+	if err != nil {
+		return nil, err
+	}
+
+	times := make(map[uint64]uint, len(raw))
+	for t, n := range raw {
+		times[uint64(t)] = uint(n)
+	}
+	return times, nil
+}
@@ -0,0 +1,222 @@
+package ftmclient
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxPoolStatus is the number of pending and queued transactions in the pool.
+type TxPoolStatus struct {
+	Pending uint
+	Queued  uint
+}
+
+// TxPoolStatus returns the number of pending and queued transactions in the pool.
+func (ec *Client) TxPoolStatus(ctx context.Context) (*TxPoolStatus, error) {
+	var raw struct {
+		Pending hexutil.Uint `json:"pending"`
+		Queued  hexutil.Uint `json:"queued"`
+	}
+	err := ec.c.CallContext(ctx, &raw, "txpool_status")
+	if err != nil {
+		return nil, err
+	}
+
+	return &TxPoolStatus{
+		Pending: uint(raw.Pending),
+		Queued:  uint(raw.Queued),
+	}, nil
+}
+
+// TxEmitterStatus tells whether a local emitter would originate a pool transaction.
+type TxEmitterStatus struct {
+	Validator idx.ValidatorID
+	// Originate is true if the transaction would be originated into the next event
+	Originate bool
+	// Reason explains why the transaction isn't originated
+	Reason string
+	// Turn is the validator whose turn it is to originate the transaction, zero if unknown
+	Turn idx.ValidatorID
+	// NextTurn is the estimated start of the next emitter's turn, nil if unknown
+	NextTurn *time.Time
+}
+
+// TxStatus is the inclusion diagnostics of a pool transaction.
+type TxStatus struct {
+	Hash  common.Hash
+	From  common.Address
+	Nonce uint64
+	// Status is either "pending" or "queued"
+	Status string
+	Local  bool
+
+	StateNonce    uint64
+	NoncePosition uint
+	NonceGap      uint64
+	SenderPending []uint64
+	SenderQueued  []uint64
+	AccountSlots  uint64
+	AccountQueue  uint64
+
+	GasPrice             *big.Int
+	GasFeeCap            *big.Int
+	GasTipCap            *big.Int
+	MinGasPrice          *big.Int
+	EffectiveMinGasPrice *big.Int
+	SuggestedGasPrice    *big.Int
+	PoolMinTip           *big.Int
+
+	ReplacementGasFeeCap *big.Int
+	ReplacementGasTipCap *big.Int
+
+	Emitters []TxEmitterStatus
+	Problems []string
+}
+
+type rpcTxStatus struct {
+	Hash   common.Hash    `json:"hash"`
+	From   common.Address `json:"from"`
+	Nonce  hexutil.Uint64 `json:"nonce"`
+	Status string         `json:"status"`
+	Local  bool           `json:"local"`
+
+	StateNonce    hexutil.Uint64   `json:"stateNonce"`
+	NoncePosition hexutil.Uint     `json:"noncePosition"`
+	NonceGap      hexutil.Uint64   `json:"nonceGap"`
+	SenderPending []hexutil.Uint64 `json:"senderPending"`
+	SenderQueued  []hexutil.Uint64 `json:"senderQueued"`
+	AccountSlots  hexutil.Uint64   `json:"accountSlots"`
+	AccountQueue  hexutil.Uint64   `json:"accountQueue"`
+
+	GasPrice             *hexutil.Big `json:"gasPrice"`
+	GasFeeCap            *hexutil.Big `json:"maxFeePerGas"`
+	GasTipCap            *hexutil.Big `json:"maxPriorityFeePerGas"`
+	MinGasPrice          *hexutil.Big `json:"minGasPrice"`
+	EffectiveMinGasPrice *hexutil.Big `json:"effectiveMinGasPrice"`
+	SuggestedGasPrice    *hexutil.Big `json:"suggestedGasPrice"`
+	PoolMinTip           *hexutil.Big `json:"poolMinTip"`
+
+	ReplacementGasFeeCap *hexutil.Big `json:"replacementMaxFeePerGas"`
+	ReplacementGasTipCap *hexutil.Big `json:"replacementMaxPriorityFeePerGas"`
+
+	Emitters []struct {
+		Validator hexutil.Uint64  `json:"validator"`
+		Originate bool            `json:"originate"`
+		Reason    string          `json:"reason"`
+		Turn      *hexutil.Uint64 `json:"turn"`
+		NextTurn  *hexutil.Uint64 `json:"nextTurn"`
+	} `json:"emitters"`
+	Problems []string `json:"problems"`
+}
+
+// TxPoolTxStatus returns the inclusion diagnostics of the pool transaction,
+// i.e. why the transaction is queued or isn't originated by the local emitters.
+func (ec *Client) TxPoolTxStatus(ctx context.Context, txHash common.Hash) (*TxStatus, error) {
+	var raw *rpcTxStatus
+	err := ec.c.CallContext(ctx, &raw, "txpool_status", txHash)
+	if err != nil {
+		return nil, err
+	} else if raw == nil {
+		return nil, ethereum.NotFound
+	}
+
+	status := &TxStatus{
+		Hash:                 raw.Hash,
+		From:                 raw.From,
+		Nonce:                uint64(raw.Nonce),
+		Status:               raw.Status,
+		Local:                raw.Local,
+		StateNonce:           uint64(raw.StateNonce),
+		NoncePosition:        uint(raw.NoncePosition),
+		NonceGap:             uint64(raw.NonceGap),
+		SenderPending:        toUint64s(raw.SenderPending),
+		SenderQueued:         toUint64s(raw.SenderQueued),
+		AccountSlots:         uint64(raw.AccountSlots),
+		AccountQueue:         uint64(raw.AccountQueue),
+		GasPrice:             raw.GasPrice.ToInt(),
+		GasFeeCap:            raw.GasFeeCap.ToInt(),
+		GasTipCap:            raw.GasTipCap.ToInt(),
+		MinGasPrice:          raw.MinGasPrice.ToInt(),
+		EffectiveMinGasPrice: raw.EffectiveMinGasPrice.ToInt(),
+		SuggestedGasPrice:    raw.SuggestedGasPrice.ToInt(),
+		PoolMinTip:           raw.PoolMinTip.ToInt(),
+		ReplacementGasFeeCap: raw.ReplacementGasFeeCap.ToInt(),
+		ReplacementGasTipCap: raw.ReplacementGasTipCap.ToInt(),
+		Emitters:             make([]TxEmitterStatus, len(raw.Emitters)),
+		Problems:             raw.Problems,
+	}
+	for i, e := range raw.Emitters {
+		status.Emitters[i] = TxEmitterStatus{
+			Validator: idx.ValidatorID(e.Validator),
+			Originate: e.Originate,
+			Reason:    e.Reason,
+		}
+		if e.Turn != nil {
+			status.Emitters[i].Turn = idx.ValidatorID(*e.Turn)
+		}
+		if e.NextTurn != nil {
+			nextTurn := time.Unix(int64(*e.NextTurn), 0)
+			status.Emitters[i].NextTurn = &nextTurn
+		}
+	}
+
+	return status, nil
+}
+
+func toUint64s(raw []hexutil.Uint64) []uint64 {
+	res := make([]uint64, len(raw))
+	for i, n := range raw {
+		res[i] = uint64(n)
+	}
+	return res
+}
+
+// TxPoolContent returns the pending and queued transactions of the pool, grouped by sender and nonce.
+func (ec *Client) TxPoolContent(ctx context.Context) (pending, queued map[common.Address]map[uint64]*types.Transaction, err error) {
+	var raw struct {
+		Pending map[common.Address]map[uint64]*types.Transaction `json:"pending"`
+		Queued  map[common.Address]map[uint64]*types.Transaction `json:"queued"`
+	}
+	err = ec.c.CallContext(ctx, &raw, "txpool_content")
+	if err != nil {
+		return
+	}
+
+	return raw.Pending, raw.Queued, nil
+}
+
+// TxPoolContentFrom returns the pending and queued transactions of the sender in the pool, by nonce.
+func (ec *Client) TxPoolContentFrom(ctx context.Context, addr common.Address) (pending, queued map[uint64]*types.Transaction, err error) {
+	var raw struct {
+		Pending map[uint64]*types.Transaction `json:"pending"`
+		Queued  map[uint64]*types.Transaction `json:"queued"`
+	}
+	err = ec.c.CallContext(ctx, &raw, "txpool_contentFrom", addr)
+	if err != nil {
+		return
+	}
+
+	return raw.Pending, raw.Queued, nil
+}
+
+// TxPoolInspect returns the textual summaries of the pending and queued transactions of the pool,
+// grouped by sender and nonce.
+func (ec *Client) TxPoolInspect(ctx context.Context) (pending, queued map[common.Address]map[uint64]string, err error) {
+	var raw struct {
+		Pending map[common.Address]map[uint64]string `json:"pending"`
+		Queued  map[common.Address]map[uint64]string `json:"queued"`
+	}
+	err = ec.c.CallContext(ctx, &raw, "txpool_inspect")
+	if err != nil {
+		return
+	}
+
+	return raw.Pending, raw.Queued, nil
+}
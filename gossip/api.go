@@ -1,8 +1,13 @@
 package gossip
 
 import (
+	"errors"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 )
 
 // PublicEthereumAPI provides an API to access Ethereum-like information.
@@ -35,3 +40,41 @@ func (api *PublicEthereumAPI) Hashrate() hexutil.Uint64 {
 func (api *PublicEthereumAPI) ChainId() hexutil.Uint64 {
 	return hexutil.Uint64(api.s.store.GetRules().NetworkID)
 }
+
+// PrivateAdminAPI provides an API to manage the peer scores, the txpool admission policy and the live state pruning.
+type PrivateAdminAPI struct {
+	s *Service
+}
+
+// NewPrivateAdminAPI creates a new admin API for gossip.
+func NewPrivateAdminAPI(s *Service) *PrivateAdminAPI {
+	return &PrivateAdminAPI{s}
+}
+
+// PeerScores returns the scores of known peers, keyed by their enode IDs
+func (api *PrivateAdminAPI) PeerScores() map[string]peerscore.PeerScore {
+	return api.s.handler.peerScores.Snapshot()
+}
+
+// UnbanPeer removes the ban of a peer and resets its score. Returns false if the peer wasn't banned
+func (api *PrivateAdminAPI) UnbanPeer(id string) bool {
+	return api.s.handler.peerScores.Unban(id)
+}
+
+// ReloadTxPolicy re-reads the txpool admission policy from the config file and applies it
+func (api *PrivateAdminAPI) ReloadTxPolicy() error {
+	if api.s.txPolicyReloader == nil {
+		return errors.New("txpool admission policy reloading isn't supported")
+	}
+	return api.s.txPolicyReloader()
+}
+
+// StartStatePruning launches the online pruning of the EVM states which are older than the kept blocks
+func (api *PrivateAdminAPI) StartStatePruning() error {
+	return api.s.StartStatePruning()
+}
+
+// StatePruningStatus returns the progress of the online EVM state pruning
+func (api *PrivateAdminAPI) StatePruningStatus() evmstore.LivePruningStatus {
+	return api.s.store.evm.LivePruningStatus()
+}
@@ -2,6 +2,7 @@ package gossip
 
 import (
 	"fmt"
+	"math/big"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -25,7 +26,9 @@ import (
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
 	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/txtrace"
 	"github.com/Fantom-foundation/go-opera/utils"
+	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
 )
 
 var (
@@ -70,6 +73,8 @@ func (s *Service) GetConsensusCallbacks() lachesis.ConsensusCallbacks {
 			s.store,
 			s.blockProcModules,
 			s.config.TxIndex,
+			s.config.TraceIndex,
+			s.config.AddressTxIndex,
 			&s.feed,
 			&s.emitters,
 			s.verWatcher,
@@ -86,6 +91,8 @@ func consensusCallbackBeginBlockFn(
 	store *Store,
 	blockProc BlockProc,
 	txIndex bool,
+	traceIndex bool,
+	addressTxIndex bool,
 	feed *ServiceFeed,
 	emitters *[]*emitter.Emitter,
 	verWatcher *verwatcher.VerWarcher,
@@ -185,6 +192,7 @@ func consensusCallbackBeginBlockFn(
 				for _, em := range *emitters {
 					em.OnEventConfirmed(e)
 				}
+				recordConfirmedEventMetrics(e, time.Now())
 			},
 			EndBlock: func() (newValidators *pos.Validators) {
 				if atroposTime <= bs.LastBlock.Time {
@@ -250,7 +258,14 @@ func consensusCallbackBeginBlockFn(
 					})
 				}
 
-				evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, evmStateReader, onNewLogAll, es.Rules, es.Rules.EvmChainConfig(store.GetUpgradeHeights()))
+				vmCfg := opera.DefaultVMConfig
+				var callTracer *txtrace.CallTracer
+				if traceIndex {
+					callTracer = txtrace.NewCallTracer()
+					vmCfg.Debug = true
+					vmCfg.Tracer = callTracer
+				}
+				evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, evmStateReader, onNewLogAll, es.Rules, es.Rules.EvmChainConfig(store.GetUpgradeHeights()), vmCfg)
 				executionStart := time.Now()
 
 				// Execute pre-internal transactions
@@ -265,6 +280,7 @@ func consensusCallbackBeginBlockFn(
 
 				// Seal epoch if requested
 				if sealing {
+					store.SetEpochMetrics(calcEpochMetrics(blockCtx, bs, es))
 					sealer.Update(bs, es)
 					prevUpg := es.Rules.Upgrades
 					bs, es = sealer.SealEpoch() // TODO: refactor to not mutate the bs, it is unclear
@@ -370,6 +386,21 @@ func consensusCallbackBeginBlockFn(
 							}
 						}
 					}
+					// Index senders and recipients of not skipped txs
+					if addressTxIndex {
+						txSigner := gsignercache.Wrap(types.LatestSignerForChainID(new(big.Int).SetUint64(es.Rules.NetworkID)))
+						store.evm.IndexAddressTxs(blockCtx.Idx, evmBlock.Transactions, allReceipts, txSigner)
+					}
+					// Index internal traces of not skipped txs
+					if traceIndex {
+						for i, tx := range evmBlock.Transactions {
+							traces := callTracer.Traces(tx.Hash(), evmBlock.Hash, blockCtx.Idx, uint32(i))
+							err := store.evm.EvmTraces.SetTxTraces(txtrace.TxID{Block: blockCtx.Idx, Position: uint32(i)}, traces)
+							if err != nil {
+								log.Crit("Failed to index tx traces", "err", err)
+							}
+						}
+					}
 					for _, tx := range append(preInternalTxs, internalTxs...) {
 						store.evm.SetTx(tx.Hash(), tx)
 					}
@@ -434,6 +465,8 @@ func consensusCallbackBeginBlockFn(
 						evmBlock.GasUsed, "txs", fmt.Sprintf("%d/%d", len(evmBlock.Transactions), len(block.SkippedTxs)),
 						"age", utils.PrettyDuration(blockAge), "t", utils.PrettyDuration(now.Sub(start)))
 					blockAgeGauge.Update(int64(blockAge.Nanoseconds()))
+					recordBlockTxsMetrics(evmBlock.Transactions, now)
+					recordLlrMetrics(store.GetLlrState(), blockCtx.Idx, store.GetEpoch()-1)
 				}
 				if confirmedEvents.Len() != 0 {
 					atomic.StoreUint32(blockBusyFlag, 1)
@@ -473,10 +506,27 @@ func (s *Service) ReexecuteBlocks(from, to idx.Block) {
 			log.Crit("Failue to re-execute blocks", "err", err)
 		}
 		es := s.store.GetHistoryEpochState(s.store.FindBlockEpoch(b))
-		evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, evmStateReader, func(t *types.Log) {}, es.Rules, es.Rules.EvmChainConfig(upgradeHeights))
+		vmCfg := opera.DefaultVMConfig
+		var callTracer *txtrace.CallTracer
+		if s.config.TraceIndex {
+			callTracer = txtrace.NewCallTracer()
+			vmCfg.Debug = true
+			vmCfg.Tracer = callTracer
+		}
+		evmProcessor := blockProc.EVMModule.Start(blockCtx, statedb, evmStateReader, func(t *types.Log) {}, es.Rules, es.Rules.EvmChainConfig(upgradeHeights), vmCfg)
 		txs := s.store.GetBlockTxs(b, block)
 		evmProcessor.Execute(txs)
 		evmProcessor.Finalize()
+		// traces of the re-executed blocks may be lost together with the not flushed state
+		if callTracer != nil {
+			for i, tx := range txs {
+				traces := callTracer.Traces(tx.Hash(), common.Hash(block.Atropos), b, uint32(i))
+				err := s.store.evm.EvmTraces.SetTxTraces(txtrace.TxID{Block: b, Position: uint32(i)}, traces)
+				if err != nil {
+					log.Crit("Failed to index tx traces", "err", err)
+				}
+			}
+		}
 		_ = s.store.evm.Commit(b, block.Root, false)
 		s.store.evm.Cap()
 		s.mayCommit(false)
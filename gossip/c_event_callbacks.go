@@ -240,9 +240,11 @@ func (s *Service) processEvent(e *inter.EventPayload) error {
 	for _, em := range s.emitters {
 		em.OnEventConnected(e)
 	}
+	recordConnectedEventMetrics(e, newEpoch)
 
 	if newEpoch != oldEpoch {
 		s.switchEpochTo(newEpoch)
+		recordNewEpochMetrics(newEpoch)
 	}
 
 	s.mayCommit(newEpoch != oldEpoch)
@@ -139,22 +139,39 @@ func (s *Store) WriteFullBlockRecord(br ibr.LlrIdxFullBlockRecord) {
 }
 
 func (s *Service) ProcessFullBlockRecord(br ibr.LlrIdxFullBlockRecord) error {
+	_, err := s.processFullBlockRecord(br, false)
+	return err
+}
+
+// ImportFullBlockRecord writes the block record, verifying its hash against the LLR votes if the block is decided.
+// Returns false if the block isn't decided, i.e. the record is written unverified.
+// An unverified record doesn't move the lowest block to fill, and it's replaced by the verified record once it's received.
+func (s *Service) ImportFullBlockRecord(br ibr.LlrIdxFullBlockRecord) (verified bool, err error) {
+	return s.processFullBlockRecord(br, true)
+}
+
+func (s *Service) processFullBlockRecord(br ibr.LlrIdxFullBlockRecord, allowUndecided bool) (verified bool, err error) {
 	// engineMu should NOT be locked here
-	if s.store.HasBlock(br.Idx) {
-		return eventcheck.ErrAlreadyProcessedBR
+	res := s.store.GetLlrBlockResult(br.Idx)
+	replace := res != nil && s.store.IsUnverifiedBlockRecord(br.Idx)
+	if s.store.HasBlock(br.Idx) && !replace {
+		return false, eventcheck.ErrAlreadyProcessedBR
 	}
 	done := s.procLogger.BlockRecordConnectionStarted(br)
 	defer done()
-	res := s.store.GetLlrBlockResult(br.Idx)
-	if res == nil {
-		return eventcheck.ErrUndecidedBR
+	if res == nil && !allowUndecided {
+		return false, eventcheck.ErrUndecidedBR
 	}
 
-	if br.Hash() != *res {
-		return errors.New("block record hash mismatch")
+	if res != nil && br.Hash() != *res {
+		return false, errors.New("block record hash mismatch")
 	}
 
+	if replace {
+		s.store.DelBlockIndex(s.store.GetBlock(br.Idx).Atropos)
+	}
 	s.store.WriteFullBlockRecord(br)
+	s.store.SetUnverifiedBlockRecord(br.Idx, res == nil)
 	s.engineMu.Lock()
 	defer s.engineMu.Unlock()
 	if s.verWatcher != nil {
@@ -165,10 +182,12 @@ func (s *Service) ProcessFullBlockRecord(br ibr.LlrIdxFullBlockRecord) error {
 			}
 		}
 	}
-	updateLowestBlockToFill(br.Idx, s.store)
+	if res != nil {
+		updateLowestBlockToFill(br.Idx, s.store)
+	}
 	s.mayCommit(false)
 
-	return nil
+	return res != nil, nil
 }
 
 func (s *Service) processRawEpochVote(epoch idx.Epoch, ev hash.Hash, val idx.Validator, vals *pos.Validators, llrs *LlrState) {
@@ -250,36 +269,52 @@ func (s *Store) WriteUpgradeHeight(bs iblockproc.BlockState, es iblockproc.Epoch
 }
 
 func (s *Service) ProcessFullEpochRecord(er ier.LlrIdxFullEpochRecord) error {
+	_, err := s.processFullEpochRecord(er, false)
+	return err
+}
+
+// ImportFullEpochRecord writes the epoch record, verifying its hash against the LLR votes if the epoch is decided.
+// Returns false if the epoch isn't decided, i.e. the record is written unverified.
+// An unverified record doesn't move the lowest epoch to fill, and it's replaced by the verified record once it's received.
+func (s *Service) ImportFullEpochRecord(er ier.LlrIdxFullEpochRecord) (verified bool, err error) {
+	return s.processFullEpochRecord(er, true)
+}
+
+func (s *Service) processFullEpochRecord(er ier.LlrIdxFullEpochRecord, allowUndecided bool) (verified bool, err error) {
 	// engineMu should NOT be locked here
-	if s.store.HasHistoryBlockEpochState(er.Idx) {
-		return eventcheck.ErrAlreadyProcessedER
+	res := s.store.GetLlrEpochResult(er.Idx)
+	replace := res != nil && s.store.IsUnverifiedEpochRecord(er.Idx)
+	if s.store.HasHistoryBlockEpochState(er.Idx) && !replace {
+		return false, eventcheck.ErrAlreadyProcessedER
 	}
 	done := s.procLogger.EpochRecordConnectionStarted(er)
 	defer done()
 
-	res := s.store.GetLlrEpochResult(er.Idx)
-	if res == nil {
-		return eventcheck.ErrUndecidedER
+	if res == nil && !allowUndecided {
+		return false, eventcheck.ErrUndecidedER
 	}
 
-	if er.Hash() != *res {
-		return errors.New("epoch record hash mismatch")
+	if res != nil && er.Hash() != *res {
+		return false, errors.New("epoch record hash mismatch")
 	}
 
 	s.store.WriteFullEpochRecord(er)
 	s.store.WriteUpgradeHeight(er.BlockState, er.EpochState, s.store.GetHistoryEpochState(er.EpochState.Epoch-1))
+	s.store.SetUnverifiedEpochRecord(er.Idx, res == nil)
 	s.engineMu.Lock()
 	defer s.engineMu.Unlock()
-	updateLowestEpochToFill(er.Idx, s.store)
+	if res != nil {
+		updateLowestEpochToFill(er.Idx, s.store)
+	}
 	s.mayCommit(false)
 
-	return nil
+	return res != nil, nil
 }
 
 func updateLowestBlockToFill(block idx.Block, store *Store) {
 	store.ModifyLlrState(func(llrs *LlrState) {
 		llrs.LowestBlockToFill = idx.Block(actualizeLowestIndex(uint64(llrs.LowestBlockToFill), uint64(block), func(u uint64) bool {
-			return store.GetBlock(idx.Block(u)) != nil
+			return store.GetBlock(idx.Block(u)) != nil && !store.IsUnverifiedBlockRecord(idx.Block(u))
 		}))
 	})
 }
@@ -287,7 +322,7 @@ func updateLowestBlockToFill(block idx.Block, store *Store) {
 func updateLowestEpochToFill(epoch idx.Epoch, store *Store) {
 	store.ModifyLlrState(func(llrs *LlrState) {
 		llrs.LowestEpochToFill = idx.Epoch(actualizeLowestIndex(uint64(llrs.LowestEpochToFill), uint64(epoch), func(u uint64) bool {
-			return store.HasHistoryBlockEpochState(idx.Epoch(u))
+			return store.HasVerifiedHistoryBlockEpochState(idx.Epoch(u))
 		}))
 	})
 }
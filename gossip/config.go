@@ -16,6 +16,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
 	"github.com/Fantom-foundation/go-opera/gossip/filters"
 	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brprocessor"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream/brstreamleecher"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream/brstreamseeder"
@@ -27,6 +28,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epprocessor"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream/epstreamleecher"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream/epstreamseeder"
+	"github.com/Fantom-foundation/go-opera/gossip/traces"
 )
 
 const nominalSize uint = 1
@@ -67,11 +69,14 @@ type (
 		RandomTxHashesSendPeriod time.Duration
 
 		PeerCache PeerCacheConfig
+
+		PeerScore peerscore.Config
 	}
 
 	// Config for the gossip service.
 	Config struct {
 		FilterAPI filters.Config
+		TraceAPI  traces.Config
 
 		// This can be set to list of enrtree:// URLs which will be queried for
 		// for nodes to connect to.
@@ -80,8 +85,30 @@ type (
 
 		AllowSnapsync bool
 
+		// Sentry topology options
+		Sentry SentryConfig
+
+		// Private transactions options
+		PrivateTxs PrivateTxsConfig
+
+		// Health check HTTP endpoints options
+		Health HealthConfig
+
+		// History retention options
+		HistoryRetention HistoryRetentionConfig
+
+		// Migration of the finalized history into the freezer options
+		Freezer FreezerConfig
+
 		TxIndex bool // Whether to enable indexing transactions and receipts or not
 
+		// Whether to enable recording and indexing internal traces of transactions or not.
+		// Traces are recorded only for the executed blocks, so there are no traces of the blocks
+		// which are written from the block records of a genesis file or of the LLR sync.
+		TraceIndex bool
+
+		AddressTxIndex bool // Whether to enable indexing transactions by senders and recipients or not
+
 		// Protocol options
 		Protocol ProtocolConfig
 
@@ -106,6 +133,49 @@ type (
 		RPCBlockExt bool
 	}
 
+	// SentryConfig is config for the sentry topology, where a validator is connected only to its sentry nodes.
+	SentryConfig struct {
+		// Mode is either "validator" (a validator behind sentries), "sentry" (a sentry of validators) or empty (a regular node)
+		Mode string
+		// PrivatePeers are enode URLs of the sentries in the validator mode, or of the validators in the sentry mode
+		PrivatePeers []string `toml:",omitempty"`
+	}
+
+	// PrivateTxsConfig is config of the private transactions, which aren't announced to peers
+	// and are originated only by validators.
+	PrivateTxsConfig struct {
+		// Peers are enode URLs of the validators (or of their sentries) which private transactions are forwarded to.
+		// Sentries forward private transactions also to their validators.
+		// Private transactions are accepted only from these peers.
+		Peers []string `toml:",omitempty"`
+		// DefaultLifetime is the number of blocks to keep a private transaction for, if its expiry block isn't specified
+		DefaultLifetime idx.Block
+		// MaxLifetime is the max number of blocks to keep a private transaction for
+		MaxLifetime idx.Block
+	}
+
+	// HistoryRetentionConfig is config of the background pruning of the old events, blocks, receipts and logs.
+	HistoryRetentionConfig struct {
+		// Epochs is the number of the latest sealed epochs to keep the history of, besides the current epoch.
+		// Zero disables the history pruning.
+		Epochs idx.Epoch
+		// Period is the interval of checking for the history to prune
+		Period time.Duration
+		// BatchBlocks is the number of blocks which are pruned at once, without releasing the processing lock
+		BatchBlocks idx.Block
+	}
+
+	// FreezerConfig is config of the background migration of the finalized history into the freezer.
+	// The migration is enabled if the freezer is routed in the DBs routing table.
+	FreezerConfig struct {
+		// Epochs is the number of the latest sealed epochs to keep in the key-value DBs, besides the current epoch
+		Epochs idx.Epoch
+		// Period is the interval of checking for the history to migrate
+		Period time.Duration
+		// BatchBlocks is the number of blocks which are migrated at once
+		BatchBlocks idx.Block
+	}
+
 	StoreCacheConfig struct {
 		// Cache size for full events.
 		EventsNum  int
@@ -129,6 +199,9 @@ type (
 		EVM                 evmstore.StoreConfig
 		MaxNonFlushedSize   int
 		MaxNonFlushedPeriod time.Duration
+		// FreezerDir is the directory of the freezer for the finalized history, disabled if empty.
+		// It's derived from the "freezer" route of the DBs routing table.
+		FreezerDir string `toml:"-"`
 	}
 )
 
@@ -146,6 +219,7 @@ type PeerCacheConfig struct {
 func DefaultConfig(scale cachescale.Func) Config {
 	cfg := Config{
 		FilterAPI: filters.DefaultConfig(),
+		TraceAPI:  traces.DefaultConfig(),
 
 		TxIndex: true,
 
@@ -203,6 +277,7 @@ func DefaultConfig(scale cachescale.Func) Config {
 			MaxRandomTxHashesSend:    128,
 			RandomTxHashesSendPeriod: 20 * time.Second,
 			PeerCache:                DefaultPeerCacheConfig(scale),
+			PeerScore:                peerscore.DefaultConfig(),
 		},
 
 		GPO: gasprice.Config{
@@ -211,6 +286,14 @@ func DefaultConfig(scale cachescale.Func) Config {
 			DefaultCertainty: 0.5 * gasprice.DecimalUnit,
 		},
 
+		Health: DefaultHealthConfig(),
+
+		PrivateTxs: DefaultPrivateTxsConfig(),
+
+		HistoryRetention: DefaultHistoryRetentionConfig(),
+
+		Freezer: DefaultFreezerConfig(),
+
 		RPCBlockExt: true,
 
 		RPCGasCap:   50000000,
@@ -255,6 +338,18 @@ func (c *Config) Validate() error {
 	if p.DagProcessor.EventsBufferLimit.Size < protocolMaxMsgSize {
 		return fmt.Errorf("EventsBufferLimit.Size has to be at least %d", protocolMaxMsgSize)
 	}
+	if err := c.Sentry.Validate(); err != nil {
+		return err
+	}
+	if err := c.PrivateTxs.Validate(); err != nil {
+		return err
+	}
+	if err := c.HistoryRetention.Validate(); err != nil {
+		return err
+	}
+	if err := c.Freezer.Validate(); err != nil {
+		return err
+	}
 
 	return nil
 }
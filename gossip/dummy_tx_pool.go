@@ -1,6 +1,7 @@
 package gossip
 
 import (
+	"errors"
 	"math/rand"
 	"sort"
 	"sync"
@@ -44,6 +45,14 @@ func (p *dummyTxPool) AddLocal(tx *types.Transaction) error {
 	return p.AddLocals([]*types.Transaction{tx})[0]
 }
 
+func (p *dummyTxPool) AddPrivate(tx *types.Transaction, expiry uint64) error {
+	return errors.New("private transactions aren't supported")
+}
+
+func (p *dummyTxPool) IsPrivate(txid common.Hash) bool {
+	return false
+}
+
 func (p *dummyTxPool) Nonce(addr common.Address) uint64 {
 	return 0
 }
@@ -60,6 +69,10 @@ func (p *dummyTxPool) ContentFrom(addr common.Address) (types.Transactions, type
 	return nil, nil
 }
 
+func (p *dummyTxPool) TxInfo(hash common.Hash) *evmcore.TxInfo {
+	return nil
+}
+
 // Pending returns all the transactions known to the pool
 func (p *dummyTxPool) Pending(enforceTips bool) (map[common.Address]types.Transactions, error) {
 	p.lock.RLock()
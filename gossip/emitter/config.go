@@ -40,6 +40,11 @@ type Config struct {
 
 	MaxTxsPerAddress int
 
+	// PrivateTxOriginators are IDs of the validators which receive the private transactions.
+	// Private transactions are originated in turns among them, as other validators don't know the transactions.
+	// If empty, the private transactions are originated only by this validator.
+	PrivateTxOriginators []idx.ValidatorID `toml:",omitempty"`
+
 	MaxParents idx.Event
 
 	// thresholds on GasLeft
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/emitter/ancestor"
@@ -21,7 +23,9 @@ import (
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/logger"
 	"github.com/Fantom-foundation/go-opera/tracing"
+	"github.com/Fantom-foundation/go-opera/utils/promexp"
 	"github.com/Fantom-foundation/go-opera/utils/rate"
+	"github.com/Fantom-foundation/go-opera/valkeystore"
 )
 
 const (
@@ -29,6 +33,9 @@ const (
 	PayloadIndexerSize    = 5000
 )
 
+var busyRateGauge = promexp.NewGaugeVec("opera_emitter_busy_rate",
+	"Share of emitter ticks when the node was busy, per minute.", "validator")
+
 type Emitter struct {
 	txTime *lru.Cache // tx hash -> tx time
 
@@ -40,6 +47,7 @@ type Emitter struct {
 
 	prevIdleTime       time.Time
 	prevEmittedAtTime  time.Time
+	paused             uint32 // 1 if emitting is paused by the doublesign protection
 	prevEmittedAtBlock idx.Block
 	originatedTxs      *originatedtxs.Buffer
 	pendingGas         uint64
@@ -48,6 +56,10 @@ type Emitter struct {
 	// validators of a future epoch inside OnEventConnected of last epoch event
 	validators *pos.Validators
 	epoch      idx.Epoch
+	// validators of the current epoch which originate the private transactions in turns
+	privateTxOriginators *pos.Validators
+	// snapshot of the epoch data for reading without the world lock
+	epochSnapshot atomic.Value // stores epochSnapshot
 
 	// challenges is deadlines when each validator should emit an event
 	challenges map[idx.ValidatorID]time.Time
@@ -176,6 +188,11 @@ func (em *Emitter) Stop() {
 	em.busyRate.Stop()
 }
 
+// Validator returns the validator ID of the emitter, zero if the emitter isn't a validator
+func (em *Emitter) Validator() idx.ValidatorID {
+	return em.config.Validator.ID
+}
+
 func (em *Emitter) tick() {
 	// track synced time
 	if em.world.PeersNum() == 0 {
@@ -191,6 +208,9 @@ func (em *Emitter) tick() {
 	} else {
 		em.busyRate.Mark(1)
 	}
+	if em.config.Validator.ID != 0 {
+		busyRateGauge.Set(em.busyRate.Rate1(), strconv.FormatUint(uint64(em.config.Validator.ID), 10))
+	}
 	if em.world.IsBusy() {
 		return
 	}
@@ -293,6 +313,14 @@ func (em *Emitter) loadPrevEmitTime() time.Time {
 }
 
 // createEvent is not safe for concurrent use.
+// signEvent signs the event. The event locator is passed to the signers which guard against double-signing.
+func (em *Emitter) signEvent(e *inter.MutableEventPayload) ([]byte, error) {
+	if signer, ok := em.world.Signer.(valkeystore.EventSignerI); ok {
+		return signer.SignEvent(em.config.Validator.PubKey, e.Locator())
+	}
+	return em.world.Signer.Sign(em.config.Validator.PubKey, e.HashToSign().Bytes())
+}
+
 func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*inter.EventPayload, error) {
 	if !em.isValidator() {
 		return nil, nil
@@ -300,8 +328,10 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*i
 
 	if synced := em.logSyncStatus(em.isSyncedToEmit()); !synced {
 		// I'm reindexing my old events, so don't create events until connect all the existing self-events
+		atomic.StoreUint32(&em.paused, 1)
 		return nil, nil
 	}
+	atomic.StoreUint32(&em.paused, 0)
 
 	var (
 		selfParentSeq  idx.Event
@@ -406,7 +436,7 @@ func (em *Emitter) createEvent(sortedTxs *types.TransactionsByPriceAndNonce) (*i
 	mutEvent.SetPayloadHash(inter.CalcPayloadHash(mutEvent))
 
 	// sign
-	bSig, err := em.world.Signer.Sign(em.config.Validator.PubKey, mutEvent.HashToSign().Bytes())
+	bSig, err := em.signEvent(mutEvent)
 	if err != nil {
 		em.Periodic.Error(time.Second, "Failed to sign event", "err", err)
 		return nil, err
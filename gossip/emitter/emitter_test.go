@@ -1,7 +1,10 @@
 package emitter
 
 import (
+	"io/ioutil"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -105,3 +108,116 @@ func TestEmitter(t *testing.T) {
 		em.tick()
 	})
 }
+
+func TestTxOriginationWithoutWorldLock(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	gValidators := makefakegenesis.GetFakeValidators(3)
+	vv := pos.NewBuilder()
+	for _, v := range gValidators {
+		vv.Set(v.ID, pos.Weight(1))
+	}
+	validators := vv.Build()
+	cfg.Validator.ID = gValidators[0].ID
+
+	ctrl := gomock.NewController(t)
+	external := mock.NewMockExternal(ctrl)
+	txPool := mock.NewMockTxPool(ctrl)
+	txSigner := mock.NewMockTxSigner(ctrl)
+
+	// Lock and Unlock aren't expected, the consensus engine lock must not be taken
+	external.EXPECT().DagIndex().
+		Return((*vecmt.Index)(nil)).
+		AnyTimes()
+	external.EXPECT().GetRules().
+		Return(opera.FakeNetRules()).
+		AnyTimes()
+	external.EXPECT().GetEpochValidators().
+		Return(validators, idx.Epoch(1)).
+		AnyTimes()
+	external.EXPECT().GetLastEvent(idx.Epoch(1), cfg.Validator.ID).
+		Return((*hash.Event)(nil)).
+		AnyTimes()
+	external.EXPECT().GetGenesisTime().
+		Return(inter.Timestamp(uint64(time.Now().UnixNano()))).
+		AnyTimes()
+	txSigner.EXPECT().Sender(gomock.Any()).
+		Return(common.Address{1}, nil).
+		AnyTimes()
+	txPool.EXPECT().IsPrivate(gomock.Any()).
+		Return(false).
+		AnyTimes()
+
+	em := NewEmitter(cfg, World{
+		External: external,
+		TxPool:   txPool,
+		Signer:   mock.NewMockSigner(ctrl),
+		TxSigner: txSigner,
+	})
+	em.init()
+
+	tx := types.NewTransaction(1, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil)
+	res := em.TxOrigination(tx)
+	require.Equal(cfg.Validator.ID, res.Validator)
+	require.Equal(res.Turn == cfg.Validator.ID, res.Originate)
+	if !res.Originate {
+		require.NotEmpty(res.Reason)
+	}
+}
+
+func TestPrivateTxOriginators(t *testing.T) {
+	require := require.New(t)
+
+	vv := pos.NewBuilder()
+	vv.Set(1, 10)
+	vv.Set(2, 20)
+	vv.Set(3, 30)
+	validators := vv.Build()
+
+	// only the local validator if not configured
+	originators := privateTxOriginators(validators, nil, 2)
+	require.Equal([]idx.ValidatorID{2}, originators.IDs())
+	require.Equal(pos.Weight(20), originators.Get(2))
+	require.Equal(0, int(privateTxOriginators(validators, nil, 0).Len()))
+
+	// configured validators which aren't in the epoch are skipped
+	originators = privateTxOriginators(validators, []idx.ValidatorID{3, 1, 4}, 2)
+	require.Equal(2, int(originators.Len()))
+	require.True(originators.Exists(1))
+	require.True(originators.Exists(3))
+	require.False(originators.Exists(2))
+
+	// the turns of a private tx are among the originators only
+	for i := 0; i < 10; i++ {
+		turn, ok := txTurn(time.Now(), common.Address{byte(i)}, uint64(i), time.Now(), originators, 1)
+		if ok {
+			require.True(originators.Exists(turn))
+		}
+	}
+}
+
+func TestStatusEventFileError(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "emitter")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	em := &Emitter{}
+	em.config.Validator.ID = 1
+	em.config.PrevEmittedEventFile.Path = filepath.Join(dir, "last")
+	em.emittedEventFile = openPrevActionFile(em.config.PrevEmittedEventFile.Path, false)
+
+	// no events are emitted yet
+	s, err := em.Status()
+	require.NoError(err)
+	require.Equal(idx.ValidatorID(1), s.Validator)
+	require.Nil(s.LastEmittedEvent)
+
+	// unreadable file is reported instead of crashing the node
+	require.NoError(em.emittedEventFile.Close())
+	s, err = em.Status()
+	require.Error(err)
+	require.Equal(idx.ValidatorID(1), s.Validator)
+}
@@ -27,6 +27,13 @@ func (em *Emitter) OnNewEpoch(newValidators *pos.Validators, newEpoch idx.Epoch)
 	}
 
 	em.validators, em.epoch = newValidators, newEpoch
+	em.privateTxOriginators = privateTxOriginators(newValidators, em.config.PrivateTxOriginators, em.config.Validator.ID)
+	em.epochSnapshot.Store(epochSnapshot{
+		validators:           newValidators,
+		privateTxOriginators: em.privateTxOriginators,
+		epoch:                newEpoch,
+		rules:                rules,
+	})
 
 	if !em.isValidator() {
 		return
@@ -426,6 +426,20 @@ func (mr *MockTxPoolMockRecorder) Count() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockTxPool)(nil).Count))
 }
 
+// IsPrivate mocks base method
+func (m *MockTxPool) IsPrivate(arg0 common.Hash) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPrivate", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsPrivate indicates an expected call of IsPrivate
+func (mr *MockTxPoolMockRecorder) IsPrivate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPrivate", reflect.TypeOf((*MockTxPool)(nil).IsPrivate), arg0)
+}
+
 // Has mocks base method
 func (m *MockTxPool) Has(arg0 common.Hash) bool {
 	m.ctrl.T.Helper()
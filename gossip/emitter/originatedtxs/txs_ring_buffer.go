@@ -1,11 +1,15 @@
 package originatedtxs
 
 import (
+	"sync"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/hashicorp/golang-lru/simplelru"
 )
 
+// Buffer counts the originated transactions of senders. It's safe for concurrent use.
 type Buffer struct {
+	mu          sync.Mutex
 	senderCount *simplelru.LRU // sender address -> number of transactions
 }
 
@@ -15,8 +19,9 @@ func New(maxAddresses int) *Buffer {
 	return ring
 }
 
-// Inc is not safe for concurrent use
 func (ring *Buffer) Inc(sender common.Address) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
 	cur, ok := ring.senderCount.Peek(sender)
 	if ok {
 		ring.senderCount.Add(sender, cur.(int)+1)
@@ -25,8 +30,9 @@ func (ring *Buffer) Inc(sender common.Address) {
 	}
 }
 
-// Dec is not safe for concurrent use
 func (ring *Buffer) Dec(sender common.Address) {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
 	cur, ok := ring.senderCount.Peek(sender)
 	if !ok {
 		return
@@ -38,13 +44,15 @@ func (ring *Buffer) Dec(sender common.Address) {
 	}
 }
 
-// Clear is not safe for concurrent use
 func (ring *Buffer) Clear() {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
 	ring.senderCount.Purge()
 }
 
-// TotalOf is not safe for concurrent use
 func (ring *Buffer) TotalOf(sender common.Address) int {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
 	cur, ok := ring.senderCount.Get(sender)
 	if !ok {
 		return 0
@@ -52,7 +60,8 @@ func (ring *Buffer) TotalOf(sender common.Address) int {
 	return cur.(int)
 }
 
-// Empty is not safe for concurrent use
 func (ring *Buffer) Empty() bool {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
 	return ring.senderCount.Len() == 0
 }
@@ -37,19 +37,27 @@ func (em *Emitter) writeLastEmittedEventID(id hash.Event) {
 }
 
 func (em *Emitter) readLastEmittedEventID() *hash.Event {
+	id, err := em.tryReadLastEmittedEventID()
+	if err != nil {
+		log.Crit("Failed to read event file", "file", em.config.PrevEmittedEventFile.Path, "err", err)
+	}
+	return id
+}
+
+func (em *Emitter) tryReadLastEmittedEventID() (*hash.Event, error) {
 	if em.emittedEventFile == nil {
-		return nil
+		return nil, nil
 	}
 	buf := make([]byte, 32)
 	_, err := em.emittedEventFile.ReadAt(buf, 0)
 	if err != nil {
 		if err == io.EOF {
-			return nil
+			return nil, nil
 		}
-		log.Crit("Failed to read event file", "file", em.config.PrevEmittedEventFile.Path, "err", err)
+		return nil, err
 	}
 	v := hash.BytesToEvent(buf)
-	return &v
+	return &v, nil
 }
 
 func (em *Emitter) writeLastEmittedBlockVotes(b idx.Block) {
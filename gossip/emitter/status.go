@@ -0,0 +1,44 @@
+package emitter
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// Status is a snapshot of the emitter state
+type Status struct {
+	Validator idx.ValidatorID
+	// Paused is true if emitting is paused by the doublesign protection
+	Paused bool
+	// LastEmittedEvent is the last emitted event according to the prev emitted event file
+	LastEmittedEvent *hash.Event
+	// LastEmittedAt is the creation time of LastEmittedEvent, zero if the event is unknown
+	LastEmittedAt time.Time
+}
+
+// Status returns the emitter state. It's safe for concurrent use after the emitter is started.
+// An error is returned if the prev emitted event file cannot be read.
+func (em *Emitter) Status() (Status, error) {
+	s := Status{
+		Validator: em.config.Validator.ID,
+		Paused:    atomic.LoadUint32(&em.paused) != 0,
+	}
+	if s.Validator == 0 {
+		return s, nil
+	}
+	var err error
+	s.LastEmittedEvent, err = em.tryReadLastEmittedEventID()
+	if err != nil {
+		return s, fmt.Errorf("failed to read event file %s: %v", em.config.PrevEmittedEventFile.Path, err)
+	}
+	if s.LastEmittedEvent != nil {
+		if e := em.world.GetEvent(*s.LastEmittedEvent); e != nil {
+			s.LastEmittedAt = e.CreationTime().Time()
+		}
+	}
+	return s, nil
+}
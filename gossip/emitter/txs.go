@@ -1,6 +1,8 @@
 package emitter
 
 import (
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
@@ -14,6 +16,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/eventcheck/epochcheck"
 	"github.com/Fantom-foundation/go-opera/eventcheck/gaspowercheck"
 	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/opera"
 	"github.com/Fantom-foundation/go-opera/utils"
 )
 
@@ -135,19 +138,50 @@ func (em *Emitter) getTxTime(txHash common.Hash) time.Time {
 	return txTimeI.(time.Time)
 }
 
-// safe for concurrent use
-func (em *Emitter) isMyTxTurn(txHash common.Hash, sender common.Address, accountNonce uint64, now time.Time, validators *pos.Validators, me idx.ValidatorID, epoch idx.Epoch) bool {
-	txTime := em.getTxTime(txHash)
-
+// txTurn returns the validator whose turn it is to originate the transaction.
+// Returns false if the round is about to change.
+func txTurn(txTime time.Time, sender common.Address, accountNonce uint64, now time.Time, validators *pos.Validators, epoch idx.Epoch) (idx.ValidatorID, bool) {
 	roundIndex := getTxRoundIndex(now, txTime, validators.Len())
 	if roundIndex != getTxRoundIndex(now.Add(TxTurnPeriodLatency), txTime, validators.Len()) {
 		// round is about to change, avoid originating the transaction to avoid racing with another validator
-		return false
+		return 0, false
 	}
 
 	roundsHash := hash.Of(sender.Bytes(), bigendian.Uint64ToBytes(accountNonce/TxTurnNonces), epoch.Bytes())
 	rounds := utils.WeightedPermutation(roundIndex+1, validators.SortedWeights(), roundsHash)
-	return validators.GetID(idx.Validator(rounds[roundIndex])) == me
+	return validators.GetID(idx.Validator(rounds[roundIndex])), true
+}
+
+// privateTxOriginators returns the validators of the epoch which originate the private transactions in turns.
+// The weights of the validators are kept, so the turns are distributed the same way as for regular transactions.
+func privateTxOriginators(validators *pos.Validators, ids []idx.ValidatorID, me idx.ValidatorID) *pos.Validators {
+	if len(ids) == 0 {
+		ids = []idx.ValidatorID{me}
+	}
+	builder := pos.NewBuilder()
+	for _, id := range ids {
+		if validators.Exists(id) {
+			builder.Set(id, validators.Get(id))
+		}
+	}
+	return builder.Build()
+}
+
+// turnValidators returns the validators which originate the transaction in turns
+func (em *Emitter) turnValidators(txHash common.Hash, validators, privateTxOriginators *pos.Validators) *pos.Validators {
+	if em.world.TxPool.IsPrivate(txHash) {
+		// private txs are known only to the validators which they are forwarded to
+		return privateTxOriginators
+	}
+	return validators
+}
+
+// safe for concurrent use
+func (em *Emitter) isMyTxTurn(txHash common.Hash, sender common.Address, accountNonce uint64, now time.Time, validators *pos.Validators, me idx.ValidatorID, epoch idx.Epoch) bool {
+	txTime := em.getTxTime(txHash)
+
+	turn, ok := txTurn(txTime, sender, accountNonce, now, validators, epoch)
+	return ok && turn == me
 }
 
 func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.TransactionsByPriceAndNonce) {
@@ -180,7 +214,8 @@ func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.Transactio
 			continue
 		}
 		// my turn, i.e. try to not include the same tx simultaneously by different validators
-		if !em.isMyTxTurn(tx.Hash(), sender, tx.Nonce(), time.Now(), em.validators, e.Creator(), em.epoch) {
+		turnValidators := em.turnValidators(tx.Hash(), em.validators, em.privateTxOriginators)
+		if turnValidators.Len() == 0 || !em.isMyTxTurn(tx.Hash(), sender, tx.Nonce(), time.Now(), turnValidators, e.Creator(), em.epoch) {
 			sorted.Pop()
 			continue
 		}
@@ -196,3 +231,92 @@ func (em *Emitter) addTxs(e *inter.MutableEventPayload, sorted *types.Transactio
 		sorted.Shift()
 	}
 }
+
+// TxOrigination describes whether the emitter would originate a transaction
+type TxOrigination struct {
+	Validator idx.ValidatorID
+	// Originate is true if the transaction would be originated into the next event
+	Originate bool
+	// Reason explains why the transaction isn't originated
+	Reason string
+	// Turn is the validator whose turn it is to originate the transaction, if known
+	Turn idx.ValidatorID
+	// NextTurn is the estimated start of the next emitter's turn, if the current turn isn't the emitter's
+	NextTurn *time.Time
+}
+
+// epochSnapshot is the epoch data which is updated on epoch change
+type epochSnapshot struct {
+	validators           *pos.Validators
+	privateTxOriginators *pos.Validators
+	epoch                idx.Epoch
+	rules                opera.Rules
+}
+
+// TxOrigination checks whether the emitter would originate a pending transaction now.
+// The gas power of the next event isn't taken into account.
+// It doesn't take the world lock, so it may be called from the API without delaying the consensus.
+func (em *Emitter) TxOrigination(tx *types.Transaction) TxOrigination {
+	res := TxOrigination{
+		Validator: em.config.Validator.ID,
+	}
+	if res.Validator == 0 {
+		res.Reason = "node isn't a validator"
+		return res
+	}
+
+	snapshot, ok := em.epochSnapshot.Load().(epochSnapshot)
+	if !ok || !snapshot.validators.Exists(res.Validator) {
+		res.Reason = "validator isn't in the current epoch"
+		return res
+	}
+	if atomic.LoadUint32(&em.paused) != 0 {
+		res.Reason = "emitting is paused by the doublesign protection"
+		return res
+	}
+	rules := snapshot.rules
+	if err := epochcheck.CheckTxs(types.Transactions{tx}, rules); err != nil {
+		res.Reason = fmt.Sprintf("transaction violates the epoch rules: %v", err)
+		return res
+	}
+	if tx.Gas() >= rules.Economy.Gas.MaxEventGas {
+		res.Reason = "transaction gas limit exceeds the max event gas"
+		return res
+	}
+	sender, _ := types.Sender(em.world.TxSigner, tx)
+	if em.originatedTxs.TotalOf(sender) != 0 {
+		res.Reason = "another transaction of the sender is originated but not confirmed yet"
+		return res
+	}
+	turnValidators := em.turnValidators(tx.Hash(), snapshot.validators, snapshot.privateTxOriginators)
+	if turnValidators.Len() == 0 {
+		res.Reason = "no validators originate the private transaction"
+		return res
+	}
+
+	now := time.Now()
+	txTime := now
+	if t, ok := em.txTime.Peek(tx.Hash()); ok {
+		txTime = t.(time.Time)
+	}
+	turn, ok := txTurn(txTime, sender, tx.Nonce(), now, turnValidators, snapshot.epoch)
+	res.Turn = turn
+	if ok && turn == res.Validator {
+		res.Originate = true
+		return res
+	}
+	if ok {
+		res.Reason = fmt.Sprintf("turn of validator %d", turn)
+	} else {
+		res.Reason = "turn is about to pass to another validator"
+	}
+	// find the next round of the emitter
+	for i := 1; i <= int(turnValidators.Len()); i++ {
+		roundStart := txTime.Add((now.Sub(txTime)/TxTurnPeriod + time.Duration(i)) * TxTurnPeriod)
+		if next, _ := txTurn(txTime, sender, tx.Nonce(), roundStart, turnValidators, snapshot.epoch); next == res.Validator {
+			res.NextTurn = &roundStart
+			break
+		}
+	}
+	return res
+}
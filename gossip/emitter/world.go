@@ -90,4 +90,8 @@ type TxPool interface {
 
 	// Count returns the total number of transactions
 	Count() int
+
+	// IsPrivate returns true if the transaction was submitted privately,
+	// i.e. it's originated only by the local validator
+	IsPrivate(hash common.Hash) bool
 }
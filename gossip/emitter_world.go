@@ -40,7 +40,11 @@ func (ew *emitterWorldProc) Check(emitted *inter.EventPayload, parents inter.Eve
 func (ew *emitterWorldProc) Process(emitted *inter.EventPayload) error {
 	done := ew.s.procLogger.EventConnectionStarted(emitted, true)
 	defer done()
-	return ew.s.processEvent(emitted)
+	err := ew.s.processEvent(emitted)
+	if err == nil {
+		ew.s.feed.newEvent.Send(emitted)
+	}
+	return err
 }
 
 func (ew *emitterWorldProc) Broadcast(emitted *inter.EventPayload) {
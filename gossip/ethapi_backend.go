@@ -28,6 +28,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/opera"
 	"github.com/Fantom-foundation/go-opera/topicsdb"
 	"github.com/Fantom-foundation/go-opera/tracing"
+	"github.com/Fantom-foundation/go-opera/txtrace"
 )
 
 // EthAPIBackend implements ethapi.Backend.
@@ -61,6 +62,9 @@ func (b *EthAPIBackend) ResolveRpcBlockNumberOrHash(ctx context.Context, blockNr
 		if idx.Block(number) > latest {
 			return 0, errors.New("block not found")
 		}
+		if err := b.checkBlockPruned(idx.Block(number)); err != nil {
+			return 0, err
+		}
 		return idx.Block(number), nil
 	} else if h, ok := blockNrOrHash.Hash(); ok {
 		index := b.svc.store.GetBlockIndex(hash.Event(h))
@@ -72,6 +76,27 @@ func (b *EthAPIBackend) ResolveRpcBlockNumberOrHash(ctx context.Context, blockNr
 	return 0, errors.New("unknown header selector")
 }
 
+// checkBlockPruned returns ErrPrunedHistory if the block is deleted by the history retention.
+func (b *EthAPIBackend) checkBlockPruned(n idx.Block) error {
+	start := b.svc.store.GetHistoryStart()
+	if n == 0 || n >= start.Block {
+		return nil
+	}
+	if genesis := b.svc.store.GetGenesisBlockIndex(); genesis != nil && n == *genesis {
+		return nil
+	}
+	return fmt.Errorf("%w: the earliest available block is %d", evmstore.ErrPrunedHistory, start.Block)
+}
+
+// checkEpochPruned returns ErrPrunedHistory if the epoch is deleted by the history retention.
+func (b *EthAPIBackend) checkEpochPruned(epoch idx.Epoch) error {
+	start := b.svc.store.GetHistoryStart()
+	if epoch >= start.Epoch {
+		return nil
+	}
+	return fmt.Errorf("%w: the earliest available epoch is %d", evmstore.ErrPrunedHistory, start.Epoch)
+}
+
 // HeaderByNumber returns evm block header by its number, or nil if not exists.
 func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error) {
 	blk, err := b.BlockByNumber(ctx, number)
@@ -103,6 +128,9 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 	if number == rpc.LatestBlockNumber {
 		blk = b.state.CurrentBlock()
 	} else {
+		if err := b.checkBlockPruned(idx.Block(number)); err != nil {
+			return nil, err
+		}
 		n := uint64(number.Int64())
 		blk = b.state.GetBlock(common.Hash{}, n)
 	}
@@ -116,6 +144,9 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	if number, ok := blockNrOrHash.Number(); ok && (number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber) {
 		header = &b.state.CurrentBlock().EvmHeader
 	} else if number, ok := blockNrOrHash.Number(); ok {
+		if err := b.checkBlockPruned(idx.Block(number)); err != nil {
+			return nil, nil, err
+		}
 		header = b.state.GetHeader(common.Hash{}, uint64(number))
 	} else if h, ok := blockNrOrHash.Hash(); ok {
 		index := b.svc.store.GetBlockIndex(hash.Event(h))
@@ -183,6 +214,9 @@ func (b *EthAPIBackend) GetEventPayload(ctx context.Context, shortEventID string
 	if err != nil {
 		return nil, err
 	}
+	if err := b.checkEpochPruned(id.Epoch()); err != nil {
+		return nil, err
+	}
 	return b.svc.store.GetEventPayload(id), nil
 }
 
@@ -192,6 +226,9 @@ func (b *EthAPIBackend) GetEvent(ctx context.Context, shortEventID string) (*int
 	if err != nil {
 		return nil, err
 	}
+	if err := b.checkEpochPruned(id.Epoch()); err != nil {
+		return nil, err
+	}
 	return b.svc.store.GetEvent(id), nil
 }
 
@@ -234,7 +271,7 @@ func (b *EthAPIBackend) epochWithDefault(ctx context.Context, epoch rpc.BlockNum
 		err = errors.New("epoch is not in range")
 		return
 	}
-	return requested, nil
+	return requested, b.checkEpochPruned(requested)
 }
 
 // ForEachEpochEvent iterates all the events which are observed by head, and accepted by a filter.
@@ -273,7 +310,7 @@ func (b *EthAPIBackend) BlockByHash(ctx context.Context, h common.Hash) (*evmcor
 // GetReceiptsByNumber returns receipts by block number.
 func (b *EthAPIBackend) GetReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (types.Receipts, error) {
 	if !b.svc.config.TxIndex {
-		return nil, errors.New("transactions index is disabled (enable TxIndex and re-process the DAGs)")
+		return nil, fmt.Errorf("%w (enable TxIndex and re-process the DAGs)", evmstore.ErrTxIndexDisabled)
 	}
 
 	if number == rpc.PendingBlockNumber {
@@ -283,6 +320,9 @@ func (b *EthAPIBackend) GetReceiptsByNumber(ctx context.Context, number rpc.Bloc
 		header := b.state.CurrentHeader()
 		number = rpc.BlockNumber(header.Number.Uint64())
 	}
+	if err := b.checkBlockPruned(idx.Block(number)); err != nil {
+		return nil, err
+	}
 
 	block := b.state.GetBlock(common.Hash{}, uint64(number))
 	receipts := b.svc.store.evm.GetReceipts(idx.Block(number), b.signer, block.Hash, block.Transactions)
@@ -336,6 +376,15 @@ func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction)
 	return err
 }
 
+// SendPrivateTx submits the transaction which isn't announced to peers, zero expiry means the default lifetime
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction, expiry idx.Block) error {
+	err := b.svc.SendPrivateTx(signedTx, expiry)
+	if err == nil {
+		tracing.StartTx(signedTx.Hash(), "EthAPIBackend.SendPrivateTx()")
+	}
+	return err
+}
+
 func (b *EthAPIBackend) SubscribeLogsNotify(ch chan<- []*types.Log) notify.Subscription {
 	return b.svc.feed.SubscribeNewLogs(ch)
 }
@@ -344,6 +393,14 @@ func (b *EthAPIBackend) SubscribeNewBlockNotify(ch chan<- evmcore.ChainHeadNotif
 	return b.svc.feed.SubscribeNewBlock(ch)
 }
 
+func (b *EthAPIBackend) SubscribeNewEventsNotify(ch chan<- *inter.EventPayload) notify.Subscription {
+	return b.svc.feed.SubscribeNewEvent(ch)
+}
+
+func (b *EthAPIBackend) SubscribeNewEpochsNotify(ch chan<- idx.Epoch) notify.Subscription {
+	return b.svc.feed.SubscribeNewEpoch(ch)
+}
+
 func (b *EthAPIBackend) SubscribeNewTxsNotify(ch chan<- evmcore.NewTxsNotify) notify.Subscription {
 	return b.svc.txpool.SubscribeNewTxsNotify(ch)
 }
@@ -368,9 +425,17 @@ func (b *EthAPIBackend) GetTxPosition(txHash common.Hash) *evmstore.TxPosition {
 	return b.svc.store.evm.GetTxPosition(txHash)
 }
 
+// GetAddressTxs returns positions of the transactions sent from or to the address.
+func (b *EthAPIBackend) GetAddressTxs(ctx context.Context, addr common.Address, start evmstore.AddressTx, to idx.Block, limit int) ([]evmstore.AddressTx, error) {
+	if !b.svc.config.AddressTxIndex {
+		return nil, errors.New("address transactions index is disabled (enable AddressTxIndex and backfill it with 'opera db index-addresses')")
+	}
+	return b.svc.store.evm.GetAddressTxs(addr, start, to, limit), nil
+}
+
 func (b *EthAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
 	if !b.svc.config.TxIndex {
-		return nil, 0, 0, errors.New("transactions index is disabled (enable TxIndex and re-process the DAG)")
+		return nil, 0, 0, fmt.Errorf("%w (enable TxIndex and re-process the DAG)", evmstore.ErrTxIndexDisabled)
 	}
 
 	position := b.svc.store.evm.GetTxPosition(txHash)
@@ -429,6 +494,26 @@ func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) (types.Transactio
 	return b.svc.txpool.ContentFrom(addr)
 }
 
+func (b *EthAPIBackend) TxPoolTxInfo(txHash common.Hash) *evmcore.TxInfo {
+	return b.svc.txpool.TxInfo(txHash)
+}
+
+// TxOrigination returns whether the local emitters would originate the transaction
+func (b *EthAPIBackend) TxOrigination(tx *types.Transaction) []ethapi.TxOrigination {
+	res := make([]ethapi.TxOrigination, 0, len(b.svc.emitters))
+	for _, em := range b.svc.emitters {
+		o := em.TxOrigination(tx)
+		res = append(res, ethapi.TxOrigination{
+			Validator: o.Validator,
+			Originate: o.Originate,
+			Reason:    o.Reason,
+			Turn:      o.Turn,
+			NextTurn:  o.NextTurn,
+		})
+	}
+	return res
+}
+
 func (b *EthAPIBackend) SuggestGasTipCap(ctx context.Context, certainty uint64) *big.Int {
 	return b.svc.gpo.SuggestTip(certainty)
 }
@@ -465,6 +550,10 @@ func (b *EthAPIBackend) EvmLogIndex() *topicsdb.Index {
 	return b.svc.store.evm.EvmLogs
 }
 
+func (b *EthAPIBackend) EvmTraceIndex() *txtrace.Index {
+	return b.svc.store.evm.EvmTraces
+}
+
 // CurrentEpoch returns current epoch number.
 func (b *EthAPIBackend) CurrentEpoch(ctx context.Context) idx.Epoch {
 	return b.svc.store.GetEpoch()
@@ -524,10 +613,24 @@ func (b *EthAPIBackend) GetEpochBlockState(ctx context.Context, epoch rpc.BlockN
 	if epoch == rpc.LatestBlockNumber {
 		epoch = rpc.BlockNumber(b.svc.store.GetEpoch())
 	}
+	if err := b.checkEpochPruned(idx.Epoch(epoch)); err != nil {
+		return nil, nil, err
+	}
 	bs, es := b.svc.store.GetHistoryBlockEpochState(idx.Epoch(epoch))
 	return bs, es, nil
 }
 
+// GetEpochMetrics returns the validators metrics of a sealed epoch, or the metrics so far for the current epoch.
+// Returns nil if the metrics weren't recorded for the epoch.
+func (b *EthAPIBackend) GetEpochMetrics(ctx context.Context, epoch idx.Epoch) (*iblockproc.EpochMetrics, error) {
+	// Note: loads bs and es atomically to avoid a race condition
+	bs, es := b.svc.store.GetBlockEpochState()
+	if epoch == es.Epoch {
+		return calcEpochMetrics(bs.LastBlock, bs, es), nil
+	}
+	return b.svc.store.GetEpochMetrics(epoch), nil
+}
+
 func (b *EthAPIBackend) CalcBlockExtApi() bool {
 	return b.svc.config.RPCBlockExt
 }
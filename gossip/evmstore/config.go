@@ -1,6 +1,9 @@
 package evmstore
 
 import (
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 )
@@ -31,11 +34,24 @@ type (
 		// Whether to enable greedy gc mode
 		GreedyGC bool
 	}
+	// LivePruningConfig is a config for the online EVM state pruning.
+	LivePruningConfig struct {
+		// Directory of the live entries set, online pruning is disabled if empty
+		Datadir string
+		// Number of the latest blocks whose states are kept
+		KeepBlocks idx.Block
+		// Max number of entries deleted at once
+		BatchSize int
+		// Pause between batches to not hurt the blocks processing
+		BatchPause time.Duration
+	}
 	// StoreConfig is a config for store db.
 	StoreConfig struct {
 		Cache StoreCacheConfig
 		// Enables tracking of SHA3 preimages in the VM
 		EnablePreimageRecording bool
+		// Online EVM state pruning
+		LivePruning LivePruningConfig
 	}
 )
 
@@ -55,6 +71,11 @@ func DefaultStoreConfig(scale cachescale.Func) StoreConfig {
 			TrieDirtyLimit:    scale.U(256 * opt.MiB),
 		},
 		EnablePreimageRecording: true,
+		LivePruning: LivePruningConfig{
+			KeepBlocks: 128,
+			BatchSize:  10000,
+			BatchPause: 100 * time.Millisecond,
+		},
 	}
 }
 
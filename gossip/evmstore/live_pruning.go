@@ -0,0 +1,668 @@
+package evmstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/leveldb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+const (
+	livePruningMarking  = 1
+	livePruningSweeping = 2
+	livePruningFinished = 3
+
+	// markVisited prefixes the entries reached from the kept roots, whole subtrees of such nodes are marked
+	markVisited = 'v'
+	// markWritten prefixes the entries written during pruning, their subtrees aren't necessarily marked
+	markWritten = 'w'
+
+	// rangeCompactionThreshold is the minimal number of deleted entries to trigger the DB compaction
+	rangeCompactionThreshold = 100000
+)
+
+var (
+	livePruningProgressKey = []byte("progress")
+
+	errLivePruningDisabled    = errors.New("live EVM state pruning is disabled")
+	errLivePruningRunning     = errors.New("live EVM state pruning is already running")
+	errLivePruningInterrupted = errors.New("live EVM state pruning is interrupted")
+)
+
+// LivePruningStatus describes the progress of the online EVM state pruning.
+type LivePruningStatus struct {
+	Phase       string    `json:"phase"`
+	Started     time.Time `json:"started"`
+	Roots       uint64    `json:"roots"`
+	Marked      uint64    `json:"marked"`
+	Checked     uint64    `json:"checked"`
+	Deleted     uint64    `json:"deleted"`
+	DeletedSize uint64    `json:"deletedSize"`
+	Progress    float64   `json:"progress"`
+	ETA         uint64    `json:"eta"` // seconds
+	Error       string    `json:"error,omitempty"`
+}
+
+// livePruningProgress is the persistent state of a pruning run, it allows to resume the run after restarts.
+type livePruningProgress struct {
+	Phase       uint8
+	Started     uint64
+	Roots       uint64
+	Marked      uint64
+	Cursor      []byte
+	Checked     uint64
+	Deleted     uint64
+	DeletedSize uint64
+}
+
+// livePruner deletes the EVM state entries which are unreachable from the latest states without stopping the node.
+// A run consists of two phases:
+//
+// - marking: every trie node and code reachable from the kept roots is put into the live set
+// - sweeping: the EVM DB is iterated and the entries missing in the live set are deleted in batches
+//
+// Entries written during the run are put into the live set by the write barrier,
+// so the states committed concurrently with the run are never pruned.
+type livePruner struct {
+	cfg LivePruningConfig
+	s   *Store
+	db  kvdb.Store // EVM table without the write barrier
+
+	// barrier orders the writes of EVM entries against the deletion batches
+	barrier sync.RWMutex
+	marks   kvdb.Store // live set, nil if the write barrier is disabled
+
+	mu              sync.Mutex
+	running         bool
+	pending         bool // an interrupted run awaits resumption
+	progress        livePruningProgress
+	resumed         time.Time
+	resumedProgress float64
+	err             error
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+
+	logger.Instance
+}
+
+// StartLivePruning launches the background pruning of the EVM state entries which aren't reachable
+// from the given roots and from the snapshot disk layer.
+// The roots are requested after the write barrier is enabled, so the states committed meanwhile are kept.
+func (s *Store) StartLivePruning(roots func() []common.Hash) error {
+	return s.livePruner.start(roots)
+}
+
+// ResumeLivePruning continues the pruning run interrupted by the node shutdown, if any.
+func (s *Store) ResumeLivePruning(roots func() []common.Hash) error {
+	return s.livePruner.resume(roots)
+}
+
+// LivePruningStatus returns the progress of the online EVM state pruning.
+func (s *Store) LivePruningStatus() LivePruningStatus {
+	return s.livePruner.status()
+}
+
+func newLivePruner(s *Store, db kvdb.Store) *livePruner {
+	p := &livePruner{
+		cfg:      s.cfg.LivePruning,
+		s:        s,
+		db:       db,
+		quit:     make(chan struct{}),
+		Instance: logger.New("live-pruning"),
+	}
+	if p.cfg.BatchSize < 1 {
+		p.cfg.BatchSize = 1
+	}
+	return p
+}
+
+// wrap returns the EVM table which puts the written entries into the live set while pruning is running.
+func (p *livePruner) wrap(db kvdb.Store) kvdb.Store {
+	return &barrierStore{db, p}
+}
+
+func openLiveSet(path string) (kvdb.Store, error) {
+	return leveldb.New(path, 64*opt.MiB, 0, nil, nil)
+}
+
+func readLivePruningProgress(marks kvdb.Store) (*livePruningProgress, error) {
+	b, err := marks.Get(livePruningProgressKey)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	progress := &livePruningProgress{}
+	return progress, rlp.DecodeBytes(b, progress)
+}
+
+func writeLivePruningProgress(marks kvdb.Store, progress livePruningProgress) error {
+	b, err := rlp.EncodeToBytes(&progress)
+	if err != nil {
+		return err
+	}
+	return marks.Put(livePruningProgressKey, b)
+}
+
+func liveSetKey(prefix byte, key []byte) []byte {
+	return append([]byte{prefix}, key[len(key)-common.HashLength:]...)
+}
+
+// recover reopens the live set of an interrupted run.
+// Sweeping has to be guarded by the write barrier right away, whereas marking is restarted from scratch later.
+func (p *livePruner) recover() error {
+	if _, err := os.Stat(p.cfg.Datadir); os.IsNotExist(err) {
+		return nil
+	}
+	marks, err := openLiveSet(p.cfg.Datadir)
+	if err != nil {
+		return err
+	}
+	progress, err := readLivePruningProgress(marks)
+	if err != nil {
+		_ = marks.Close()
+		return err
+	}
+	if progress != nil && progress.Phase == livePruningSweeping {
+		p.activate(marks)
+		p.progress = *progress
+		p.pending = true
+		return nil
+	}
+	_ = marks.Close()
+	p.pending = progress != nil && progress.Phase == livePruningMarking
+	return os.RemoveAll(p.cfg.Datadir)
+}
+
+func (p *livePruner) activate(marks kvdb.Store) {
+	p.barrier.Lock()
+	defer p.barrier.Unlock()
+	p.marks = marks
+}
+
+func (p *livePruner) deactivate() kvdb.Store {
+	p.barrier.Lock()
+	defer p.barrier.Unlock()
+	marks := p.marks
+	p.marks = nil
+	return marks
+}
+
+// markWritten puts the written EVM entry into the live set. Must be called under the barrier read lock.
+func (p *livePruner) markWritten(key []byte) {
+	if p.marks == nil || !IsMptKey(key) {
+		return
+	}
+	if err := p.marks.Put(liveSetKey(markWritten, key), []byte{}); err != nil {
+		p.Log.Crit("Failed to mark written EVM entry", "err", err)
+	}
+}
+
+func (p *livePruner) isLive(key []byte) (bool, error) {
+	ok, err := p.marks.Has(liveSetKey(markVisited, key))
+	if ok || err != nil {
+		return ok, err
+	}
+	return p.marks.Has(liveSetKey(markWritten, key))
+}
+
+func (p *livePruner) start(roots func() []common.Hash) error {
+	if p.cfg.Datadir == "" {
+		return errLivePruningDisabled
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.running {
+		return errLivePruningRunning
+	}
+	if p.pending && p.progress.Phase == livePruningSweeping {
+		p.Log.Info("Resuming live EVM state pruning", "deleted", p.progress.Deleted)
+		p.launch(nil)
+		return nil
+	}
+
+	if marks := p.deactivate(); marks != nil {
+		_ = marks.Close()
+	}
+	if err := os.RemoveAll(p.cfg.Datadir); err != nil {
+		return err
+	}
+	marks, err := openLiveSet(p.cfg.Datadir)
+	if err != nil {
+		return err
+	}
+	progress := livePruningProgress{
+		Phase:   livePruningMarking,
+		Started: uint64(time.Now().UnixNano()),
+	}
+	if err := writeLivePruningProgress(marks, progress); err != nil {
+		_ = marks.Close()
+		return err
+	}
+	// enable the write barrier before the roots are chosen
+	p.activate(marks)
+	p.progress = progress
+	p.err = nil
+	p.Log.Info("Starting live EVM state pruning")
+	p.launch(roots)
+	return nil
+}
+
+func (p *livePruner) resume(roots func() []common.Hash) error {
+	p.mu.Lock()
+	pending := p.pending
+	p.mu.Unlock()
+	if !pending {
+		return nil
+	}
+	return p.start(roots)
+}
+
+// launch starts the run in background, marking is skipped if roots are nil. Must be called under the mutex.
+func (p *livePruner) launch(roots func() []common.Hash) {
+	p.running = true
+	p.pending = false
+	p.resumed = time.Now()
+	p.resumedProgress = cursorProgress(p.progress.Cursor)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		var err error
+		if roots != nil {
+			err = p.mark(roots())
+		}
+		if err == nil {
+			err = p.sweep()
+		}
+		p.finish(err)
+	}()
+}
+
+// stop interrupts the running pruning, it will be resumed after restart.
+func (p *livePruner) stop() {
+	close(p.quit)
+	p.wg.Wait()
+	if marks := p.deactivate(); marks != nil {
+		_ = marks.Close()
+	}
+}
+
+func (p *livePruner) interrupted() bool {
+	select {
+	case <-p.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *livePruner) pause() bool {
+	select {
+	case <-p.quit:
+		return false
+	case <-time.After(p.cfg.BatchPause):
+		return true
+	}
+}
+
+// mark puts every trie node and code reachable from the roots into the live set.
+func (p *livePruner) mark(roots []common.Hash) error {
+	if snaps := p.s.Snaps; snaps != nil {
+		roots = append(roots, snaps.DiskRoot())
+	}
+	p.mu.Lock()
+	p.progress.Roots = uint64(len(roots))
+	p.mu.Unlock()
+
+	var (
+		marked uint64
+		start  = time.Now()
+		logged = time.Now()
+	)
+	visit := func(h common.Hash) (bool, error) {
+		key := liveSetKey(markVisited, h.Bytes())
+		if ok, err := p.marks.Has(key); ok || err != nil {
+			return ok, err
+		}
+		if err := p.marks.Put(key, []byte{}); err != nil {
+			return false, err
+		}
+		marked++
+		if marked%uint64(p.cfg.BatchSize) == 0 {
+			p.mu.Lock()
+			p.progress.Marked = marked
+			p.mu.Unlock()
+			if time.Since(logged) > 8*time.Second {
+				p.Log.Info("Marking live EVM state", "nodes", marked, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+			if !p.pause() {
+				return false, errLivePruningInterrupted
+			}
+		}
+		return false, nil
+	}
+	markTrie := func(it interface {
+		Next(bool) bool
+		Hash() common.Hash
+	}, onLeaf func() error) error {
+		for skip := false; it.Next(!skip); {
+			skip = false
+			if it.Hash() != emptyHash {
+				var err error
+				if skip, err = visit(it.Hash()); err != nil {
+					return err
+				}
+				continue
+			}
+			if onLeaf != nil {
+				if err := onLeaf(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, root := range roots {
+		if root == emptyHash || root == types.EmptyRootHash {
+			continue
+		}
+		stateTrie, err := p.s.EvmState.OpenTrie(root)
+		if err != nil {
+			return err
+		}
+		stateIt := stateTrie.NodeIterator(nil)
+		err = markTrie(stateIt, func() error {
+			if !stateIt.Leaf() {
+				return nil
+			}
+			addrHash := common.BytesToHash(stateIt.LeafKey())
+			var account state.Account
+			if err := rlp.DecodeBytes(stateIt.LeafBlob(), &account); err != nil {
+				return fmt.Errorf("failed to decode account %s: %v", addrHash.String(), err)
+			}
+			if codeHash := common.BytesToHash(account.CodeHash); codeHash != emptyCodeHash {
+				if _, err := visit(codeHash); err != nil {
+					return err
+				}
+			}
+			if account.Root == types.EmptyRootHash {
+				return nil
+			}
+			storageTrie, err := p.s.EvmState.OpenStorageTrie(addrHash, account.Root)
+			if err != nil {
+				return fmt.Errorf("failed to open storage trie %s at %s addr: %v", account.Root.String(), addrHash.String(), err)
+			}
+			storageIt := storageTrie.NodeIterator(nil)
+			if err := markTrie(storageIt, nil); err != nil {
+				return err
+			}
+			return storageIt.Error()
+		})
+		if err != nil {
+			return err
+		}
+		if err := stateIt.Error(); err != nil {
+			return fmt.Errorf("EVM state trie %s iteration error: %v", root.String(), err)
+		}
+	}
+	p.Log.Info("Marked live EVM state", "nodes", marked, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.Marked = marked
+	p.progress.Phase = livePruningSweeping
+	p.progress.Cursor = nil
+	p.resumed = time.Now()
+	p.resumedProgress = 0
+	return writeLivePruningProgress(p.marks, p.progress)
+}
+
+// sweep deletes the EVM entries missing in the live set, starting from the persisted cursor.
+func (p *livePruner) sweep() error {
+	p.mu.Lock()
+	cursor := p.progress.Cursor
+	p.mu.Unlock()
+
+	logged := time.Now()
+	for {
+		if p.interrupted() {
+			return errLivePruningInterrupted
+		}
+		var (
+			candidates [][]byte
+			sizes      []int
+			scanned    uint64
+			exhausted  = true
+		)
+		it := p.db.NewIterator(nil, cursor)
+		for it.Next() {
+			key := common.CopyBytes(it.Key())
+			scanned++
+			cursor = append(key, 0) // next key after the current one
+			if IsMptKey(key) {
+				live, err := p.isLive(key)
+				if err != nil {
+					it.Release()
+					return err
+				}
+				if !live {
+					candidates = append(candidates, key)
+					sizes = append(sizes, len(key)+len(it.Value()))
+				}
+			}
+			if len(candidates) >= p.cfg.BatchSize || scanned >= 16*uint64(p.cfg.BatchSize) {
+				exhausted = false
+				break
+			}
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return err
+		}
+
+		deleted, deletedSize, err := p.deleteBatch(candidates, sizes)
+		if err != nil {
+			return err
+		}
+
+		p.mu.Lock()
+		p.progress.Cursor = cursor
+		p.progress.Checked += scanned
+		p.progress.Deleted += deleted
+		p.progress.DeletedSize += deletedSize
+		progress := p.progress
+		p.mu.Unlock()
+		if err := writeLivePruningProgress(p.marks, progress); err != nil {
+			return err
+		}
+		if time.Since(logged) > 8*time.Second {
+			status := p.status()
+			p.Log.Info("Pruning EVM state data", "nodes", progress.Deleted, "size", common.StorageSize(progress.DeletedSize),
+				"progress", fmt.Sprintf("%.2f%%", status.Progress*100), "eta", common.PrettyDuration(time.Duration(status.ETA)*time.Second))
+			logged = time.Now()
+		}
+		if exhausted {
+			return nil
+		}
+		if !p.pause() {
+			return errLivePruningInterrupted
+		}
+	}
+}
+
+// deleteBatch deletes the entries which weren't marked by the write barrier since they were checked.
+func (p *livePruner) deleteBatch(keys [][]byte, sizes []int) (deleted, deletedSize uint64, err error) {
+	if len(keys) == 0 {
+		return 0, 0, nil
+	}
+	p.barrier.Lock()
+	defer p.barrier.Unlock()
+	batch := p.db.NewBatch()
+	for i, key := range keys {
+		live, err := p.isLive(key)
+		if err != nil {
+			return 0, 0, err
+		}
+		if live {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			return 0, 0, err
+		}
+		deleted++
+		deletedSize += uint64(sizes[i])
+	}
+	return deleted, deletedSize, batch.Write()
+}
+
+// finish drops the live set unless the run is interrupted, and compacts the DB after a successful run.
+func (p *livePruner) finish(err error) {
+	if err == errLivePruningInterrupted {
+		p.mu.Lock()
+		p.running = false
+		p.pending = true
+		p.mu.Unlock()
+		p.Log.Info("Live EVM state pruning is interrupted, it will be resumed after restart")
+		return
+	}
+	if marks := p.deactivate(); marks != nil {
+		_ = marks.Close()
+	}
+	if rmErr := os.RemoveAll(p.cfg.Datadir); rmErr != nil {
+		p.Log.Warn("Failed to remove live EVM state set", "path", p.cfg.Datadir, "err", rmErr)
+	}
+
+	p.mu.Lock()
+	p.running = false
+	p.err = err
+	if err == nil {
+		p.progress.Phase = livePruningFinished
+	}
+	progress := p.progress
+	p.mu.Unlock()
+
+	if err != nil {
+		p.Log.Error("Live EVM state pruning failed", "err", err)
+		return
+	}
+	elapsed := time.Since(time.Unix(0, int64(progress.Started)))
+	p.Log.Info("Live EVM state pruning finished", "nodes", progress.Deleted, "size", common.StorageSize(progress.DeletedSize),
+		"elapsed", common.PrettyDuration(elapsed))
+
+	// remove the deleted data from the disk right away, small prunings are left for the background compaction
+	if progress.Deleted < rangeCompactionThreshold {
+		return
+	}
+	for b := 0x00; b <= 0xf0 && !p.interrupted(); b += 0x10 {
+		var (
+			start = []byte{byte(b)}
+			end   = []byte{byte(b + 0x10)}
+		)
+		if b == 0xf0 {
+			end = nil
+		}
+		if err := p.db.Compact(start, end); err != nil {
+			p.Log.Warn("EVM DB compaction failed", "err", err)
+			return
+		}
+	}
+}
+
+func (p *livePruner) status() LivePruningStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st := LivePruningStatus{
+		Phase:       "idle",
+		Roots:       p.progress.Roots,
+		Marked:      p.progress.Marked,
+		Checked:     p.progress.Checked,
+		Deleted:     p.progress.Deleted,
+		DeletedSize: p.progress.DeletedSize,
+	}
+	if p.progress.Started != 0 {
+		st.Started = time.Unix(0, int64(p.progress.Started))
+	}
+	switch {
+	case p.err != nil:
+		st.Phase = "failed"
+		st.Error = p.err.Error()
+	case p.pending:
+		st.Phase = "interrupted"
+		st.Progress = cursorProgress(p.progress.Cursor)
+	case p.progress.Phase == livePruningMarking:
+		st.Phase = "marking"
+	case p.progress.Phase == livePruningSweeping:
+		st.Phase = "sweeping"
+		st.Progress = cursorProgress(p.progress.Cursor)
+		if done := st.Progress - p.resumedProgress; done > 0 {
+			eta := time.Since(p.resumed).Seconds() * (1 - st.Progress) / done
+			st.ETA = uint64(eta)
+		}
+	case p.progress.Phase == livePruningFinished:
+		st.Phase = "finished"
+		st.Progress = 1
+	}
+	return st
+}
+
+// cursorProgress estimates the swept fraction of the DB, assuming the keys are mostly uniformly distributed hashes.
+func cursorProgress(cursor []byte) float64 {
+	var prefix [8]byte
+	copy(prefix[:], cursor)
+	return float64(binary.BigEndian.Uint64(prefix[:])) / math.MaxUint64
+}
+
+// barrierStore is the EVM table which marks the written entries while pruning is running.
+type barrierStore struct {
+	kvdb.Store
+	p *livePruner
+}
+
+type barrierBatch struct {
+	kvdb.Batch
+	p *livePruner
+}
+
+// Put marks the entry as live and writes it.
+func (s *barrierStore) Put(key []byte, value []byte) error {
+	s.p.barrier.RLock()
+	defer s.p.barrier.RUnlock()
+	s.p.markWritten(key)
+	return s.Store.Put(key, value)
+}
+
+// NewBatch creates a batch which marks the written entries.
+func (s *barrierStore) NewBatch() kvdb.Batch {
+	return &barrierBatch{s.Store.NewBatch(), s.p}
+}
+
+// Put marks the entry as live and adds it into the batch.
+// The entry is marked before the batch is written, so it's never deleted after the write.
+func (b *barrierBatch) Put(key []byte, value []byte) error {
+	b.p.barrier.RLock()
+	b.p.markWritten(key)
+	b.p.barrier.RUnlock()
+	return b.Batch.Put(key, value)
+}
+
+// Write writes the batch, it's never interleaved with a deletion batch.
+func (b *barrierBatch) Write() error {
+	b.p.barrier.RLock()
+	defer b.p.barrier.RUnlock()
+	return b.Batch.Write()
+}
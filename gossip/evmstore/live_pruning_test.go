@@ -0,0 +1,81 @@
+package evmstore
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLivePruning(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "livepruning")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cfg := LiteStoreConfig()
+	cfg.LivePruning.Datadir = filepath.Join(dir, "marks")
+	cfg.LivePruning.BatchSize = 3
+	cfg.LivePruning.BatchPause = 0
+	store := NewStore(memorydb.NewProducer(""), cfg)
+	defer store.Close()
+
+	commit := func(from common.Hash, salt int64) common.Hash {
+		statedb, err := state.New(from, store.EvmState, nil)
+		require.NoError(err)
+		for i := int64(1); i <= 20; i++ {
+			addr := common.BigToAddress(big.NewInt(i))
+			statedb.SetBalance(addr, big.NewInt(i*salt))
+			statedb.SetState(addr, common.BigToHash(big.NewInt(salt)), common.BigToHash(big.NewInt(i)))
+			statedb.SetCode(addr, []byte{byte(i), byte(salt)})
+		}
+		root, err := statedb.Commit(true)
+		require.NoError(err)
+		require.NoError(store.EvmState.TrieDB().Commit(root, false, nil))
+		return root
+	}
+	checkState := func(root common.Hash) {
+		// read from the disk to bypass the trie caches
+		statedb, err := state.New(root, state.NewDatabase(store.EvmDb), nil)
+		require.NoError(err)
+		it := state.NewNodeIterator(statedb)
+		for it.Next() {
+		}
+		require.NoError(it.Error)
+	}
+
+	root1 := commit(common.Hash{}, 1)
+	root2 := commit(root1, 2)
+	var root3 common.Hash
+	require.NoError(store.StartLivePruning(func() []common.Hash {
+		// the state committed after the write barrier is enabled must be kept
+		root3 = commit(root2, 3)
+		return []common.Hash{root2}
+	}))
+	require.Equal(errLivePruningRunning, store.StartLivePruning(nil))
+
+	var status LivePruningStatus
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(10 * time.Millisecond) {
+		if status = store.LivePruningStatus(); status.Phase == "finished" || status.Phase == "failed" {
+			break
+		}
+	}
+	require.Equal("finished", status.Phase, status.Error)
+	require.NotZero(status.Deleted)
+
+	checkState(root2)
+	checkState(root3)
+	has, err := store.EVMDB().Has(root1.Bytes())
+	require.NoError(err)
+	require.False(has)
+	_, err = os.Stat(cfg.LivePruning.Datadir)
+	require.True(os.IsNotExist(err))
+}
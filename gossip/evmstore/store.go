@@ -22,7 +22,9 @@ import (
 	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
 	"github.com/Fantom-foundation/go-opera/logger"
 	"github.com/Fantom-foundation/go-opera/topicsdb"
+	"github.com/Fantom-foundation/go-opera/txtrace"
 	"github.com/Fantom-foundation/go-opera/utils/adapters/kvdb2ethdb"
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/freezer"
 	"github.com/Fantom-foundation/go-opera/utils/rlpstore"
 )
 
@@ -38,12 +40,14 @@ type Store struct {
 		Receipts    kvdb.Store `table:"r"`
 		TxPositions kvdb.Store `table:"x"`
 		Txs         kvdb.Store `table:"X"`
+		AddressTxs  kvdb.Store `table:"a"`
 	}
 
-	EvmDb    ethdb.Database
-	EvmState state.Database
-	EvmLogs  *topicsdb.Index
-	Snaps    *snapshot.Tree
+	EvmDb     ethdb.Database
+	EvmState  state.Database
+	EvmLogs   *topicsdb.Index
+	EvmTraces *txtrace.Index
+	Snaps     *snapshot.Tree
 
 	cache struct {
 		TxPositions *wlru.Cache `cache:"-"` // store by pointer
@@ -55,6 +59,10 @@ type Store struct {
 
 	triegc *prque.Prque // Priority queue mapping block numbers to tries to gc
 
+	livePruner *livePruner
+
+	frozenReceipts *freezer.Table
+
 	logger.Instance
 }
 
@@ -75,9 +83,17 @@ func NewStore(dbs kvdb.DBProducer, cfg StoreConfig) *Store {
 	if err != nil {
 		s.Log.Crit("Failed to open tables", "err", err)
 	}
+	s.livePruner = newLivePruner(s, s.table.Evm)
+	if cfg.LivePruning.Datadir != "" {
+		s.table.Evm = s.livePruner.wrap(s.table.Evm)
+		if err := s.livePruner.recover(); err != nil {
+			s.Log.Crit("Failed to recover live EVM state pruning", "err", err)
+		}
+	}
 
 	s.initEVMDB()
 	s.EvmLogs = topicsdb.New(dbs)
+	s.EvmTraces = txtrace.New(dbs)
 	s.initCache()
 
 	return s
@@ -89,10 +105,12 @@ func (s *Store) Close() {
 		return nil
 	}
 
+	s.livePruner.stop()
 	_ = table.CloseTables(&s.table)
 	table.MigrateTables(&s.table, nil)
 	table.MigrateCaches(&s.cache, setnil)
 	s.EvmLogs.Close()
+	s.EvmTraces.Close()
 }
 
 func (s *Store) initCache() {
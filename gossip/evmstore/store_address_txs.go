@@ -0,0 +1,87 @@
+package evmstore
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Fantom-foundation/go-opera/utils/signers/internaltx"
+)
+
+// AddressTx is a position of a transaction sent from or to an address.
+type AddressTx struct {
+	Block       idx.Block
+	BlockOffset uint32
+}
+
+func addressTxKey(addr common.Address, pos AddressTx) []byte {
+	key := make([]byte, 0, common.AddressLength+8+4)
+	key = append(key, addr.Bytes()...)
+	key = append(key, pos.Block.Bytes()...)
+	key = append(key, bigendian.Uint32ToBytes(pos.BlockOffset)...)
+	return key
+}
+
+func (s *Store) setAddressTx(addr common.Address, pos AddressTx) {
+	if err := s.table.AddressTxs.Put(addressTxKey(addr, pos), []byte{}); err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+// IndexAddressTxs indexes the block transactions by their senders and recipients.
+// Receipts are used to index the created contracts.
+func (s *Store) IndexAddressTxs(n idx.Block, txs types.Transactions, receipts types.Receipts, signer types.Signer) {
+	forEachAddressTx(n, txs, receipts, signer, s.setAddressTx)
+}
+
+// unindexAddressTxs deletes the address index entries of the block transactions.
+func (s *Store) unindexAddressTxs(n idx.Block, txs types.Transactions, receipts types.Receipts, signer types.Signer) {
+	forEachAddressTx(n, txs, receipts, signer, func(addr common.Address, pos AddressTx) {
+		if err := s.table.AddressTxs.Delete(addressTxKey(addr, pos)); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	})
+}
+
+func forEachAddressTx(n idx.Block, txs types.Transactions, receipts types.Receipts, signer types.Signer, fn func(addr common.Address, pos AddressTx)) {
+	for i, tx := range txs {
+		pos := AddressTx{
+			Block:       n,
+			BlockOffset: uint32(i),
+		}
+		if from, err := internaltx.Sender(signer, tx); err == nil {
+			fn(from, pos)
+		}
+		if tx.To() != nil {
+			fn(*tx.To(), pos)
+		} else if i < len(receipts) {
+			fn(receipts[i].ContractAddress, pos)
+		}
+	}
+}
+
+// GetAddressTxs returns up to limit positions of the transactions sent from or to the address,
+// starting from the given position and not later than the given block. Result is in the chain order.
+func (s *Store) GetAddressTxs(addr common.Address, start AddressTx, to idx.Block, limit int) []AddressTx {
+	startKey := addressTxKey(addr, start)
+	it := s.table.AddressTxs.NewIterator(addr.Bytes(), startKey[common.AddressLength:])
+	defer it.Release()
+
+	res := make([]AddressTx, 0, limit)
+	for len(res) < limit && it.Next() {
+		key := it.Key()[common.AddressLength:]
+		pos := AddressTx{
+			Block:       idx.BytesToBlock(key[:8]),
+			BlockOffset: bigendian.BytesToUint32(key[8:]),
+		}
+		if pos.Block > to {
+			break
+		}
+		res = append(res, pos)
+	}
+	if it.Error() != nil {
+		s.Log.Crit("Failed to iterate address txs", "err", it.Error())
+	}
+	return res
+}
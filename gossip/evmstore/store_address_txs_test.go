@@ -0,0 +1,47 @@
+package evmstore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+)
+
+func TestStoreAddressTxs(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	signer := types.LatestSignerForChainID(big.NewInt(1))
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	var (
+		a        = common.Address{1}
+		b        = common.Address{2}
+		contract = common.Address{3}
+	)
+	signed := func(nonce uint64, to *common.Address) *types.Transaction {
+		tx, err := types.SignTx(types.NewTx(&types.LegacyTx{Nonce: nonce, To: to, Gas: 21000, GasPrice: big.NewInt(1)}), signer, key)
+		require.NoError(err)
+		return tx
+	}
+
+	store := cachedStore()
+	store.IndexAddressTxs(1, types.Transactions{signed(0, &a), signed(1, &b)}, nil, signer)
+	store.IndexAddressTxs(5, types.Transactions{signed(2, nil)}, types.Receipts{{ContractAddress: contract}}, signer)
+	store.IndexAddressTxs(7, types.Transactions{signed(3, &a)}, nil, signer)
+
+	require.Equal([]AddressTx{{1, 0}, {1, 1}, {5, 0}, {7, 0}}, store.GetAddressTxs(sender, AddressTx{}, 10, 10))
+	require.Equal([]AddressTx{{1, 1}, {5, 0}}, store.GetAddressTxs(sender, AddressTx{1, 1}, 10, 2))
+	require.Equal([]AddressTx{{5, 0}}, store.GetAddressTxs(sender, AddressTx{2, 0}, 6, 10))
+	require.Equal([]AddressTx{{1, 0}, {7, 0}}, store.GetAddressTxs(a, AddressTx{}, 10, 10))
+	require.Equal([]AddressTx{{1, 1}}, store.GetAddressTxs(b, AddressTx{}, 10, 10))
+	require.Equal([]AddressTx{{5, 0}}, store.GetAddressTxs(contract, AddressTx{}, 10, 10))
+	require.Empty(store.GetAddressTxs(common.Address{4}, AddressTx{}, 10, 10))
+}
@@ -0,0 +1,44 @@
+package evmstore
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/freezer"
+)
+
+// AttachFreezer makes the receipts, which are moved into the freezer table, readable.
+func (s *Store) AttachFreezer(receipts *freezer.Table) {
+	s.frozenReceipts = receipts
+}
+
+func (s *Store) getFrozenReceiptsRLP(n idx.Block) rlp.RawValue {
+	if s.frozenReceipts == nil {
+		return nil
+	}
+	buf, err := s.frozenReceipts.Get(uint64(n))
+	if err == freezer.ErrOutOfBounds || len(buf) == 0 {
+		return nil
+	}
+	if err != nil {
+		s.Log.Crit("Failed to read freezer", "err", err)
+	}
+	return buf
+}
+
+// GetRawReceiptsRLPNotFrozen returns raw receipts of the block if they aren't moved into the freezer yet.
+func (s *Store) GetRawReceiptsRLPNotFrozen(n idx.Block) rlp.RawValue {
+	buf, err := s.table.Receipts.Get(n.Bytes())
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	return buf
+}
+
+// DelFrozenReceipts deletes the receipts of the block, which are moved into the freezer, from the key-value database.
+func (s *Store) DelFrozenReceipts(n idx.Block) {
+	if err := s.table.Receipts.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+	s.cache.Receipts.Remove(n)
+}
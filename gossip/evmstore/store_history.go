@@ -0,0 +1,52 @@
+package evmstore
+
+import (
+	"errors"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrPrunedHistory is returned for the data which is deleted by the history retention.
+var ErrPrunedHistory = errors.New("pruned history")
+
+// PruneBlock deletes the receipts, the transactions positions, the non-event transactions
+// and the address index entries of the block.
+func (s *Store) PruneBlock(n idx.Block, blockHash common.Hash, txs types.Transactions, signer types.Signer) {
+	receiptsStorage, _ := s.GetRawReceipts(n)
+	receipts, err := UnwrapStorageReceipts(receiptsStorage, n, signer, blockHash, txs)
+	if err != nil {
+		receipts = nil
+	}
+	s.unindexAddressTxs(n, txs, receipts, signer)
+
+	for _, tx := range txs {
+		txid := tx.Hash()
+		if pos := s.GetTxPosition(txid); pos != nil && pos.Block == n {
+			if err := s.table.TxPositions.Delete(txid.Bytes()); err != nil {
+				s.Log.Crit("Failed to delete key", "err", err)
+			}
+			s.cache.TxPositions.Remove(txid.String())
+		}
+		if err := s.table.Txs.Delete(txid.Bytes()); err != nil {
+			s.Log.Crit("Failed to delete key", "err", err)
+		}
+	}
+
+	if err := s.table.Receipts.Delete(n.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+	s.cache.Receipts.Remove(n)
+	s.cache.EvmBlocks.Remove(n)
+}
+
+// PruneLogsAndTraces deletes the indexed logs and transaction traces of block range.
+func (s *Store) PruneLogsAndTraces(from, to idx.Block) {
+	if err := s.EvmLogs.PruneBlocks(from, to); err != nil {
+		s.Log.Crit("Failed to prune logs", "err", err)
+	}
+	if err := s.EvmTraces.PruneBlocks(from, to); err != nil {
+		s.Log.Crit("Failed to prune traces", "err", err)
+	}
+}
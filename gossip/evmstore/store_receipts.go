@@ -46,6 +46,9 @@ func (s *Store) GetRawReceiptsRLP(n idx.Block) rlp.RawValue {
 	if err != nil {
 		s.Log.Crit("Failed to get key-value", "err", err)
 	}
+	if buf == nil {
+		return s.getFrozenReceiptsRLP(n)
+	}
 	return buf
 }
 
@@ -5,11 +5,16 @@ package evmstore
 */
 
 import (
+	"errors"
+
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// ErrTxIndexDisabled is returned for the transactions data if the transactions index isn't maintained.
+var ErrTxIndexDisabled = errors.New("transactions index is disabled")
+
 type TxPosition struct {
 	Block       idx.Block
 	Event       hash.Event
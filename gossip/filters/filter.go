@@ -126,6 +126,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	begin := idx.Block(f.begin)
 	if f.begin < 0 {
 		begin = head
+	} else if _, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(begin)); err != nil {
+		// the range starts at a pruned block
+		return nil, err
 	}
 	end := idx.Block(f.end)
 	if f.end < 0 {
@@ -0,0 +1,152 @@
+package gossip
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// DefaultFreezerConfig returns default config of the migration into the freezer
+func DefaultFreezerConfig() FreezerConfig {
+	return FreezerConfig{
+		Epochs:      2,
+		Period:      time.Minute,
+		BatchBlocks: 1000,
+	}
+}
+
+// Validate checks the migration into the freezer config
+func (c *FreezerConfig) Validate() error {
+	if c.Epochs < 2 {
+		// the events of the latest sealed epoch are needed by the peers which are finishing the epoch
+		return errors.New("freezer Epochs has to be at least 2")
+	}
+	if c.Period <= 0 || c.BatchBlocks == 0 {
+		return errors.New("freezer Period and BatchBlocks have to be positive")
+	}
+	return nil
+}
+
+// freezerMigrator periodically moves the finalized blocks, receipts and events of old epochs into the freezer
+type freezerMigrator struct {
+	svc *Service
+	cfg FreezerConfig
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newFreezerMigrator(svc *Service, cfg FreezerConfig) *freezerMigrator {
+	return &freezerMigrator{
+		svc:  svc,
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+func (m *freezerMigrator) loop() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.cfg.Period)
+	defer ticker.Stop()
+	for {
+		m.migrate()
+		select {
+		case <-ticker.C:
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+func (m *freezerMigrator) Start() {
+	if !m.svc.store.HasFreezer() {
+		return
+	}
+	m.wg.Add(1)
+	go m.loop()
+}
+
+func (m *freezerMigrator) Stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+func (m *freezerMigrator) stopped() bool {
+	select {
+	case <-m.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// migrate moves blocks and epochs older than the kept epochs into the freezer.
+// Each step appends the data into the freezer before deleting it from the key-value DBs.
+func (m *freezerMigrator) migrate() {
+	s := m.svc
+	s.historyMu.Lock()
+	defer s.historyMu.Unlock()
+	current := s.store.GetEpoch()
+	if current <= m.cfg.Epochs {
+		return
+	}
+	target := current - m.cfg.Epochs
+	hbs, _ := s.store.GetHistoryBlockEpochState(target)
+	if hbs == nil {
+		return
+	}
+	cutoff := hbs.LastBlock.Idx + 1
+
+	progress := s.store.GetFreezerProgress()
+	if progress.Block == 0 {
+		progress.Block = s.store.firstStoredBlock()
+	}
+	if progress.Epoch == 0 {
+		progress.Epoch = s.store.firstStoredEpoch()
+		if progress.Epoch == 0 {
+			progress.Epoch = target
+		}
+	}
+	if progress.Block < cutoff || progress.Epoch < target {
+		log.Info("Moving history into freezer", "epoch", progress.Epoch, "until_epoch", target, "block", progress.Block, "until_block", cutoff)
+	}
+	for from := progress.Block; from < cutoff; {
+		if m.stopped() {
+			return
+		}
+		to := from + m.cfg.BatchBlocks - 1
+		if to >= cutoff {
+			to = cutoff - 1
+		}
+		if err := s.store.freezeBlocks(from, to); err != nil {
+			m.fail(err)
+			return
+		}
+		s.withEngineLocked(func() {
+			s.store.delFrozenBlocks(from, to)
+		})
+		from = to + 1
+	}
+	for epoch := progress.Epoch; epoch < target; epoch++ {
+		if m.stopped() {
+			return
+		}
+		if err := s.store.freezeEpoch(epoch); err != nil {
+			m.fail(err)
+			return
+		}
+		s.withEngineLocked(func() {
+			s.store.delFrozenEpoch(epoch)
+		})
+	}
+}
+
+// fail discards the items of the failed migration step
+func (m *freezerMigrator) fail(err error) {
+	log.Error("Failed to move history into freezer", "err", err)
+	if err := m.svc.store.rollbackFreezer(); err != nil {
+		log.Crit("Failed to rollback freezer", "err", err)
+	}
+}
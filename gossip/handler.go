@@ -31,6 +31,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/eventcheck/heavycheck"
 	"github.com/Fantom-foundation/go-opera/eventcheck/parentlesscheck"
 	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brprocessor"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream/brstreamleecher"
@@ -156,6 +157,12 @@ type handler struct {
 	epSeeder    *epstreamseeder.Seeder
 	epProcessor *epprocessor.Processor
 
+	peerScores *peerscore.Scores
+	// enode IDs of sentries or validators behind this sentry
+	privatePeers map[string]bool
+	// enode IDs of peers which private transactions are forwarded to
+	privateTxPeers map[string]bool
+
 	process processCallback
 
 	txFetcher *itemsfetcher.Fetcher
@@ -212,6 +219,9 @@ func newHandler(
 		process:              c.process,
 		checkers:             c.checkers,
 		peers:                newPeerSet(),
+		peerScores:           peerscore.New(c.config.Protocol.PeerScore),
+		privatePeers:         c.config.Sentry.privatePeersSet(),
+		privateTxPeers:       c.config.privateTxPeersSet(),
 		engineMu:             c.engineMu,
 		txsyncCh:             make(chan *txsync),
 		quitSync:             make(chan struct{}),
@@ -288,6 +298,8 @@ func newHandler(
 			}
 			return p.progress.Epoch
 		},
+		PeerScore: h.peerScores.Score,
+		Stalled:   h.stalledSession,
 	})
 	h.dagSeeder = dagstreamseeder.New(h.config.Protocol.DagStreamSeeder, dagstreamseeder.Callbacks{
 		ForEachEvent: c.s.ForEachEventRLP,
@@ -319,11 +331,13 @@ func newHandler(
 		},
 		PeerBlock: func(peer string) idx.Block {
 			p := h.peers.Peer(peer)
-			if p == nil || p.Useless() {
+			if p == nil || p.Useless() || !p.progress.HasBlock(h.store.GetLlrState().LowestBlockToFill) {
 				return 0
 			}
 			return p.progress.LastBlockIdx
 		},
+		PeerScore: h.peerScores.Score,
+		Stalled:   h.stalledSession,
 	})
 	h.bvSeeder = bvstreamseeder.New(h.config.Protocol.BvStreamSeeder, bvstreamseeder.Callbacks{
 		Iterate: h.store.IterateOverlappingBlockVotesRLP,
@@ -347,7 +361,7 @@ func newHandler(
 			}
 			return end
 		},
-		IsProcessed: h.store.HasBlock,
+		IsProcessed: h.store.HasVerifiedBlock,
 		RequestChunk: func(peer string, r brstream.Request) error {
 			p := h.peers.Peer(peer)
 			if p == nil {
@@ -360,32 +374,35 @@ func newHandler(
 		},
 		PeerBlock: func(peer string) idx.Block {
 			p := h.peers.Peer(peer)
-			if p == nil || p.Useless() {
+			if p == nil || p.Useless() || !p.progress.HasBlock(h.store.GetLlrState().LowestBlockToFill) {
 				return 0
 			}
 			return p.progress.LastBlockIdx
 		},
+		PeerScore: h.peerScores.Score,
+		Stalled:   h.stalledSession,
 	})
 	h.brSeeder = brstreamseeder.New(h.config.Protocol.BrStreamSeeder, brstreamseeder.Callbacks{
 		Iterate: h.store.IterateFullBlockRecordsRLP,
 	})
 
 	h.epProcessor = h.makeEpProcessor(h.checkers)
+	lowestEpochToFetch := func() idx.Epoch {
+		llrs := h.store.GetLlrState()
+		if llrs.LowestEpochToFill < llrs.LowestEpochToDecide {
+			return llrs.LowestEpochToFill
+		}
+		return llrs.LowestEpochToDecide
+	}
 	h.epLeecher = epstreamleecher.New(h.config.Protocol.EpStreamLeecher, epstreamleecher.Callbacks{
-		LowestEpochToFetch: func() idx.Epoch {
-			llrs := h.store.GetLlrState()
-			if llrs.LowestEpochToFill < llrs.LowestEpochToDecide {
-				return llrs.LowestEpochToFill
-			}
-			return llrs.LowestEpochToDecide
-		},
+		LowestEpochToFetch: lowestEpochToFetch,
 		MaxEpochToFetch: func() idx.Epoch {
 			if !h.syncStatus.RequestLLR() {
 				return 0
 			}
 			return h.store.GetLlrState().LowestEpochToDecide + 10000
 		},
-		IsProcessed: h.store.HasHistoryBlockEpochState,
+		IsProcessed: h.store.HasVerifiedHistoryBlockEpochState,
 		RequestChunk: func(peer string, r epstream.Request) error {
 			p := h.peers.Peer(peer)
 			if p == nil {
@@ -398,11 +415,13 @@ func newHandler(
 		},
 		PeerEpoch: func(peer string) idx.Epoch {
 			p := h.peers.Peer(peer)
-			if p == nil || p.Useless() {
+			if p == nil || p.Useless() || p.progress.LowestEpoch > lowestEpochToFetch() {
 				return 0
 			}
 			return p.progress.Epoch
 		},
+		PeerScore: h.peerScores.Score,
+		Stalled:   h.stalledSession,
 	})
 	h.epSeeder = epstreamseeder.New(h.config.Protocol.EpStreamSeeder, epstreamseeder.Callbacks{
 		Iterate: h.store.IterateEpochPacksRLP,
@@ -415,11 +434,43 @@ func (h *handler) peerMisbehaviour(peer string, err error) bool {
 	if eventcheck.IsBan(err) {
 		log.Warn("Dropping peer due to a misbehaviour", "peer", peer, "err", err)
 		h.removePeer(peer)
+		h.peerBehaviour(peer, peerscore.InvalidItem)
 		return true
 	}
 	return false
 }
 
+// peerBehaviour updates the peer score. The peer is disconnected if it gets banned.
+func (h *handler) peerBehaviour(peer string, b peerscore.Behaviour) {
+	if len(peer) == 0 || !h.peerScores.Add(peer, b) {
+		return
+	}
+	p := h.peers.Peer(peer)
+	if h.privatePeers[peer] || p != nil && p.Peer.Info().Network.Trusted {
+		return
+	}
+	log.Warn("Banning peer due to a low score", "peer", peer, "reason", b, "duration", h.config.Protocol.PeerScore.BanDuration)
+	h.removePeer(peer)
+}
+
+// stalledSession penalizes the peer for a stalled stream session.
+// The leechers call it under their locks, while a ban unregisters the peer from the leechers,
+// so the penalty is applied asynchronously.
+func (h *handler) stalledSession(peer string) {
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		h.peerBehaviour(peer, peerscore.StalledSession)
+	}()
+}
+
+// chunkBehaviour updates the peer score according to the stream chunk content
+func (h *handler) chunkBehaviour(peer string, session *peerscore.SessionStats, sessionID uint32, items int, newItems int) {
+	if b, ok := session.AddChunk(sessionID, items, newItems, h.config.Protocol.PeerScore.MaxDuplicatesRatio); ok {
+		h.peerBehaviour(peer, b)
+	}
+}
+
 func (h *handler) makeDagProcessor(checkers *eventcheck.Checkers) *dagprocessor.Processor {
 	// checkers
 	lightCheck := func(e dag.Event) error {
@@ -486,6 +537,7 @@ func (h *handler) makeDagProcessor(checkers *eventcheck.Checkers) *dagprocessor.
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming event rejected", "event", e.ID().String(), "creator", e.Creator(), "err", err)
 					h.removePeer(peer)
+					h.peerBehaviour(peer, peerscore.InvalidItem)
 				}
 			},
 
@@ -530,6 +582,7 @@ func (h *handler) makeBvProcessor(checkers *eventcheck.Checkers) *bvprocessor.Pr
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming BVs rejected", "BVs", bvs.Signed.Locator.ID(), "creator", bvs.Signed.Locator.Creator, "err", err)
 					h.removePeer(peer)
+					h.peerBehaviour(peer, peerscore.InvalidItem)
 				}
 			},
 			Check: allChecker.Enqueue,
@@ -547,6 +600,7 @@ func (h *handler) makeBrProcessor() *brprocessor.Processor {
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming BR rejected", "block", br.Idx, "err", err)
 					h.removePeer(peer)
+					h.peerBehaviour(peer, peerscore.InvalidItem)
 				}
 			},
 		},
@@ -575,12 +629,14 @@ func (h *handler) makeEpProcessor(checkers *eventcheck.Checkers) *epprocessor.Pr
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming EV rejected", "event", ev.Signed.Locator.ID(), "creator", ev.Signed.Locator.Creator, "err", err)
 					h.removePeer(peer)
+					h.peerBehaviour(peer, peerscore.InvalidItem)
 				}
 			},
 			ReleasedER: func(er ier.LlrIdxFullEpochRecord, peer string, err error) {
 				if eventcheck.IsBan(err) {
 					log.Warn("Incoming ER rejected", "epoch", er.Idx, "err", err)
 					h.removePeer(peer)
+					h.peerBehaviour(peer, peerscore.InvalidItem)
 				}
 			},
 			CheckEV: allChecker.Enqueue,
@@ -754,10 +810,13 @@ func (h *handler) Stop() {
 func (h *handler) myProgress() PeerProgress {
 	bs := h.store.GetBlockState()
 	epoch := h.store.GetEpoch()
+	hs := h.store.GetHistoryStart()
 	return PeerProgress{
 		Epoch:            epoch,
 		LastBlockIdx:     bs.LastBlock.Idx,
 		LastBlockAtropos: bs.LastBlock.Atropos,
+		LowestBlockIdx:   hs.Block,
+		LowestEpoch:      hs.Epoch,
 	}
 }
 
@@ -772,32 +831,49 @@ func (h *handler) highestPeerProgress() PeerProgress {
 	return max
 }
 
-// handle is the callback invoked to manage the life cycle of a peer. When
-// this function terminates, the peer is disconnected.
-func (h *handler) handle(p *peer) error {
-	// If the peer has a `snap` extension, wait for it to connect so we can have
-	// a uniform initialization/teardown mechanism
-	snap, err := h.peers.WaitSnapExtension(p)
-	if err != nil {
-		p.Log().Error("Snapshot extension barrier failed", "err", err)
-		return err
+// admitPeer checks whether the peer is allowed to connect, and marks it as private or useless.
+// The private peers are neither filtered nor banned.
+func (h *handler) admitPeer(p *peer) (useless bool, err error) {
+	private := h.privatePeers[p.id]
+	if h.config.Sentry.isPrivateMesh() && !private {
+		// validator is connected only to its sentries
+		return false, p2p.DiscUselessPeer
 	}
-	useless := discfilter.Banned(p.Node().ID(), p.Node().Record())
-	if !useless && (!eligibleForSnap(p.Peer) || !strings.Contains(strings.ToLower(p.Name()), "opera")) {
+	if private {
+		p.SetPrivate()
+	}
+	useless = !private && discfilter.Banned(p.Node().ID(), p.Node().Record())
+	if !private && !useless && (!eligibleForSnap(p.Peer) || !strings.Contains(strings.ToLower(p.Name()), "opera")) {
 		useless = true
 		discfilter.Ban(p.ID())
 	}
-	if !p.Peer.Info().Network.Trusted && useless && h.peers.UselessNum() >= h.maxPeers/10 {
-		// don't allow more than 10% of useless peers
-		return p2p.DiscTooManyPeers
+	if !p.Peer.Info().Network.Trusted && !private && h.peerScores.Banned(p.id) {
+		return useless, p2p.DiscUselessPeer
 	}
 	if !p.Peer.Info().Network.Trusted && useless {
 		if h.peers.UselessNum() >= h.maxPeers/10 {
 			// don't allow more than 10% of useless peers
-			return p2p.DiscTooManyPeers
+			return useless, p2p.DiscTooManyPeers
 		}
 		p.SetUseless()
 	}
+	return useless, nil
+}
+
+// handle is the callback invoked to manage the life cycle of a peer. When
+// this function terminates, the peer is disconnected.
+func (h *handler) handle(p *peer) error {
+	// If the peer has a `snap` extension, wait for it to connect so we can have
+	// a uniform initialization/teardown mechanism
+	snap, err := h.peers.WaitSnapExtension(p)
+	if err != nil {
+		p.Log().Error("Snapshot extension barrier failed", "err", err)
+		return err
+	}
+	useless, err := h.admitPeer(p)
+	if err != nil {
+		return err
+	}
 
 	h.peerWG.Add(1)
 	defer h.peerWG.Done()
@@ -830,7 +906,7 @@ func (h *handler) handle(p *peer) error {
 		p.Log().Warn("Leecher peer registration failed", "err", err)
 		return err
 	}
-	if p.RunningCap(ProtocolName, []uint{FTM63}) {
+	if p.RunningCap(ProtocolName, []uint{FTM63, FTM64}) {
 		if err := h.epLeecher.RegisterPeer(p.id); err != nil {
 			p.Log().Warn("Leecher peer registration failed", "err", err)
 			return err
@@ -1050,6 +1126,26 @@ func (h *handler) handleMsg(p *peer) error {
 		}
 		h.handleTxHashes(p, txHashes)
 
+	case msg.Code == PrivateEvmTxsMsg:
+		// private transactions are accepted only from the configured peers, and from the sentries or validators behind them
+		if !h.privateTxPeers[p.id] && !h.privatePeers[p.id] {
+			p.Log().Debug("Private transactions from a not configured peer")
+			h.peerBehaviour(p.id, peerscore.InvalidItem)
+			break
+		}
+		// Transactions arrived, make sure we have a valid and fresh graph to handle them
+		if !h.syncStatus.AcceptTxs() {
+			break
+		}
+		var txs []privateTx
+		if err := msg.Decode(&txs); err != nil {
+			return errResp(ErrDecode, "msg %v: %v", msg, err)
+		}
+		if err := checkLenLimits(len(txs), txs); err != nil {
+			return err
+		}
+		h.handlePrivateTxs(p, txs)
+
 	case msg.Code == GetEvmTxsMsg:
 		var requests []common.Hash
 		if err := msg.Decode(&requests); err != nil {
@@ -1062,7 +1158,7 @@ func (h *handler) handleMsg(p *peer) error {
 		txs := make(types.Transactions, 0, len(requests))
 		for _, txid := range requests {
 			tx := h.txpool.Get(txid)
-			if tx == nil {
+			if tx == nil || h.txpool.IsPrivate(txid) {
 				continue
 			}
 			txs = append(txs, tx)
@@ -1125,7 +1221,7 @@ func (h *handler) handleMsg(p *peer) error {
 			}
 		}
 		if len(rawEvents) != 0 {
-			p.EnqueueSendEventsRLP(rawEvents, ids, p.queue)
+			p.EnqueueSendEventsRLP(rawEvents, ids, p.eventsQueue())
 		}
 
 	case msg.Code == RequestEventsStream:
@@ -1168,6 +1264,20 @@ func (h *handler) handleMsg(p *peer) error {
 		if (len(chunk.Events) != 0) && (len(chunk.IDs) != 0) {
 			return errors.New("expected either events or event hashes")
 		}
+		epoch := h.store.GetEpoch()
+		newEvents := 0
+		for _, id := range chunk.IDs {
+			if h.isEventInterested(id, epoch) {
+				newEvents++
+			}
+		}
+		for _, e := range chunk.Events {
+			if h.isEventInterested(e.ID(), epoch) {
+				newEvents++
+			}
+		}
+		h.chunkBehaviour(p.id, &p.sessions.dag, chunk.SessionID, len(chunk.IDs)+len(chunk.Events), newEvents)
+
 		var last hash.Event
 		if len(chunk.IDs) != 0 {
 			h.handleEventHashes(p, chunk.IDs)
@@ -1213,6 +1323,14 @@ func (h *handler) handleMsg(p *peer) error {
 			return err
 		}
 
+		newBVs := 0
+		for _, bvs := range chunk.BVs {
+			if !h.store.HasBlockVotes(bvs.Val.Epoch, bvs.Val.LastBlock(), bvs.Signed.Locator.ID()) {
+				newBVs++
+			}
+		}
+		h.chunkBehaviour(p.id, &p.sessions.bv, chunk.SessionID, len(chunk.BVs), newBVs)
+
 		var last bvstreamleecher.BVsID
 		if len(chunk.BVs) != 0 {
 			_ = h.bvProcessor.Enqueue(p.id, chunk.BVs, nil)
@@ -1263,6 +1381,18 @@ func (h *handler) handleMsg(p *peer) error {
 			return err
 		}
 
+		newBRs := 0
+		for _, br := range chunk.BRs {
+			if !h.store.HasVerifiedBlock(br.Idx) {
+				newBRs++
+			}
+		}
+		h.chunkBehaviour(p.id, &p.sessions.br, chunk.SessionID, len(chunk.BRs), newBRs)
+		if len(chunk.BRs) == 0 && chunk.Done && p.progress.HasBlock(h.store.GetLlrState().LowestBlockToFill) {
+			// peer claims to have the records, but didn't send them
+			h.peerBehaviour(p.id, peerscore.MissingItems)
+		}
+
 		var last idx.Block
 		if len(chunk.BRs) != 0 {
 			_ = h.brProcessor.Enqueue(p.id, chunk.BRs, msgSize, nil)
@@ -1305,6 +1435,18 @@ func (h *handler) handleMsg(p *peer) error {
 			return err
 		}
 
+		newEPs := 0
+		for _, ep := range chunk.EPs {
+			if !h.store.HasVerifiedHistoryBlockEpochState(ep.Record.Idx) {
+				newEPs++
+			}
+		}
+		h.chunkBehaviour(p.id, &p.sessions.ep, chunk.SessionID, len(chunk.EPs), newEPs)
+		if len(chunk.EPs) == 0 && chunk.Done && p.progress.HasEpoch(h.store.GetLlrState().LowestEpochToFill) {
+			// peer claims to have the records, but didn't send them
+			h.peerBehaviour(p.id, peerscore.MissingItems)
+		}
+
 		var last idx.Epoch
 		if len(chunk.EPs) != 0 {
 			_ = h.epProcessor.Enqueue(p.id, chunk.EPs, msgSize, nil)
@@ -1370,16 +1512,22 @@ func (h *handler) BroadcastEvent(event *inter.EventPayload, passed time.Duration
 
 	fullRecipients := h.decideBroadcastAggressiveness(event.Size(), passed, len(peers))
 
-	// Exclude low quality peers from fullBroadcast
+	// Private peers always receive full events, exclude low quality peers from fullBroadcast
+	var privateBroadcast = make([]*peer, 0, len(h.privatePeers))
 	var fullBroadcast = make([]*peer, 0, fullRecipients)
 	var hashBroadcast = make([]*peer, 0, len(peers))
 	for _, p := range peers {
-		if !p.Useless() && len(fullBroadcast) < fullRecipients {
+		if p.Private() {
+			privateBroadcast = append(privateBroadcast, p)
+		} else if !p.Useless() && len(fullBroadcast) < fullRecipients {
 			fullBroadcast = append(fullBroadcast, p)
 		} else {
 			hashBroadcast = append(hashBroadcast, p)
 		}
 	}
+	for _, peer := range privateBroadcast {
+		peer.AsyncSendEvents(inter.EventPayloads{event}, peer.eventsQueue())
+	}
 	for _, peer := range fullBroadcast {
 		peer.AsyncSendEvents(inter.EventPayloads{event}, peer.queue)
 	}
@@ -1387,7 +1535,7 @@ func (h *handler) BroadcastEvent(event *inter.EventPayload, passed time.Duration
 	for _, peer := range hashBroadcast {
 		peer.AsyncSendEventIDs(hash.Events{event.ID()}, peer.queue)
 	}
-	log.Trace("Broadcast event", "hash", id, "fullRecipients", len(fullBroadcast), "hashRecipients", len(hashBroadcast))
+	log.Trace("Broadcast event", "hash", id, "privateRecipients", len(privateBroadcast), "fullRecipients", len(fullBroadcast), "hashRecipients", len(hashBroadcast))
 	return len(peers)
 }
 
@@ -1482,6 +1630,7 @@ func (h *handler) txBroadcastLoop() {
 	for {
 		select {
 		case notify := <-h.txsCh:
+			recordTxArrivals(notify.Txs, time.Now())
 			h.BroadcastTxs(notify.Txs)
 
 		// Err() channel will be closed when unsubscribing.
@@ -18,6 +18,7 @@ package gossip
 
 import (
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
+	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
 
@@ -55,6 +56,10 @@ func (h *snapHandler) Handle(peer *snap.Peer, packet snap.Packet) error {
 // `eth`, all subsystem registrations and lifecycle management will be done by
 // the main `eth` handler to prevent strange races.
 func (h *handler) runSnapExtension(peer *snap.Peer, handler snap.Handler) error {
+	if h.config.Sentry.isPrivateMesh() && !h.privatePeers[peer.ID()] {
+		// validator is connected only to its sentries
+		return p2p.DiscUselessPeer
+	}
 	h.peerWG.Add(1)
 	defer h.peerWG.Done()
 	if err := h.peers.RegisterSnapExtension(peer); err != nil {
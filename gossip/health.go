@@ -0,0 +1,198 @@
+package gossip
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+// HealthConfig is config of the health check HTTP endpoints
+type HealthConfig struct {
+	// ListenAddr is the address of the health HTTP server. The server is disabled if empty
+	ListenAddr string
+	// MinPeers is the min number of peers for the node to be ready
+	MinPeers int
+	// MaxBlockAge is the max age of the head block for the node to be ready
+	MaxBlockAge time.Duration
+	// MaxEmitAge is the max age of the last emitted event for a validator node to be ready
+	MaxEmitAge time.Duration
+	// MaxFlushAge is the max time since the last DB flush for the node to be ready
+	MaxFlushAge time.Duration
+}
+
+// DefaultHealthConfig returns default health check config
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		MinPeers:    1,
+		MaxBlockAge: 5 * time.Minute,
+		MaxEmitAge:  15 * time.Minute,
+		MaxFlushAge: 2 * time.Hour,
+	}
+}
+
+// EmitterHealth is the health of a validator emitter
+type EmitterHealth struct {
+	Validator   idx.ValidatorID `json:"validator"`
+	Active      bool            `json:"active"`
+	Paused      bool            `json:"paused"`
+	LastEmitted string          `json:"lastEmitted,omitempty"`
+	// LastEmittedAge is in seconds, it's absent if the last emitted event is unknown
+	LastEmittedAge *float64 `json:"lastEmittedAge,omitempty"`
+}
+
+// HealthStatus is the node health report. Ages are in seconds.
+// The node is live as long as it's able to report its health, the thresholds affect only the readiness.
+type HealthStatus struct {
+	Live         bool            `json:"live"`
+	Ready        bool            `json:"ready"`
+	Problems     []string        `json:"problems,omitempty"`
+	SyncStage    string          `json:"syncStage"`
+	MaybeSynced  bool            `json:"maybeSynced"`
+	Peers        int             `json:"peers"`
+	HeadBlock    idx.Block       `json:"headBlock"`
+	HeadBlockAge float64         `json:"headBlockAge"`
+	LastFlushAge float64         `json:"lastFlushAge"`
+	Emitters     []EmitterHealth `json:"emitters,omitempty"`
+}
+
+func (s syncStage) String() string {
+	switch s {
+	case ssUnknown:
+		return "unknown"
+	case ssSnaps:
+		return "snaps"
+	case ssEvmSnapGen:
+		return "evmSnapGen"
+	case ssEvents:
+		return "events"
+	}
+	return "invalid"
+}
+
+// Health checks the node state against the configured thresholds.
+// It doesn't lock the engine, so it's cheap to poll.
+func (s *Service) Health() HealthStatus {
+	cfg := s.config.Health
+	now := time.Now()
+	st := HealthStatus{
+		Live:        true,
+		Ready:       true,
+		SyncStage:   s.handler.syncStatus.Stage().String(),
+		MaybeSynced: s.handler.syncStatus.MaybeSynced(),
+		Peers:       s.handler.peers.Len(),
+	}
+	notReady := func(format string, args ...interface{}) {
+		st.Ready = false
+		st.Problems = append(st.Problems, fmt.Sprintf(format, args...))
+	}
+
+	if !s.handler.syncStatus.Is(ssEvents) {
+		notReady("syncing %s", st.SyncStage)
+	} else if !st.MaybeSynced {
+		notReady("syncing events")
+	}
+	if st.Peers < cfg.MinPeers {
+		notReady("%d peers, required %d", st.Peers, cfg.MinPeers)
+	}
+
+	bs := s.store.GetBlockState()
+	st.HeadBlock = bs.LastBlock.Idx
+	headBlockAge := now.Sub(bs.LastBlock.Time.Time())
+	st.HeadBlockAge = headBlockAge.Seconds()
+	if headBlockAge > cfg.MaxBlockAge {
+		notReady("head block is %s old", headBlockAge.Round(time.Second))
+	}
+
+	flushAge := now.Sub(s.store.LastFlushTime())
+	st.LastFlushAge = flushAge.Seconds()
+	if flushAge > cfg.MaxFlushAge {
+		notReady("DB wasn't flushed for %s", flushAge.Round(time.Second))
+	}
+
+	validators := s.store.GetValidators()
+	for _, em := range s.emitters {
+		es, err := em.Status()
+		if es.Validator == 0 {
+			continue
+		}
+		if err != nil {
+			notReady("validator %d status is unknown: %v", es.Validator, err)
+			continue
+		}
+		eh := EmitterHealth{
+			Validator: es.Validator,
+			Active:    validators.Exists(es.Validator),
+			Paused:    es.Paused,
+		}
+		if es.LastEmittedEvent != nil {
+			eh.LastEmitted = es.LastEmittedEvent.String()
+		}
+		var emitAge time.Duration
+		if !es.LastEmittedAt.IsZero() {
+			emitAge = now.Sub(es.LastEmittedAt)
+			age := emitAge.Seconds()
+			eh.LastEmittedAge = &age
+		}
+		if eh.Active {
+			if eh.Paused {
+				notReady("validator %d emitting is paused", es.Validator)
+			} else if eh.LastEmittedAge == nil {
+				notReady("validator %d hasn't emitted events", es.Validator)
+			} else if emitAge > cfg.MaxEmitAge {
+				notReady("validator %d last event is %s old", es.Validator, emitAge.Round(time.Second))
+			}
+		}
+		st.Emitters = append(st.Emitters, eh)
+	}
+	return st
+}
+
+func (s *Service) healthHandler(ready bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		st := s.Health()
+		ok := st.Live
+		if ready {
+			ok = st.Ready
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(st)
+	})
+}
+
+// startHealthServer starts the HTTP server with the liveness (/health) and readiness (/ready) endpoints
+func (s *Service) startHealthServer() error {
+	if len(s.config.Health.ListenAddr) == 0 {
+		return nil
+	}
+	listener, err := net.Listen("tcp", s.config.Health.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to start health server: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/health", s.healthHandler(false))
+	mux.Handle("/ready", s.healthHandler(true))
+	s.healthServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.Log.Info("Health server started", "addr", listener.Addr())
+	go func() {
+		if err := s.healthServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.Log.Error("Health server failed", "err", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Service) stopHealthServer() {
+	if s.healthServer != nil {
+		_ = s.healthServer.Close()
+	}
+}
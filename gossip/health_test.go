@@ -0,0 +1,95 @@
+package gossip
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requestHealth(t *testing.T, env *testEnv, ready bool) (int, HealthStatus) {
+	rec := httptest.NewRecorder()
+	env.healthHandler(ready).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var st HealthStatus
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&st))
+	return rec.Code, st
+}
+
+func requireProblem(t *testing.T, st HealthStatus, problem string) {
+	for _, p := range st.Problems {
+		if strings.Contains(p, problem) {
+			return
+		}
+	}
+	require.Fail(t, "problem isn't reported", "expected %q in %v", problem, st.Problems)
+}
+
+func TestHealthHandler(t *testing.T) {
+	require := require.New(t)
+
+	env := newTestEnv(2, 1)
+	defer env.Close()
+	env.config.Health = HealthConfig{
+		MinPeers:    0,
+		MaxBlockAge: 100000 * time.Hour,
+		MaxEmitAge:  time.Hour,
+		MaxFlushAge: time.Hour,
+	}
+	emitters := env.emitters
+
+	// syncing node is live, but not ready
+	code, st := requestHealth(t, env, false)
+	require.Equal(http.StatusOK, code)
+	require.True(st.Live)
+	require.False(st.Ready)
+	code, st = requestHealth(t, env, true)
+	require.Equal(http.StatusServiceUnavailable, code)
+	requireProblem(t, st, "syncing")
+
+	// synced node without validators is ready
+	env.handler.syncStatus.Set(ssEvents)
+	env.handler.syncStatus.MarkMaybeSynced()
+	env.emitters = nil
+	code, st = requestHealth(t, env, true)
+	require.Equal(http.StatusOK, code)
+	require.True(st.Ready)
+	require.Empty(st.Problems)
+
+	// not enough peers
+	env.config.Health.MinPeers = 1
+	code, st = requestHealth(t, env, true)
+	require.Equal(http.StatusServiceUnavailable, code)
+	requireProblem(t, st, "0 peers, required 1")
+	env.config.Health.MinPeers = 0
+
+	// stale DB flush affects only the readiness
+	env.config.Health.MaxFlushAge = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	code, st = requestHealth(t, env, true)
+	require.Equal(http.StatusServiceUnavailable, code)
+	requireProblem(t, st, "DB wasn't flushed")
+	code, st = requestHealth(t, env, false)
+	require.Equal(http.StatusOK, code)
+	require.True(st.Live)
+	env.config.Health.MaxFlushAge = time.Hour
+
+	// the validator hasn't emitted events
+	env.emitters = emitters
+	code, st = requestHealth(t, env, true)
+	require.Equal(http.StatusServiceUnavailable, code)
+	requireProblem(t, st, "hasn't emitted events")
+	require.Len(st.Emitters, 1)
+	require.True(st.Emitters[0].Active)
+
+	// stale head block
+	env.emitters = nil
+	env.config.Health.MaxBlockAge = time.Nanosecond
+	code, _ = requestHealth(t, env, true)
+	require.Equal(http.StatusServiceUnavailable, code)
+	code, _ = requestHealth(t, env, false)
+	require.Equal(http.StatusOK, code)
+}
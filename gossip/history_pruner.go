@@ -0,0 +1,166 @@
+package gossip
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// DefaultHistoryRetentionConfig returns default config of the history retention, which keeps the whole history
+func DefaultHistoryRetentionConfig() HistoryRetentionConfig {
+	return HistoryRetentionConfig{
+		Epochs:      0,
+		Period:      time.Minute,
+		BatchBlocks: 100,
+	}
+}
+
+// Validate checks the history retention config
+func (c *HistoryRetentionConfig) Validate() error {
+	if c.Epochs == 1 {
+		// the events of the latest sealed epoch are needed by the peers which are finishing the epoch
+		return errors.New("history retention Epochs has to be either 0 (disabled) or at least 2")
+	}
+	if c.Epochs != 0 && (c.Period <= 0 || c.BatchBlocks == 0) {
+		return errors.New("history retention Period and BatchBlocks have to be positive")
+	}
+	return nil
+}
+
+// historyPruner periodically deletes the history which is older than the retention window
+type historyPruner struct {
+	store  *Store
+	cfg    HistoryRetentionConfig
+	signer types.Signer
+
+	// historyMu serializes the pruning with the migration into the freezer
+	historyMu sync.Locker
+	// withEngineLocked calls fn while events and blocks processing is paused
+	withEngineLocked func(fn func())
+	// commit flushes the pruned data if needed, it's called while events and blocks processing is paused
+	commit func()
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+func newHistoryPruner(svc *Service, cfg HistoryRetentionConfig) *historyPruner {
+	return &historyPruner{
+		store:            svc.store,
+		cfg:              cfg,
+		signer:           svc.EthAPI.signer,
+		historyMu:        &svc.historyMu,
+		withEngineLocked: svc.withEngineLocked,
+		commit: func() {
+			if svc.store.IsCommitNeeded() {
+				svc.commit(false)
+			}
+		},
+		quit: make(chan struct{}),
+	}
+}
+
+func (p *historyPruner) loop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.cfg.Period)
+	defer ticker.Stop()
+	for {
+		p.prune()
+		select {
+		case <-ticker.C:
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+func (p *historyPruner) Start() {
+	if p.cfg.Epochs == 0 {
+		return
+	}
+	p.wg.Add(1)
+	go p.loop()
+}
+
+func (p *historyPruner) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+}
+
+func (p *historyPruner) stopped() bool {
+	select {
+	case <-p.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// prune deletes blocks and epochs older than the retention window.
+// The blocks are pruned first, because the transactions of blocks are read from their events.
+// The earliest-available markers are moved before the deletion, so RPC never observes partially deleted data.
+// The deleted batches are committed, so the not flushed data doesn't grow while the history is pruned.
+func (p *historyPruner) prune() {
+	s := p.store
+	p.historyMu.Lock()
+	defer p.historyMu.Unlock()
+	current := s.GetEpoch()
+	if current <= p.cfg.Epochs {
+		return
+	}
+	target := current - p.cfg.Epochs
+	hbs, _ := s.GetHistoryBlockEpochState(target)
+	if hbs == nil {
+		return
+	}
+	cutoff := hbs.LastBlock.Idx + 1
+
+	start := s.GetHistoryStart()
+	if start.Block == 0 {
+		start.Block = s.firstStoredBlock()
+	}
+	if start.Epoch == 0 {
+		start.Epoch = s.firstHistoryEpoch()
+	}
+	if start.Block < cutoff || start.Epoch < target {
+		log.Info("Pruning history", "epoch", start.Epoch, "earliest_epoch", target, "block", start.Block, "earliest_block", cutoff)
+	}
+	for start.Block < cutoff {
+		if p.stopped() {
+			return
+		}
+		from, to := start.Block, start.Block+p.cfg.BatchBlocks-1
+		if to >= cutoff {
+			to = cutoff - 1
+		}
+		start.Block = to + 1
+		p.withEngineLocked(func() {
+			s.SetHistoryStart(start)
+			s.PruneBlocksHistory(from, to, p.signer)
+			p.commit()
+		})
+	}
+	for start.Epoch < target {
+		if p.stopped() {
+			return
+		}
+		epoch := start.Epoch
+		start.Epoch++
+		p.withEngineLocked(func() {
+			s.SetHistoryStart(start)
+			s.PruneEpochHistory(epoch)
+			p.commit()
+		})
+	}
+}
+
+// withEngineLocked calls fn while events and blocks processing is paused
+func (s *Service) withEngineLocked(fn func()) {
+	s.engineMu.Lock()
+	defer s.engineMu.Unlock()
+	s.blockProcWg.Wait()
+	fn()
+}
@@ -0,0 +1,190 @@
+package gossip
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+)
+
+const (
+	testPrunerFirstEpoch   = idx.Epoch(2)
+	testPrunerCurrentEpoch = idx.Epoch(7)
+	testPrunerGenesisBlock = idx.Block(3)
+	testPrunerBlocks       = idx.Block(24)
+)
+
+// testPrunerEpochLastBlock returns the last block of a sealed epoch, each epoch has 4 blocks
+func testPrunerEpochLastBlock(epoch idx.Epoch) idx.Block {
+	return idx.Block(epoch) * 4
+}
+
+// newTestPrunerStore makes a store with the history which starts at the genesis block and epoch,
+// like after the LLR genesis
+func newTestPrunerStore() *Store {
+	store := NewMemStore()
+	validators := pos.EqualWeightValidators([]idx.ValidatorID{1}, 1)
+	store.SetBlockEpochState(iblockproc.BlockState{
+		LastBlock: iblockproc.BlockCtx{Idx: testPrunerBlocks},
+	}, iblockproc.EpochState{
+		Epoch:      testPrunerCurrentEpoch,
+		Validators: validators,
+	})
+	store.SetGenesisBlockIndex(testPrunerGenesisBlock)
+	for n := testPrunerGenesisBlock; n <= testPrunerBlocks; n++ {
+		atropos := hash.FakeEvent()
+		store.SetBlock(n, &inter.Block{
+			Time:    inter.Timestamp(n),
+			Atropos: atropos,
+		})
+		store.SetBlockIndex(atropos, n)
+	}
+	for epoch := testPrunerFirstEpoch; epoch < testPrunerCurrentEpoch; epoch++ {
+		store.SetHistoryBlockEpochState(epoch, iblockproc.BlockState{
+			LastBlock: iblockproc.BlockCtx{Idx: testPrunerEpochLastBlock(epoch)},
+		}, iblockproc.EpochState{
+			Epoch:      epoch,
+			Validators: validators,
+		})
+	}
+	for epoch := testPrunerFirstEpoch; epoch <= testPrunerCurrentEpoch; epoch++ {
+		me := &inter.MutableEventPayload{}
+		me.SetVersion(1)
+		me.SetEpoch(epoch)
+		me.SetLamport(1)
+		me.SetCreator(1)
+		me.SetPayloadHash(inter.CalcPayloadHash(me))
+		store.SetEvent(me.Build())
+	}
+	return store
+}
+
+type testPruner struct {
+	*historyPruner
+	locks   int
+	commits int
+}
+
+func newTestPruner(store *Store, stopAfter int) *testPruner {
+	p := &testPruner{}
+	p.historyPruner = &historyPruner{
+		store: store,
+		cfg: HistoryRetentionConfig{
+			Epochs:      2,
+			BatchBlocks: 3,
+		},
+		signer:    types.HomesteadSigner{},
+		historyMu: new(sync.Mutex),
+		withEngineLocked: func(fn func()) {
+			fn()
+			p.locks++
+			if p.locks == stopAfter {
+				close(p.quit)
+			}
+		},
+		commit: func() {
+			p.commits++
+		},
+		quit: make(chan struct{}),
+	}
+	return p
+}
+
+func checkPrunedHistory(t *testing.T, store *Store, start HistoryStart) {
+	require := require.New(t)
+
+	require.Equal(start, store.GetHistoryStart())
+	for n := testPrunerGenesisBlock; n <= testPrunerBlocks; n++ {
+		// the genesis block is kept
+		require.Equal(n == testPrunerGenesisBlock || n >= start.Block, store.GetBlock(n) != nil, n)
+	}
+	for epoch := testPrunerFirstEpoch; epoch <= testPrunerCurrentEpoch; epoch++ {
+		if epoch < testPrunerCurrentEpoch {
+			require.Equal(epoch >= start.Epoch, store.HasHistoryBlockEpochState(epoch), epoch)
+		}
+		num := 0
+		store.ForEachEpochEvent(epoch, func(*inter.EventPayload) bool {
+			num++
+			return true
+		})
+		if epoch >= start.Epoch {
+			require.Equal(1, num, epoch)
+		} else {
+			require.Equal(0, num, epoch)
+		}
+	}
+}
+
+func TestHistoryPruner(t *testing.T) {
+	require := require.New(t)
+	store := newTestPrunerStore()
+
+	p := newTestPruner(store, 0)
+	p.prune()
+	target := testPrunerCurrentEpoch - p.cfg.Epochs
+	checkPrunedHistory(t, store, HistoryStart{
+		Epoch: target,
+		Block: testPrunerEpochLastBlock(target) + 1,
+	})
+	// the epochs are pruned from the first stored epoch, each batch is committed
+	blockBatches := int(testPrunerEpochLastBlock(target)+1-testPrunerGenesisBlock+p.cfg.BatchBlocks-1) / int(p.cfg.BatchBlocks)
+	epochBatches := int(target - testPrunerFirstEpoch)
+	require.Equal(blockBatches+epochBatches, p.locks)
+	require.Equal(p.locks, p.commits)
+
+	// nothing to prune until the next epoch
+	p = newTestPruner(store, 0)
+	p.prune()
+	require.Equal(0, p.locks)
+}
+
+func TestHistoryPrunerResume(t *testing.T) {
+	store := newTestPrunerStore()
+
+	// interrupt the pruning in the middle of the blocks
+	newTestPruner(store, 2).prune()
+	checkPrunedHistory(t, store, HistoryStart{
+		Epoch: testPrunerFirstEpoch,
+		Block: testPrunerGenesisBlock + 6,
+	})
+
+	// interrupt the pruning in the middle of the epochs
+	newTestPruner(store, 5).prune()
+	checkPrunedHistory(t, store, HistoryStart{
+		Epoch: testPrunerFirstEpoch + 1,
+		Block: testPrunerEpochLastBlock(5) + 1,
+	})
+
+	newTestPruner(store, 0).prune()
+	checkPrunedHistory(t, store, HistoryStart{
+		Epoch: 5,
+		Block: testPrunerEpochLastBlock(5) + 1,
+	})
+}
+
+func TestTryGetBlockTxsPruned(t *testing.T) {
+	require := require.New(t)
+	store := newTestPrunerStore()
+
+	var events hash.Events
+	store.ForEachEpochEvent(testPrunerFirstEpoch, func(e *inter.EventPayload) bool {
+		events = append(events, e.ID())
+		return true
+	})
+	block := &inter.Block{Events: events}
+	txs, err := store.TryGetBlockTxs(testPrunerBlocks, block)
+	require.NoError(err)
+	require.Empty(txs)
+
+	// the events of the block are pruned
+	store.PruneEpochHistory(testPrunerFirstEpoch)
+	_, err = store.TryGetBlockTxs(testPrunerBlocks, block)
+	require.Error(err)
+}
@@ -0,0 +1,30 @@
+package gossip
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// livePruningRoots returns the state roots of the latest blocks which are kept by the live EVM state pruning.
+func (s *Service) livePruningRoots() []common.Hash {
+	keep := s.store.cfg.EVM.LivePruning.KeepBlocks
+	roots := []common.Hash{common.Hash(s.store.GetBlockState().FinalizedStateRoot)}
+	latest := s.store.GetLatestBlockIndex()
+	for n := latest; n > 0 && latest-n < keep; n-- {
+		block := s.store.GetBlock(n)
+		if block == nil || !s.store.evm.HasStateDB(block.Root) {
+			continue
+		}
+		roots = append(roots, common.Hash(block.Root))
+	}
+	return roots
+}
+
+// StartStatePruning launches the background pruning of the EVM states which are older than the kept blocks.
+func (s *Service) StartStatePruning() error {
+	if !s.handler.syncStatus.Is(ssEvents) {
+		return errors.New("live EVM state pruning requires the node to be synced")
+	}
+	return s.store.evm.StartLivePruning(s.livePruningRoots)
+}
@@ -0,0 +1,95 @@
+package gossip
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/utils/promexp"
+)
+
+// Consensus metrics with labels, exposed by the Prometheus exporter (see utils/promexp)
+var (
+	eventsConnectedCounter = promexp.NewCounterVec("opera_dag_events_connected_total",
+		"Number of events connected to the DAG.", "validator")
+	frameGauge = promexp.NewGaugeVec("opera_dag_frame",
+		"Highest frame of a connected event in the epoch.", "epoch")
+	epochGauge = promexp.NewGaugeVec("opera_epoch",
+		"Current epoch.")
+	finalityHistogram = promexp.NewHistogramVec("opera_finality_seconds",
+		"Time from event creation until the event is confirmed by a block.", promexp.DefaultBuckets)
+	llrBlocksToDecideGauge = promexp.NewGaugeVec("opera_llr_blocks_to_decide",
+		"Number of blocks which aren't decided by LLR block votes yet.")
+	llrEpochsToDecideGauge = promexp.NewGaugeVec("opera_llr_epochs_to_decide",
+		"Number of epochs which aren't decided by LLR epoch votes yet.")
+	gasPowerLeftGauge = promexp.NewGaugeVec("opera_validator_gas_power_left",
+		"Gas power left of a validator after its last connected event.", "validator", "type")
+	txLatencyHistogram = promexp.NewHistogramVec("opera_txpool_block_latency_seconds",
+		"Time from tx arrival into the txpool until its inclusion into a block.", promexp.DefaultBuckets)
+
+	// txArrivals keeps arrival time of the recent txpool txs
+	txArrivals, _ = lru.New(16384)
+)
+
+// recordConnectedEventMetrics updates metrics of a connected event
+func recordConnectedEventMetrics(e inter.EventI, epoch idx.Epoch) {
+	validator := strconv.FormatUint(uint64(e.Creator()), 10)
+	eventsConnectedCounter.Inc(validator)
+	frameGauge.SetMax(float64(e.Frame()), strconv.FormatUint(uint64(e.Epoch()), 10))
+	gasPowerLeftGauge.Set(float64(e.GasPowerLeft().Gas[inter.ShortTermGas]), validator, "short")
+	gasPowerLeftGauge.Set(float64(e.GasPowerLeft().Gas[inter.LongTermGas]), validator, "long")
+	epochGauge.Set(float64(epoch))
+}
+
+// recordNewEpochMetrics forgets the frames of old epochs
+func recordNewEpochMetrics(epoch idx.Epoch) {
+	prev := strconv.FormatUint(uint64(epoch-1), 10)
+	current := strconv.FormatUint(uint64(epoch), 10)
+	frameGauge.DeleteIf(func(values []string) bool {
+		return values[0] != prev && values[0] != current
+	})
+	epochGauge.Set(float64(epoch))
+}
+
+// recordConfirmedEventMetrics updates metrics of an event confirmed by a block
+func recordConfirmedEventMetrics(e inter.EventI, now time.Time) {
+	finalityHistogram.Observe(now.Sub(e.CreationTime().Time()).Seconds())
+}
+
+// recordLlrMetrics updates metrics of LLR votes processing lag, relative to the last processed block and sealed epoch
+func recordLlrMetrics(llrs LlrState, lastBlock idx.Block, lastEpoch idx.Epoch) {
+	blocksLag := 0.0
+	if lastBlock+1 > llrs.LowestBlockToDecide {
+		blocksLag = float64(lastBlock + 1 - llrs.LowestBlockToDecide)
+	}
+	epochsLag := 0.0
+	if lastEpoch+1 > llrs.LowestEpochToDecide {
+		epochsLag = float64(lastEpoch + 1 - llrs.LowestEpochToDecide)
+	}
+	llrBlocksToDecideGauge.Set(blocksLag)
+	llrEpochsToDecideGauge.Set(epochsLag)
+}
+
+// recordTxArrivals remembers arrival time of new txpool txs
+func recordTxArrivals(txs []*types.Transaction, now time.Time) {
+	for _, tx := range txs {
+		txArrivals.ContainsOrAdd(tx.Hash(), now)
+	}
+}
+
+// recordBlockTxsMetrics updates latency of the block txs which were seen in the txpool
+func recordBlockTxsMetrics(txs types.Transactions, now time.Time) {
+	for _, tx := range txs {
+		txid := tx.Hash()
+		arrived, ok := txArrivals.Peek(txid)
+		if !ok {
+			continue
+		}
+		txArrivals.Remove(txid)
+		txLatencyHistogram.Observe(now.Sub(arrived.(time.Time)).Seconds())
+	}
+}
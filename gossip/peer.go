@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rlp"
 
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockvotes/bvstream"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/dag/dagstream"
@@ -59,16 +60,23 @@ type peer struct {
 	knownTxs            mapset.Set         // Set of transaction hashes known to be known by this peer
 	knownEvents         mapset.Set         // Set of event hashes known to be known by this peer
 	queue               chan broadcastItem // queue of items to send
+	priorityQueue       chan broadcastItem // queue of items to send before the items of regular queue
 	queuedDataSemaphore *datasemaphore.DataSemaphore
 	term                chan struct{} // Termination channel to stop the broadcaster
 
 	progress PeerProgress
 
+	// sessions are the stats of the stream sessions, they're accessed only by the peer messages handler
+	sessions struct {
+		dag, bv, br, ep peerscore.SessionStats
+	}
+
 	snapExt  *snapPeer     // Satellite `snap` connection
 	syncDrop *time.Timer   // Connection dropper if `eth` sync progress isn't validated in time
 	snapWait chan struct{} // Notification channel for snap connections
 
 	useless uint32
+	private uint32
 
 	sync.RWMutex
 }
@@ -81,6 +89,23 @@ func (p *peer) SetUseless() {
 	atomic.StoreUint32(&p.useless, 1)
 }
 
+// Private returns true if peer is a sentry of this validator, or a validator behind this sentry
+func (p *peer) Private() bool {
+	return atomic.LoadUint32(&p.private) != 0
+}
+
+func (p *peer) SetPrivate() {
+	atomic.StoreUint32(&p.private, 1)
+}
+
+// eventsQueue returns the queue for events propagation, which is prioritized for private peers
+func (p *peer) eventsQueue() chan broadcastItem {
+	if p.Private() {
+		return p.priorityQueue
+	}
+	return p.queue
+}
+
 func (p *peer) SetProgress(x PeerProgress) {
 	p.Lock()
 	defer p.Unlock()
@@ -100,6 +125,16 @@ func (p *peer) InterestedIn(h hash.Event) bool {
 		!p.knownEvents.Contains(h)
 }
 
+// HasBlock returns true if the peer claims to have the record of the block
+func (a *PeerProgress) HasBlock(n idx.Block) bool {
+	return a.LowestBlockIdx <= n && n <= a.LastBlockIdx
+}
+
+// HasEpoch returns true if the peer claims to have the record of the sealed epoch
+func (a *PeerProgress) HasEpoch(epoch idx.Epoch) bool {
+	return a.LowestEpoch <= epoch && epoch < a.Epoch
+}
+
 func (a *PeerProgress) Less(b PeerProgress) bool {
 	if a.Epoch != b.Epoch {
 		return a.Epoch < b.Epoch
@@ -117,11 +152,12 @@ func newPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter, cfg PeerCacheConfi
 		knownTxs:            mapset.NewSet(),
 		knownEvents:         mapset.NewSet(),
 		queue:               make(chan broadcastItem, cfg.MaxQueuedItems),
+		priorityQueue:       make(chan broadcastItem, cfg.MaxQueuedItems),
 		queuedDataSemaphore: datasemaphore.New(dag.Metric{cfg.MaxQueuedItems, cfg.MaxQueuedSize}, getSemaphoreWarningFn("Peers queue")),
 		term:                make(chan struct{}),
 	}
 
-	go peer.broadcast(peer.queue)
+	go peer.broadcast(peer.queue, peer.priorityQueue)
 
 	return peer
 }
@@ -129,12 +165,22 @@ func newPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter, cfg PeerCacheConfi
 // broadcast is a write loop that multiplexes event propagations, announcements
 // and transaction broadcasts into the remote peer. The goal is to have an async
 // writer that does not lock up node internals.
-func (p *peer) broadcast(queue chan broadcastItem) {
+// Items of the priority queue are sent before the items of regular queue.
+func (p *peer) broadcast(queue chan broadcastItem, priorityQueue chan broadcastItem) {
 	for {
 		select {
+		case item := <-priorityQueue:
+			p.sendItem(item)
+			continue
+		default:
+		}
+
+		select {
+		case item := <-priorityQueue:
+			p.sendItem(item)
+
 		case item := <-queue:
-			_ = p2p.Send(p.rw, item.Code, item.Raw)
-			p.queuedDataSemaphore.Release(memSize(item.Raw))
+			p.sendItem(item)
 
 		case <-p.term:
 			return
@@ -142,6 +188,11 @@ func (p *peer) broadcast(queue chan broadcastItem) {
 	}
 }
 
+func (p *peer) sendItem(item broadcastItem) {
+	_ = p2p.Send(p.rw, item.Code, item.Raw)
+	p.queuedDataSemaphore.Release(memSize(item.Raw))
+}
+
 // Close signals the broadcast goroutine to terminate.
 func (p *peer) Close() {
 	p.queuedDataSemaphore.Terminate()
@@ -290,6 +341,22 @@ func (p *peer) AsyncSendTransactions(txs types.Transactions, queue chan broadcas
 	}
 }
 
+// AsyncSendPrivateTransactions queues list of private transactions propagation to a remote
+// peer. If the peer's broadcast queue is full, the transactions are silently dropped.
+func (p *peer) AsyncSendPrivateTransactions(txs []privateTx, queue chan broadcastItem) {
+	if p.asyncSendNonEncodedItem(txs, PrivateEvmTxsMsg, queue) {
+		// Mark all the transactions as known, but ensure we don't overflow our limits
+		for _, tx := range txs {
+			p.knownTxs.Add(tx.Tx.Hash())
+		}
+		for p.knownTxs.Cardinality() >= p.cfg.MaxKnownTxs {
+			p.knownTxs.Pop()
+		}
+	} else {
+		p.Log().Debug("Dropping private transactions propagation", "count", len(txs))
+	}
+}
+
 // AsyncSendTransactionHashes queues list of transactions propagation to a remote
 // peer. If the peer's broadcast queue is full, the transactions are silently dropped.
 func (p *peer) AsyncSendTransactionHashes(txids []common.Hash, queue chan broadcastItem) {
@@ -407,7 +474,7 @@ func (p *peer) EnqueueSendEventsRLP(events []rlp.RawValue, ids []hash.Event, que
 // AsyncSendProgress queues a progress propagation to a remote peer.
 // If the peer's broadcast queue is full, the progress is silently dropped.
 func (p *peer) AsyncSendProgress(progress PeerProgress, queue chan broadcastItem) {
-	if !p.asyncSendNonEncodedItem(progress, ProgressMsg, queue) {
+	if !p.asyncSendNonEncodedItem(p.compatibleProgress(progress), ProgressMsg, queue) {
 		p.Log().Debug("Dropping peer progress propagation")
 	}
 }
@@ -522,7 +589,16 @@ func (p *peer) Handshake(network uint64, progress PeerProgress, genesis common.H
 }
 
 func (p *peer) SendProgress(progress PeerProgress) error {
-	return p2p.Send(p.rw, ProgressMsg, progress)
+	return p2p.Send(p.rw, ProgressMsg, p.compatibleProgress(progress))
+}
+
+// compatibleProgress omits the fields which the peer's protocol version doesn't support
+func (p *peer) compatibleProgress(progress PeerProgress) PeerProgress {
+	if p.version < FTM64 {
+		progress.LowestBlockIdx = 0
+		progress.LowestEpoch = 0
+	}
+	return progress
 }
 
 func (p *peer) readStatus(network uint64, handshake *handshakeData, genesis common.Hash) (err error) {
@@ -585,5 +661,5 @@ func (p *snapPeer) info() *snapPeerInfo {
 
 // eligibleForSnap checks eligibility of a peer for a snap protocol. A peer is eligible for a snap if it advertises `snap` sattelite protocol along with `opera` protocol.
 func eligibleForSnap(p *p2p.Peer) bool {
-	return p.RunningCap(ProtocolName, []uint{FTM63}) && p.RunningCap(snap.ProtocolName, snap.ProtocolVersions)
+	return p.RunningCap(ProtocolName, []uint{FTM63, FTM64}) && p.RunningCap(snap.ProtocolName, snap.ProtocolVersions)
 }
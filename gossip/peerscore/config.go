@@ -0,0 +1,57 @@
+package peerscore
+
+import "time"
+
+// Weights are the score changes caused by every kind of peer behaviour.
+// Penalties are negative, rewards are positive.
+type Weights struct {
+	// InvalidItem is for an item which failed validation (bannable error)
+	InvalidItem float64
+	// DuplicateItem is for a chunk of already known items, if the session has too many duplicates
+	DuplicateItem float64
+	// MissingItems is for an empty stream response for records the peer claimed to have
+	MissingItems float64
+	// StalledSession is for a stream session which was terminated due to no progress from the peer
+	StalledSession float64
+	// UsefulChunk is for a stream chunk with new items
+	UsefulChunk float64
+}
+
+type Config struct {
+	// BanThreshold is the score below which a peer is disconnected and banned
+	BanThreshold float64
+	// BanDuration is the time during which a banned peer isn't allowed to connect
+	BanDuration time.Duration
+	// HalfLife is the time during which a score decays towards zero by half
+	HalfLife time.Duration
+	// MaxScore limits the score, so a peer cannot accumulate an unlimited credit
+	MaxScore float64
+	// MaxPeers is the max number of tracked peers. Peers with the lowest absolute scores are forgotten first,
+	// banned peers are kept until their bans expire
+	MaxPeers int
+	// MaxDuplicatesRatio is the ratio of duplicate items in a stream session above which
+	// the peer is penalized for duplicates
+	MaxDuplicatesRatio float64
+
+	Weights Weights
+}
+
+// DefaultConfig returns default peer scoring config
+func DefaultConfig() Config {
+	return Config{
+		BanThreshold: -100,
+		BanDuration:  30 * time.Minute,
+		HalfLife:     10 * time.Minute,
+		MaxScore:     100,
+		MaxPeers:     4096,
+
+		MaxDuplicatesRatio: 0.5,
+		Weights: Weights{
+			InvalidItem:    -60,
+			DuplicateItem:  -0.5,
+			MissingItems:   -10,
+			StalledSession: -20,
+			UsefulChunk:    2,
+		},
+	}
+}
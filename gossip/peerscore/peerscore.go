@@ -0,0 +1,234 @@
+package peerscore
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Behaviour is a kind of peer behaviour which changes the peer score
+type Behaviour int
+
+const (
+	InvalidItem Behaviour = iota
+	DuplicateItem
+	MissingItems
+	StalledSession
+	UsefulChunk
+)
+
+func (b Behaviour) String() string {
+	switch b {
+	case InvalidItem:
+		return "invalid item"
+	case DuplicateItem:
+		return "duplicate item"
+	case MissingItems:
+		return "missing items"
+	case StalledSession:
+		return "stalled session"
+	case UsefulChunk:
+		return "useful chunk"
+	}
+	return "unknown"
+}
+
+// PeerScore is a snapshot of a peer score
+type PeerScore struct {
+	Score       float64    `json:"score"`
+	BannedUntil *time.Time `json:"bannedUntil,omitempty"`
+}
+
+type peerState struct {
+	score       float64
+	updated     time.Time
+	bannedUntil time.Time
+}
+
+// Scores accumulates penalties and rewards of peers.
+// Scores decay towards zero over time, so old behaviour is forgotten.
+// It's safe for concurrent use.
+type Scores struct {
+	cfg   Config
+	peers map[string]*peerState
+	now   func() time.Time
+	mu    sync.Mutex
+}
+
+// New creates peer scores
+func New(cfg Config) *Scores {
+	return &Scores{
+		cfg:   cfg,
+		peers: make(map[string]*peerState),
+		now:   time.Now,
+	}
+}
+
+func (s *Scores) weight(b Behaviour) float64 {
+	switch b {
+	case InvalidItem:
+		return s.cfg.Weights.InvalidItem
+	case DuplicateItem:
+		return s.cfg.Weights.DuplicateItem
+	case MissingItems:
+		return s.cfg.Weights.MissingItems
+	case StalledSession:
+		return s.cfg.Weights.StalledSession
+	case UsefulChunk:
+		return s.cfg.Weights.UsefulChunk
+	}
+	return 0
+}
+
+// decay applies the time decay to the peer score
+func (s *Scores) decay(p *peerState, now time.Time) {
+	if s.cfg.HalfLife > 0 && now.After(p.updated) {
+		p.score *= math.Pow(0.5, float64(now.Sub(p.updated))/float64(s.cfg.HalfLife))
+	}
+	p.updated = now
+}
+
+// get returns the decayed peer state, or nil if peer isn't tracked
+func (s *Scores) get(peer string, now time.Time) *peerState {
+	p := s.peers[peer]
+	if p == nil {
+		return nil
+	}
+	if !p.bannedUntil.IsZero() && !now.Before(p.bannedUntil) {
+		// ban is expired, give the peer a fresh start
+		p.bannedUntil = time.Time{}
+		p.score = 0
+	}
+	s.decay(p, now)
+	return p
+}
+
+// Add changes the peer score according to its behaviour.
+// Returns true if the peer got banned.
+func (s *Scores) Add(peer string, b Behaviour) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	p := s.get(peer, now)
+	if p == nil {
+		if !s.prune(now) {
+			// the limit is reached by the banned peers, the new peer isn't tracked until the bans expire
+			return false
+		}
+		p = &peerState{updated: now}
+		s.peers[peer] = p
+	}
+	if !p.bannedUntil.IsZero() {
+		return false
+	}
+	p.score += s.weight(b)
+	if p.score > s.cfg.MaxScore {
+		p.score = s.cfg.MaxScore
+	}
+	if p.score < s.cfg.BanThreshold {
+		p.bannedUntil = now.Add(s.cfg.BanDuration)
+		return true
+	}
+	return false
+}
+
+// prune forgets the peers with the lowest absolute scores if limit is reached.
+// Banned peers are never forgotten before their ban expires.
+// Returns false if there's no room for a new peer, i.e. all the tracked peers are banned.
+func (s *Scores) prune(now time.Time) bool {
+	if len(s.peers) < s.cfg.MaxPeers {
+		return true
+	}
+	candidates := make([]string, 0, len(s.peers))
+	for id := range s.peers {
+		p := s.get(id, now)
+		if p.bannedUntil.IsZero() {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return math.Abs(s.peers[candidates[i]].score) < math.Abs(s.peers[candidates[j]].score)
+	})
+	for _, id := range candidates {
+		if len(s.peers) < s.cfg.MaxPeers {
+			break
+		}
+		delete(s.peers, id)
+	}
+	return len(s.peers) < s.cfg.MaxPeers
+}
+
+// Score returns the current peer score
+func (s *Scores) Score(peer string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.get(peer, s.now())
+	if p == nil {
+		return 0
+	}
+	return p.score
+}
+
+// Banned returns true if peer is banned
+func (s *Scores) Banned(peer string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.get(peer, s.now())
+	return p != nil && !p.bannedUntil.IsZero()
+}
+
+// Unban removes the peer ban and resets its score.
+// Returns false if peer wasn't banned.
+func (s *Scores) Unban(peer string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.get(peer, s.now())
+	if p == nil || p.bannedUntil.IsZero() {
+		return false
+	}
+	delete(s.peers, peer)
+	return true
+}
+
+// Snapshot returns scores of all the tracked peers
+func (s *Scores) Snapshot() map[string]PeerScore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	res := make(map[string]PeerScore, len(s.peers))
+	for id := range s.peers {
+		p := s.get(id, now)
+		ps := PeerScore{
+			Score: p.score,
+		}
+		if !p.bannedUntil.IsZero() {
+			bannedUntil := p.bannedUntil
+			ps.BannedUntil = &bannedUntil
+		}
+		res[id] = ps
+	}
+	return res
+}
+
+// Pick selects a peer for a stream session, preferring peers with higher scores.
+// It picks the best of two random candidates, so low-scoring peers are still selected sometimes
+// and a single high-scoring peer doesn't receive all the requests.
+// If score is nil, then a random candidate is picked.
+func Pick(candidates []string, score func(peer string) float64) string {
+	a := candidates[rand.Intn(len(candidates))]
+	if score == nil || len(candidates) < 2 {
+		return a
+	}
+	b := candidates[rand.Intn(len(candidates))]
+	if score(b) > score(a) {
+		return b
+	}
+	return a
+}
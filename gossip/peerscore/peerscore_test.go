@@ -0,0 +1,168 @@
+package peerscore
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScores(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1000, 0)
+	s := New(DefaultConfig())
+	s.now = func() time.Time {
+		return now
+	}
+	w := s.cfg.Weights
+
+	require.False(s.Add("a", UsefulChunk))
+	require.Equal(w.UsefulChunk, s.Score("a"))
+	require.Equal(0.0, s.Score("b"))
+
+	// reward is limited
+	for i := 0; i < 1000; i++ {
+		s.Add("a", UsefulChunk)
+	}
+	require.Equal(s.cfg.MaxScore, s.Score("a"))
+
+	// score decays by half
+	now = now.Add(s.cfg.HalfLife)
+	require.InDelta(s.cfg.MaxScore/2, s.Score("a"), 0.0001)
+
+	// peer is banned below threshold
+	require.False(s.Add("b", InvalidItem))
+	require.False(s.Banned("b"))
+	require.True(s.Add("b", InvalidItem))
+	require.True(s.Banned("b"))
+	require.False(s.Add("b", InvalidItem))
+	snapshot := s.Snapshot()
+	require.Len(snapshot, 2)
+	require.NotNil(snapshot["b"].BannedUntil)
+	require.Equal(now.Add(s.cfg.BanDuration), *snapshot["b"].BannedUntil)
+	require.Nil(snapshot["a"].BannedUntil)
+
+	// ban expires
+	now = now.Add(s.cfg.BanDuration)
+	require.False(s.Banned("b"))
+	require.Equal(0.0, s.Score("b"))
+
+	// manual unban
+	s.Add("b", InvalidItem)
+	require.True(s.Add("b", InvalidItem))
+	require.False(s.Unban("a"))
+	require.True(s.Unban("b"))
+	require.False(s.Banned("b"))
+	require.Equal(0.0, s.Score("b"))
+}
+
+func TestScoresPrune(t *testing.T) {
+	require := require.New(t)
+
+	cfg := DefaultConfig()
+	cfg.MaxPeers = 3
+	s := New(cfg)
+
+	s.Add("a", DuplicateItem)
+	s.Add("b", InvalidItem)
+	s.Add("b", InvalidItem)
+	s.Add("c", StalledSession)
+	s.Add("d", MissingItems)
+	// neutral "a" is forgotten, banned "b" is kept
+	require.Len(s.Snapshot(), 3)
+	require.True(s.Banned("b"))
+	require.Equal(0.0, s.Score("a"))
+}
+
+func TestScoresPruneKeepsBans(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1000, 0)
+	cfg := DefaultConfig()
+	cfg.MaxPeers = 3
+	s := New(cfg)
+	s.now = func() time.Time {
+		return now
+	}
+
+	s.Add("banned", InvalidItem)
+	require.True(s.Add("banned", InvalidItem))
+	s.Add("bad", StalledSession)
+	s.Add("good", UsefulChunk)
+	// no neutral peers are left, the peer with the lowest absolute score is forgotten
+	s.Add("new", MissingItems)
+	require.Len(s.Snapshot(), 3)
+	require.True(s.Banned("banned"))
+	require.Equal(cfg.Weights.StalledSession, s.Score("bad"))
+	require.Equal(0.0, s.Score("good"))
+
+	// the ban survives any number of new peers
+	for i := 0; i < 100; i++ {
+		s.Add(fmt.Sprintf("peer%d", i), StalledSession)
+	}
+	require.Len(s.Snapshot(), 3)
+	require.True(s.Banned("banned"))
+
+	// all the tracked peers are banned, new peers aren't tracked
+	for id := range s.Snapshot() {
+		for !s.Banned(id) {
+			s.Add(id, InvalidItem)
+		}
+	}
+	require.False(s.Add("other", InvalidItem))
+	require.False(s.Add("other", InvalidItem))
+	require.Len(s.Snapshot(), 3)
+	require.True(s.Banned("banned"))
+	require.False(s.Banned("other"))
+
+	// the peers are tracked again once the bans expire
+	now = now.Add(cfg.BanDuration)
+	s.Add("other", InvalidItem)
+	require.True(s.Add("other", InvalidItem))
+	require.True(s.Banned("other"))
+	require.False(s.Banned("banned"))
+}
+
+func TestPick(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("a", Pick([]string{"a"}, nil))
+
+	scores := map[string]float64{"a": -10, "b": 10, "c": 0}
+	picked := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		picked[Pick([]string{"a", "b", "c"}, func(peer string) float64 {
+			return scores[peer]
+		})]++
+	}
+	require.Greater(picked["b"], picked["c"])
+	require.Greater(picked["c"], picked["a"])
+}
+
+func TestSessionStats(t *testing.T) {
+	require := require.New(t)
+
+	check := func(s *SessionStats, sessionID uint32, items, newItems int, expB Behaviour, expOk bool) {
+		b, ok := s.AddChunk(sessionID, items, newItems, 0.5)
+		require.Equal(expOk, ok)
+		if ok {
+			require.Equal(expB, b)
+		}
+	}
+	s := &SessionStats{}
+	check(s, 1, 0, 0, 0, false)
+	check(s, 1, 10, 8, UsefulChunk, true)
+	// overlapping items are fine while the session is mostly useful
+	check(s, 1, 5, 0, 0, false)
+	check(s, 1, 1, 0, 0, false)
+	// too many duplicates in the session
+	check(s, 1, 5, 0, DuplicateItem, true)
+	check(s, 1, 10, 1, UsefulChunk, true)
+
+	// the stats are reset for a new session
+	check(s, 2, 10, 10, UsefulChunk, true)
+	check(s, 2, 10, 0, 0, false)
+	check(s, 3, 10, 0, DuplicateItem, true)
+}
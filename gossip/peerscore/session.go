@@ -0,0 +1,31 @@
+package peerscore
+
+// SessionStats counts the items which are received from a peer in a stream session.
+// Sessions of different peers overlap, so some duplicates are normal.
+// It isn't safe for concurrent use.
+type SessionStats struct {
+	id         uint32
+	items      int
+	duplicates int
+}
+
+// AddChunk accounts a chunk of the session and returns the peer behaviour.
+// A chunk without new items is penalized only if the ratio of duplicates in the session exceeds maxRatio.
+// Returns false if the chunk doesn't change the peer score.
+func (s *SessionStats) AddChunk(sessionID uint32, items, newItems int, maxRatio float64) (Behaviour, bool) {
+	if items == 0 {
+		return 0, false
+	}
+	if s.id != sessionID {
+		*s = SessionStats{id: sessionID}
+	}
+	s.items += items
+	s.duplicates += items - newItems
+	if newItems != 0 {
+		return UsefulChunk, true
+	}
+	if float64(s.duplicates) > maxRatio*float64(s.items) {
+		return DuplicateItem, true
+	}
+	return 0, false
+}
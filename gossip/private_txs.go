@@ -0,0 +1,137 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+)
+
+// DefaultPrivateTxsConfig returns default config of the private transactions
+func DefaultPrivateTxsConfig() PrivateTxsConfig {
+	return PrivateTxsConfig{
+		DefaultLifetime: 50,
+		MaxLifetime:     1000,
+	}
+}
+
+// Validate checks the private transactions config
+func (c *PrivateTxsConfig) Validate() error {
+	if c.DefaultLifetime == 0 || c.DefaultLifetime > c.MaxLifetime {
+		return errors.New("private txs DefaultLifetime has to be positive and not greater than MaxLifetime")
+	}
+	for _, url := range c.Peers {
+		if _, err := enode.Parse(enode.ValidSchemes, url); err != nil {
+			return fmt.Errorf("invalid private txs peer %s: %v", url, err)
+		}
+	}
+	return nil
+}
+
+// privateTxPeersSet returns the set of enode IDs of peers which private transactions are forwarded to.
+// It includes the validators behind the sentry in the sentry mode.
+func (c *Config) privateTxPeersSet() map[string]bool {
+	set := make(map[string]bool)
+	for _, url := range c.PrivateTxs.Peers {
+		if node, err := enode.Parse(enode.ValidSchemes, url); err == nil {
+			set[node.ID().String()] = true
+		}
+	}
+	if c.Sentry.Mode == SentryMode {
+		for id := range c.Sentry.privatePeersSet() {
+			set[id] = true
+		}
+	}
+	return set
+}
+
+// connectedPrivateTxPeers returns the connected peers which private transactions are forwarded to
+func (h *handler) connectedPrivateTxPeers(exclude string) []*peer {
+	var res []*peer
+	if len(h.privateTxPeers) == 0 {
+		return res
+	}
+	for _, p := range h.peers.List() {
+		if p.id != exclude && h.privateTxPeers[p.id] && p.version >= FTM64 {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// BroadcastPrivateTxs forwards the private transactions to the configured validators and sentries,
+// which don't know them yet. Returns the number of recipients.
+func (h *handler) BroadcastPrivateTxs(txs []privateTx, exclude string) int {
+	recipients := 0
+	for _, p := range h.connectedPrivateTxPeers(exclude) {
+		unknown := make([]privateTx, 0, len(txs))
+		for _, tx := range txs {
+			if !p.knownTxs.Contains(tx.Tx.Hash()) {
+				unknown = append(unknown, tx)
+			}
+		}
+		if len(unknown) != 0 {
+			p.AsyncSendPrivateTransactions(unknown, p.queue)
+			recipients++
+		}
+	}
+	return recipients
+}
+
+func (h *handler) handlePrivateTxs(p *peer, txs []privateTx) {
+	maxExpiry := h.store.GetLatestBlockIndex() + h.config.PrivateTxs.MaxLifetime
+	accepted := make([]privateTx, 0, len(txs))
+	for _, tx := range txs {
+		if tx.Tx == nil {
+			continue
+		}
+		p.MarkTransaction(tx.Tx.Hash())
+		if tx.Expiry > maxExpiry {
+			tx.Expiry = maxExpiry
+		}
+		if err := h.txpool.AddPrivate(tx.Tx, uint64(tx.Expiry)); err != nil {
+			log.Debug("Private transaction is rejected", "hash", tx.Tx.Hash(), "err", err)
+			continue
+		}
+		accepted = append(accepted, tx)
+	}
+	if len(accepted) != 0 {
+		h.BroadcastPrivateTxs(accepted, p.id)
+	}
+}
+
+// SendPrivateTx adds the transaction into the pool without announcing it to peers.
+// The transaction is originated by the local validator, or forwarded to the configured validators.
+// It's dropped once the expiry block is reached. Zero expiry means the default lifetime.
+func (s *Service) SendPrivateTx(tx *types.Transaction, expiry idx.Block) error {
+	cfg := s.config.PrivateTxs
+	head := s.store.GetLatestBlockIndex()
+	if expiry == 0 {
+		expiry = head + cfg.DefaultLifetime
+	}
+	if expiry <= head {
+		return evmcore.ErrPrivateTxExpired
+	}
+	if expiry > head+cfg.MaxLifetime {
+		return fmt.Errorf("private transaction expiry block is too far, max is %d", head+cfg.MaxLifetime)
+	}
+	localValidator := false
+	for _, em := range s.emitters {
+		if em.Validator() != 0 {
+			localValidator = true
+		}
+	}
+	if !localValidator && len(s.handler.connectedPrivateTxPeers("")) == 0 {
+		return errors.New("no validators are connected to originate the private transaction")
+	}
+	if err := s.txpool.AddPrivate(tx, uint64(expiry)); err != nil {
+		return err
+	}
+	s.handler.BroadcastPrivateTxs([]privateTx{{tx, expiry}}, "")
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 
+	"github.com/Fantom-foundation/go-opera/evmcore"
 	"github.com/Fantom-foundation/go-opera/gossip/emitter"
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/inter/ibr"
@@ -16,17 +17,18 @@ import (
 const (
 	FTM62           = 62
 	FTM63           = 63
-	ProtocolVersion = FTM63
+	FTM64           = 64
+	ProtocolVersion = FTM64
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 const ProtocolName = "opera"
 
 // ProtocolVersions are the supported versions of the protocol (first is primary).
-var ProtocolVersions = []uint{FTM62, FTM63}
+var ProtocolVersions = []uint{FTM62, FTM63, FTM64}
 
 // protocolLengths are the number of implemented message corresponding to different protocol versions.
-var protocolLengths = map[uint]uint64{FTM62: EventsStreamResponse + 1, FTM63: EPsStreamResponse + 1}
+var protocolLengths = map[uint]uint64{FTM62: EventsStreamResponse + 1, FTM63: EPsStreamResponse + 1, FTM64: PrivateEvmTxsMsg + 1}
 
 const protocolMaxMsgSize = inter.ProtocolMaxMsgSize // Maximum cap on the size of a protocol message
 
@@ -65,6 +67,10 @@ const (
 	BRsStreamResponse = 13
 	RequestEPsStream  = 14
 	EPsStreamResponse = 15
+
+	// Contains the batch of private transactions, which mustn't be announced to other peers.
+	// Sent only to the validators and sentries, since FTM64.
+	PrivateEvmTxsMsg = 16
 )
 
 type errCode int
@@ -106,6 +112,8 @@ type TxPool interface {
 	AddRemotes([]*types.Transaction) []error
 	AddLocals(txs []*types.Transaction) []error
 	AddLocal(tx *types.Transaction) error
+	// AddPrivate should add the transaction which isn't announced to peers, until the expiry block.
+	AddPrivate(tx *types.Transaction, expiry uint64) error
 
 	Get(common.Hash) *types.Transaction
 
@@ -117,6 +125,13 @@ type TxPool interface {
 	Content() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
 	ContentFrom(addr common.Address) (types.Transactions, types.Transactions)
 	PendingSlice() types.Transactions
+	TxInfo(hash common.Hash) *evmcore.TxInfo
+}
+
+// privateTx is a private transaction along with its expiry block
+type privateTx struct {
+	Tx     *types.Transaction
+	Expiry idx.Block
 }
 
 // handshakeData is the network packet for the initial handshake message
@@ -133,6 +148,10 @@ type PeerProgress struct {
 	LastBlockAtropos hash.Event
 	// Currently unused
 	HighestLamport idx.Lamport
+	// LowestBlockIdx and LowestEpoch are the earliest block and epoch whose history isn't pruned by the peer.
+	// Zero means the whole history is kept. Sent only since FTM64.
+	LowestBlockIdx idx.Block `rlp:"optional"`
+	LowestEpoch    idx.Epoch `rlp:"optional"`
 }
 
 type dagChunk struct {
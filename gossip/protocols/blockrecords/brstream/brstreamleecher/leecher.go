@@ -1,13 +1,13 @@
 package brstreamleecher
 
 import (
-	"math/rand"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/gossip/basestream/basestreamleecher"
 	"github.com/Fantom-foundation/lachesis-base/gossip/basestream/basestreamleecher/basepeerleecher"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockrecords/brstream"
 )
 
@@ -56,6 +56,11 @@ type Callbacks struct {
 	RequestChunk func(peer string, r brstream.Request) error
 	Suspend      func(peer string) bool
 	PeerBlock    func(peer string) idx.Block
+
+	// PeerScore is optional, peers with higher scores are preferred for new sessions
+	PeerScore func(peer string) float64
+	// Stalled is optional, it's called when a session is terminated due to no progress from the peer
+	Stalled func(peer string)
 }
 
 type sessionState struct {
@@ -78,6 +83,9 @@ func (d *Leecher) shouldTerminateSession() bool {
 
 	noProgress := time.Since(d.session.lastReceived) >= d.cfg.BaseProgressWatchdog*time.Duration(d.session.try+5)/5
 	stuck := time.Since(d.session.startTime) >= d.cfg.BaseSessionWatchdog*time.Duration(d.session.try+5)/5
+	if noProgress && d.callback.Stalled != nil && !d.callback.Suspend(d.session.peer) {
+		d.callback.Stalled(d.session.peer)
+	}
 	return stuck || noProgress
 }
 
@@ -144,7 +152,7 @@ func getSessionID(block idx.Block, try uint32) uint32 {
 }
 
 func (d *Leecher) startSession(candidates []string) {
-	peer := candidates[rand.Intn(len(candidates))]
+	peer := peerscore.Pick(candidates, d.callback.PeerScore)
 
 	start := d.callback.LowestBlockToFill()
 	end := d.callback.MaxBlockToFill()
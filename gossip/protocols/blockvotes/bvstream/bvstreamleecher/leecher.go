@@ -1,7 +1,6 @@
 package bvstreamleecher
 
 import (
-	"math/rand"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/gossip/basestream/basestreamleecher"
@@ -9,6 +8,7 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/hash"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/blockvotes/bvstream"
 )
 
@@ -56,6 +56,11 @@ type Callbacks struct {
 	RequestChunk func(peer string, r bvstream.Request) error
 	Suspend      func(peer string) bool
 	PeerBlock    func(peer string) idx.Block
+
+	// PeerScore is optional, peers with higher scores are preferred for new sessions
+	PeerScore func(peer string) float64
+	// Stalled is optional, it's called when a session is terminated due to no progress from the peer
+	Stalled func(peer string)
 }
 
 type sessionState struct {
@@ -84,6 +89,9 @@ func (d *Leecher) shouldTerminateSession() bool {
 
 	noProgress := time.Since(d.session.lastReceived) >= d.cfg.BaseProgressWatchdog*time.Duration(d.session.try+5)/5
 	stuck := time.Since(d.session.startTime) >= d.cfg.BaseSessionWatchdog*time.Duration(d.session.try+5)/5
+	if noProgress && d.callback.Stalled != nil && !d.callback.Suspend(d.session.peer) {
+		d.callback.Stalled(d.session.peer)
+	}
 	return stuck || noProgress
 }
 
@@ -136,7 +144,7 @@ func getSessionID(block idx.Block, try uint32) uint32 {
 }
 
 func (d *Leecher) startSession(candidates []string) {
-	peer := candidates[rand.Intn(len(candidates))]
+	peer := peerscore.Pick(candidates, d.callback.PeerScore)
 
 	startEpoch, startBlock := d.callback.LowestBlockToDecide()
 	endEpoch := d.callback.MaxEpochToDecide()
@@ -1,7 +1,6 @@
 package dagstreamleecher
 
 import (
-	"math/rand"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/gossip/basestream/basestreamleecher"
@@ -10,6 +9,7 @@ import (
 	"github.com/Fantom-foundation/lachesis-base/inter/dag"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/dag/dagstream"
 )
 
@@ -60,6 +60,11 @@ type Callbacks struct {
 	RequestChunk func(peer string, r dagstream.Request) error
 	Suspend      func(peer string) bool
 	PeerEpoch    func(peer string) idx.Epoch
+
+	// PeerScore is optional, peers with higher scores are preferred for new sessions
+	PeerScore func(peer string) float64
+	// Stalled is optional, it's called when a session is terminated due to no progress from the peer
+	Stalled func(peer string)
 }
 
 type sessionState struct {
@@ -78,6 +83,9 @@ func (d *Leecher) shouldTerminateSession() bool {
 
 	noProgress := time.Since(d.session.lastReceived) >= d.cfg.BaseProgressWatchdog*time.Duration(d.session.try+5)/5
 	stuck := time.Since(d.session.startTime) >= d.cfg.BaseSessionWatchdog*time.Duration(d.session.try+5)/5
+	if noProgress && d.callback.Stalled != nil && !d.callback.Suspend(d.session.peer) {
+		d.callback.Stalled(d.session.peer)
+	}
 	return stuck || noProgress
 }
 
@@ -139,7 +147,7 @@ func getSessionID(epoch idx.Epoch, try uint32) uint32 {
 }
 
 func (d *Leecher) startSession(candidates []string) {
-	peer := candidates[rand.Intn(len(candidates))]
+	peer := peerscore.Pick(candidates, d.callback.PeerScore)
 
 	typ := dagstream.RequestIDs
 	if d.callback.PeerEpoch(peer) > d.epoch && d.emptyState && d.session.try == 0 {
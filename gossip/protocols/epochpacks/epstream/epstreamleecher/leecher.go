@@ -1,13 +1,13 @@
 package epstreamleecher
 
 import (
-	"math/rand"
 	"time"
 
 	"github.com/Fantom-foundation/lachesis-base/gossip/basestream/basestreamleecher"
 	"github.com/Fantom-foundation/lachesis-base/gossip/basestream/basestreamleecher/basepeerleecher"
 	"github.com/Fantom-foundation/lachesis-base/inter/idx"
 
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
 	"github.com/Fantom-foundation/go-opera/gossip/protocols/epochpacks/epstream"
 )
 
@@ -55,6 +55,11 @@ type Callbacks struct {
 	RequestChunk func(peer string, r epstream.Request) error
 	Suspend      func(peer string) bool
 	PeerEpoch    func(peer string) idx.Epoch
+
+	// PeerScore is optional, peers with higher scores are preferred for new sessions
+	PeerScore func(peer string) float64
+	// Stalled is optional, it's called when a session is terminated due to no progress from the peer
+	Stalled func(peer string)
 }
 
 type sessionState struct {
@@ -77,6 +82,9 @@ func (d *Leecher) shouldTerminateSession() bool {
 
 	noProgress := time.Since(d.session.lastReceived) >= d.cfg.BaseProgressWatchdog*time.Duration(d.session.try+5)/5
 	stuck := time.Since(d.session.startTime) >= d.cfg.BaseSessionWatchdog*time.Duration(d.session.try+5)/5
+	if noProgress && d.callback.Stalled != nil && !d.callback.Suspend(d.session.peer) {
+		d.callback.Stalled(d.session.peer)
+	}
 	return stuck || noProgress
 }
 
@@ -127,7 +135,7 @@ func getSessionID(epoch idx.Epoch, try uint32) uint32 {
 }
 
 func (d *Leecher) startSession(candidates []string) {
-	peer := candidates[rand.Intn(len(candidates))]
+	peer := peerscore.Pick(candidates, d.callback.PeerScore)
 
 	start := d.callback.LowestEpochToFetch()
 	end := d.callback.MaxEpochToFetch()
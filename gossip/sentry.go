@@ -0,0 +1,63 @@
+package gossip
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// SentryValidatorMode is the mode of a validator which is connected only to its sentries
+	SentryValidatorMode = "validator"
+	// SentryMode is the mode of a sentry which relays the network traffic to its validators
+	SentryMode = "sentry"
+)
+
+// Nodes parses the enode URLs of the private peers
+func (c *SentryConfig) Nodes() ([]*enode.Node, error) {
+	nodes := make([]*enode.Node, 0, len(c.PrivatePeers))
+	for _, url := range c.PrivatePeers {
+		node, err := enode.Parse(enode.ValidSchemes, url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private peer %s: %v", url, err)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// Validate checks the sentry config
+func (c *SentryConfig) Validate() error {
+	switch c.Mode {
+	case "":
+		if len(c.PrivatePeers) != 0 {
+			return errors.New("private peers are specified without a sentry mode")
+		}
+		return nil
+	case SentryValidatorMode:
+		if len(c.PrivatePeers) == 0 {
+			return errors.New("sentry nodes must be specified in the validator mode")
+		}
+	case SentryMode:
+	default:
+		return fmt.Errorf("unknown sentry mode '%s': has to be either '%s' or '%s'", c.Mode, SentryValidatorMode, SentryMode)
+	}
+	_, err := c.Nodes()
+	return err
+}
+
+// privatePeersSet returns the set of enode IDs of the private peers
+func (c *SentryConfig) privatePeersSet() map[string]bool {
+	nodes, _ := c.Nodes()
+	set := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		set[node.ID().String()] = true
+	}
+	return set
+}
+
+// isPrivateMesh returns true if node is a validator which is connected only to its sentries
+func (c *SentryConfig) isPrivateMesh() bool {
+	return c.Mode == SentryValidatorMode
+}
@@ -0,0 +1,196 @@
+package gossip
+
+import (
+	"crypto/ecdsa"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/utils/cachescale"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/protocols/snap"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/discover/discfilter"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/gossip/peerscore"
+	"github.com/Fantom-foundation/go-opera/inter"
+)
+
+func newTestNode(t *testing.T) (*enode.Node, *ecdsa.PrivateKey) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return enode.NewV4(&key.PublicKey, net.IP{127, 0, 0, 1}, 5050, 5050), key
+}
+
+// newTestSentryHandler creates a handler with only the peers management
+func newTestSentryHandler(mode string, private ...*enode.Node) *handler {
+	cfg := DefaultConfig(cachescale.Identity)
+	cfg.Sentry.Mode = mode
+	for _, node := range private {
+		cfg.Sentry.PrivatePeers = append(cfg.Sentry.PrivatePeers, node.URLv4())
+	}
+	return &handler{
+		config:       cfg,
+		maxPeers:     50,
+		peers:        newPeerSet(),
+		peerScores:   peerscore.New(cfg.Protocol.PeerScore),
+		privatePeers: cfg.Sentry.privatePeersSet(),
+	}
+}
+
+// newTestPeer creates a peer, the sent messages are received from the returned pipe end
+func newTestPeer(h *handler, node *enode.Node) (*peer, *p2p.MsgPipeRW) {
+	local, remote := p2p.MsgPipe()
+	p := newPeer(FTM64, p2p.NewPeer(node.ID(), "opera", nil), local, h.config.Protocol.PeerCache)
+	return p, remote
+}
+
+func readMsgCode(t *testing.T, rw p2p.MsgReader) uint64 {
+	msg, err := rw.ReadMsg()
+	require.NoError(t, err)
+	require.NoError(t, msg.Discard())
+	return msg.Code
+}
+
+func TestPeerBroadcastPriority(t *testing.T) {
+	require := require.New(t)
+
+	node, _ := newTestNode(t)
+	h := newTestSentryHandler("")
+	p, remote := newTestPeer(h, node)
+	defer p.Close()
+
+	raw, _ := rlp.EncodeToBytes(uint(0))
+	// the first item is sent and blocked until it's read
+	require.True(p.asyncSendEncodedItem(raw, 1, p.queue))
+	for len(p.queue) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	require.True(p.asyncSendEncodedItem(raw, 2, p.queue))
+	require.True(p.asyncSendEncodedItem(raw, 3, p.queue))
+	require.True(p.asyncSendEncodedItem(raw, 4, p.priorityQueue))
+	require.True(p.asyncSendEncodedItem(raw, 5, p.priorityQueue))
+
+	codes := make([]uint64, 5)
+	for i := range codes {
+		codes[i] = readMsgCode(t, remote)
+	}
+	require.Equal([]uint64{1, 4, 5, 2, 3}, codes)
+}
+
+func TestSentryValidatorMode(t *testing.T) {
+	require := require.New(t)
+
+	sentry, _ := newTestNode(t)
+	other, _ := newTestNode(t)
+	h := newTestSentryHandler(SentryValidatorMode, sentry)
+
+	// eth handshake
+	p, _ := newTestPeer(h, other)
+	defer p.Close()
+	_, err := h.admitPeer(p)
+	require.Equal(p2p.DiscUselessPeer, err)
+	p, _ = newTestPeer(h, sentry)
+	defer p.Close()
+	useless, err := h.admitPeer(p)
+	require.NoError(err)
+	require.False(useless)
+	require.True(p.Private())
+
+	// snap handshake
+	runSnap := func(node *enode.Node) error {
+		protocol := snap.MakeProtocols((*snapHandler)(h), nil)[0]
+		local, _ := p2p.MsgPipe()
+		defer local.Close()
+		return protocol.Run(p2p.NewPeer(node.ID(), "opera", nil), local)
+	}
+	require.Equal(p2p.DiscUselessPeer, runSnap(other))
+	// the private peer passes the sentry check, but the test peer doesn't run the opera protocol
+	require.Equal(errSnapWithoutOpera, runSnap(sentry))
+
+	// a regular node doesn't reject the peers
+	h = newTestSentryHandler(SentryMode, sentry)
+	p, _ = newTestPeer(h, other)
+	defer p.Close()
+	_, err = h.admitPeer(p)
+	require.NoError(err)
+	require.False(p.Private())
+	require.Equal(errSnapWithoutOpera, runSnap(other))
+}
+
+func TestPrivatePeersNotBanned(t *testing.T) {
+	require := require.New(t)
+
+	discfilter.Enable()
+	private, _ := newTestNode(t)
+	other, _ := newTestNode(t)
+	h := newTestSentryHandler(SentryMode, private)
+
+	// the discovery filter
+	discfilter.Ban(private.ID())
+	discfilter.Ban(other.ID())
+	p, _ := newTestPeer(h, private)
+	defer p.Close()
+	useless, err := h.admitPeer(p)
+	require.NoError(err)
+	require.False(useless)
+	require.False(p.Useless())
+	p, _ = newTestPeer(h, other)
+	defer p.Close()
+	useless, err = h.admitPeer(p)
+	require.NoError(err)
+	require.True(useless)
+	require.True(p.Useless())
+
+	// the peer score ban
+	for _, node := range []*enode.Node{private, other} {
+		for !h.peerScores.Banned(node.ID().String()) {
+			h.peerBehaviour(node.ID().String(), peerscore.InvalidItem)
+		}
+	}
+	p, _ = newTestPeer(h, private)
+	defer p.Close()
+	_, err = h.admitPeer(p)
+	require.NoError(err)
+	p, _ = newTestPeer(h, other)
+	defer p.Close()
+	_, err = h.admitPeer(p)
+	require.Equal(p2p.DiscUselessPeer, err)
+}
+
+func TestBroadcastEventToPrivatePeers(t *testing.T) {
+	require := require.New(t)
+
+	private, _ := newTestNode(t)
+	other, _ := newTestNode(t)
+	h := newTestSentryHandler(SentryMode, private)
+	// no regular peers receive the full events
+	h.config.Protocol.LatencyImportance = 0
+	h.config.Protocol.ThroughputImportance = 1
+
+	pipes := make(map[enode.ID]*p2p.MsgPipeRW)
+	for _, node := range []*enode.Node{private, other} {
+		p, remote := newTestPeer(h, node)
+		defer p.Close()
+		_, err := h.admitPeer(p)
+		require.NoError(err)
+		p.SetProgress(PeerProgress{Epoch: 1})
+		require.NoError(h.peers.RegisterPeer(p, nil))
+		pipes[node.ID()] = remote
+	}
+
+	me := &inter.MutableEventPayload{}
+	me.SetEpoch(1)
+	me.SetVersion(1)
+	me.SetLamport(1)
+	event := me.Build()
+	require.Equal(2, h.BroadcastEvent(event, time.Second))
+
+	require.Equal(uint64(EventsMsg), readMsgCode(t, pipes[private.ID()]))
+	require.Equal(uint64(NewEventIDsMsg), readMsgCode(t, pipes[other.ID()]))
+	// the event is known by the peers
+	require.Equal(0, h.BroadcastEvent(event, time.Second))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"math/rand"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -47,6 +48,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/gasprice"
 	"github.com/Fantom-foundation/go-opera/gossip/proclogger"
 	snapsync "github.com/Fantom-foundation/go-opera/gossip/protocols/snap"
+	"github.com/Fantom-foundation/go-opera/gossip/traces"
 	"github.com/Fantom-foundation/go-opera/inter"
 	"github.com/Fantom-foundation/go-opera/logger"
 	"github.com/Fantom-foundation/go-opera/utils/signers/gsignercache"
@@ -60,6 +62,7 @@ type ServiceFeed struct {
 
 	newEpoch        notify.Feed
 	newEmittedEvent notify.Feed
+	newEvent        notify.Feed
 	newBlock        notify.Feed
 	newLogs         notify.Feed
 }
@@ -72,6 +75,10 @@ func (f *ServiceFeed) SubscribeNewEmitted(ch chan<- *inter.EventPayload) notify.
 	return f.scope.Track(f.newEmittedEvent.Subscribe(ch))
 }
 
+func (f *ServiceFeed) SubscribeNewEvent(ch chan<- *inter.EventPayload) notify.Subscription {
+	return f.scope.Track(f.newEvent.Subscribe(ch))
+}
+
 func (f *ServiceFeed) SubscribeNewBlock(ch chan<- evmcore.ChainHeadNotify) notify.Subscription {
 	return f.scope.Track(f.newBlock.Subscribe(ch))
 }
@@ -154,6 +161,15 @@ type Service struct {
 
 	tflusher PeriodicFlusher
 
+	historyPruner   *historyPruner
+	freezerMigrator *freezerMigrator
+	// historyMu serializes the history pruning and the migration into the freezer
+	historyMu sync.Mutex
+
+	healthServer *http.Server
+
+	txPolicyReloader func() error
+
 	logger.Instance
 }
 
@@ -255,7 +271,11 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 			Event: func(event *inter.EventPayload) error {
 				done := svc.procLogger.EventConnectionStarted(event, false)
 				defer done()
-				return svc.processEvent(event)
+				err := svc.processEvent(event)
+				if err == nil {
+					svc.feed.newEvent.Send(event)
+				}
+				return err
 			},
 			SwitchEpochTo:    svc.SwitchEpochTo,
 			PauseEvmSnapshot: svc.PauseEvmSnapshot,
@@ -276,6 +296,8 @@ func newService(config Config, store *Store, blockProc BlockProc, engine lachesi
 
 	svc.verWatcher = verwatcher.New(netVerStore)
 	svc.tflusher = svc.makePeriodicFlusher()
+	svc.historyPruner = newHistoryPruner(svc, config.HistoryRetention)
+	svc.freezerMigrator = newFreezerMigrator(svc, config.Freezer)
 
 	return svc, nil
 }
@@ -349,6 +371,11 @@ func (s *Service) RegisterEmitter(em *emitter.Emitter) {
 // MakeProtocols constructs the P2P protocol definitions for `opera`.
 func MakeProtocols(svc *Service, backend *handler, disc enode.Iterator) []p2p.Protocol {
 	protocols := make([]p2p.Protocol, len(ProtocolVersions))
+	var attributes []enr.Entry
+	if !svc.config.Sentry.isPrivateMesh() {
+		// validator behind sentries isn't advertised
+		attributes = []enr.Entry{currentENREntry(svc)}
+	}
 	for i, version := range ProtocolVersions {
 		version := version // Closure
 
@@ -380,7 +407,7 @@ func MakeProtocols(svc *Service, backend *handler, disc enode.Iterator) []p2p.Pr
 				}
 				return nil
 			},
-			Attributes:     []enr.Entry{currentENREntry(svc)},
+			Attributes:     attributes,
 			DialCandidates: disc,
 		}
 	}
@@ -420,9 +447,22 @@ func (s *Service) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateAdminAPI(s),
 		},
 	}...)
 
+	if s.config.TraceIndex {
+		apis = append(apis, rpc.API{
+			Namespace: "trace",
+			Version:   "1.0",
+			Service:   traces.NewPublicTraceAPI(s.EthAPI, s.config.TraceAPI),
+			Public:    true,
+		})
+	}
+
 	return apis
 }
 
@@ -444,12 +484,19 @@ func (s *Service) Start() error {
 		root = hash.Zero
 	}
 	_ = s.store.GenerateSnapshotAt(common.Hash(root), true)
+	if err := s.store.evm.ResumeLivePruning(s.livePruningRoots); err != nil {
+		log.Error("Failed to resume live EVM state pruning", "err", err)
+	}
+	s.historyPruner.Start()
+	s.freezerMigrator.Start()
 
 	// start blocks processor
 	s.blockProcTasks.Start(1)
 
 	// start p2p
-	StartENRUpdater(s, s.p2pServer.LocalNode())
+	if !s.config.Sentry.isPrivateMesh() {
+		StartENRUpdater(s, s.p2pServer.LocalNode())
+	}
 	s.handler.Start(s.p2pServer.MaxPeers)
 
 	// start emitters
@@ -459,6 +506,10 @@ func (s *Service) Start() error {
 
 	s.verWatcher.Start()
 
+	if err := s.startHealthServer(); err != nil {
+		return err
+	}
+
 	if s.haltCheck != nil && s.haltCheck(s.store.GetEpoch(), s.store.GetEpoch(), s.store.GetBlockState().LastBlock.Time.Time()) {
 		// halt syncing
 		s.stopped = true
@@ -475,6 +526,7 @@ func (s *Service) WaitBlockEnd() {
 // Stop method invoked when the node terminates the service.
 func (s *Service) Stop() error {
 	defer log.Info("Fantom service stopped")
+	s.stopHealthServer()
 	s.verWatcher.Stop()
 	for _, em := range s.emitters {
 		em.Stop()
@@ -488,8 +540,10 @@ func (s *Service) Stop() error {
 	s.feed.scope.Close()
 	s.eventMux.Stop()
 	s.gpo.Stop()
-	// it's safe to stop tflusher only before locking engineMu
+	// it's safe to stop tflusher, historyPruner and freezerMigrator only before locking engineMu
 	s.tflusher.Stop()
+	s.historyPruner.Stop()
+	s.freezerMigrator.Stop()
 
 	// flush the state at exit, after all the routines stopped
 	s.engineMu.Lock()
@@ -502,6 +556,11 @@ func (s *Service) Stop() error {
 	return s.store.Commit()
 }
 
+// SetTxPolicyReloader sets the function which reloads the txpool admission policy, see admin_reloadTxPolicy
+func (s *Service) SetTxPolicyReloader(reload func() error) {
+	s.txPolicyReloader = reload
+}
+
 // AccountManager return node's account manager
 func (s *Service) AccountManager() *accounts.Manager {
 	return s.accountManager
@@ -17,6 +17,7 @@ import (
 	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
 	"github.com/Fantom-foundation/go-opera/logger"
 	"github.com/Fantom-foundation/go-opera/utils/adapters/snap2kvdb"
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/freezer"
 	"github.com/Fantom-foundation/go-opera/utils/eventid"
 	"github.com/Fantom-foundation/go-opera/utils/randat"
 	"github.com/Fantom-foundation/go-opera/utils/rlpstore"
@@ -49,7 +50,8 @@ type Store struct {
 		NetworkVersion kvdb.Store `table:"V"`
 
 		// API-only
-		BlockHashes kvdb.Store `table:"B"`
+		BlockHashes  kvdb.Store `table:"B"`
+		EpochMetrics kvdb.Store `table:"m"`
 
 		LlrState           kvdb.Store `table:"S"`
 		LlrBlockResults    kvdb.Store `table:"R"`
@@ -60,9 +62,20 @@ type Store struct {
 		LlrEpochVoteIndex  kvdb.Store `table:"I"`
 		LlrLastBlockVotes  kvdb.Store `table:"G"`
 		LlrLastEpochVote   kvdb.Store `table:"F"`
+		// Imported block and epoch records which aren't verified by the LLR votes
+		LlrUnverifiedRecords kvdb.Store `table:"u"`
+
+		// History retention
+		HistoryStart kvdb.Store `table:"p"`
+		// Progress of the migration into the freezer
+		FreezerProgress kvdb.Store `table:"f"`
 	}
 
+	freezer *freezer.Freezer
+
 	prevFlushTime time.Time
+	// lastFlushTime is prevFlushTime in nanoseconds, which is safe to read concurrently
+	lastFlushTime int64
 
 	epochStore atomic.Value
 
@@ -82,6 +95,8 @@ type Store struct {
 		KvdbEvmSnap            atomic.Value // store by pointer
 		UpgradeHeights         atomic.Value // store by pointer
 		Genesis                atomic.Value // store by value
+		HistoryStart           atomic.Value // store by value
+		FreezerProgress        atomic.Value // store by value
 		LlrBlockVotesIndex     *VotesCache  // store by pointer
 		LlrEpochVoteIndex      *VotesCache  // store by pointer
 	}
@@ -114,6 +129,8 @@ func NewStore(dbs kvdb.FlushableDBProducer, cfg StoreConfig) *Store {
 		rlp:           rlpstore.Helper{logger.New("rlp")},
 	}
 
+	s.lastFlushTime = s.prevFlushTime.UnixNano()
+
 	err := table.OpenTables(&s.table, dbs, "gossip")
 	if err != nil {
 		log.Crit("Failed to open DB", "name", "gossip", "err", err)
@@ -121,6 +138,7 @@ func NewStore(dbs kvdb.FlushableDBProducer, cfg StoreConfig) *Store {
 
 	s.initCache()
 	s.evm = evmstore.NewStore(dbs, cfg.EVM)
+	s.openFreezer()
 
 	if err := s.migrateData(); err != nil {
 		s.Log.Crit("Failed to migrate Gossip DB", "err", err)
@@ -163,6 +181,9 @@ func (s *Store) Close() {
 
 	_ = s.closeEpochStore()
 	s.evm.Close()
+	if s.freezer != nil {
+		_ = s.freezer.Close()
+	}
 }
 
 func (s *Store) IsCommitNeeded() bool {
@@ -230,10 +251,17 @@ func (s *Store) Commit() error {
 
 func (s *Store) flushDBs() error {
 	s.prevFlushTime = time.Now()
+	atomic.StoreInt64(&s.lastFlushTime, s.prevFlushTime.UnixNano())
 	flushID := bigendian.Uint64ToBytes(uint64(s.prevFlushTime.UnixNano()))
 	return s.dbs.Flush(flushID)
 }
 
+// LastFlushTime returns time of the last DBs flush, or the store opening time if DBs weren't flushed yet.
+// It's safe for concurrent use.
+func (s *Store) LastFlushTime() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&s.lastFlushTime))
+}
+
 func (s *Store) EvmStore() *evmstore.Store {
 	return s.evm
 }
@@ -1,6 +1,7 @@
 package gossip
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/Fantom-foundation/lachesis-base/hash"
@@ -69,6 +70,9 @@ func (s *Store) GetBlock(n idx.Block) *inter.Block {
 	}
 
 	block, _ := s.rlp.Get(s.table.Blocks, n.Bytes(), &inter.Block{}).(*inter.Block)
+	if block == nil {
+		block = s.getFrozenBlock(n)
+	}
 
 	// Add to LRU cache.
 	if block != nil {
@@ -80,11 +84,20 @@ func (s *Store) GetBlock(n idx.Block) *inter.Block {
 
 func (s *Store) HasBlock(n idx.Block) bool {
 	has, _ := s.table.Blocks.Has(n.Bytes())
-	return has
+	return has || s.getFrozenBlock(n) != nil
 }
 
 func (s *Store) ForEachBlock(fn func(index idx.Block, block *inter.Block)) {
-	it := s.table.Blocks.NewIterator(nil, nil)
+	progress := s.GetFreezerProgress().Block
+	if s.freezer != nil {
+		tail, _ := s.freezer.Table(frozenBlocks).Items()
+		for n := idx.Block(tail); n < progress; n++ {
+			if block := s.getFrozenBlock(n); block != nil {
+				fn(n, block)
+			}
+		}
+	}
+	it := s.table.Blocks.NewIterator(nil, progress.Bytes())
 	defer it.Release()
 	for it.Next() {
 		var block inter.Block
@@ -105,6 +118,15 @@ func (s *Store) SetBlockIndex(id hash.Event, n idx.Block) {
 	s.cache.BlockHashes.Add(id, n, nominalSize)
 }
 
+// DelBlockIndex deletes chain block index.
+func (s *Store) DelBlockIndex(id hash.Event) {
+	if err := s.table.BlockHashes.Delete(id.Bytes()); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+
+	s.cache.BlockHashes.Remove(id)
+}
+
 // GetBlockIndex returns stored block index.
 func (s *Store) GetBlockIndex(id hash.Event) *idx.Block {
 	nVal, ok := s.cache.BlockHashes.Get(id)
@@ -183,37 +205,43 @@ func (s *Store) FindBlockEpoch(b idx.Block) idx.Epoch {
 }
 
 func (s *Store) GetBlockTxs(n idx.Block, block *inter.Block) types.Transactions {
+	transactions, err := s.TryGetBlockTxs(n, block)
+	if err != nil {
+		log.Crit("Failed to get block transactions", "block", n, "err", err)
+	}
+	return transactions
+}
+
+// TryGetBlockTxs is like GetBlockTxs, but it returns an error if the block data is missing, e.g. pruned
+func (s *Store) TryGetBlockTxs(n idx.Block, block *inter.Block) (types.Transactions, error) {
 	if cached := s.evm.GetCachedEvmBlock(n); cached != nil {
-		return cached.Transactions
+		return cached.Transactions, nil
 	}
 
 	transactions := make(types.Transactions, 0, len(block.Txs)+len(block.InternalTxs)+len(block.Events)*10)
 	for _, txid := range block.InternalTxs {
 		tx := s.evm.GetTx(txid)
 		if tx == nil {
-			log.Crit("Internal tx not found", "tx", txid.String())
-			continue
+			return nil, fmt.Errorf("internal tx %s not found", txid.String())
 		}
 		transactions = append(transactions, tx)
 	}
 	for _, txid := range block.Txs {
 		tx := s.evm.GetTx(txid)
 		if tx == nil {
-			log.Crit("Tx not found", "tx", txid.String())
-			continue
+			return nil, fmt.Errorf("tx %s not found", txid.String())
 		}
 		transactions = append(transactions, tx)
 	}
 	for _, id := range block.Events {
 		e := s.GetEventPayload(id)
 		if e == nil {
-			log.Crit("Block event not found", "event", id.String())
-			continue
+			return nil, fmt.Errorf("block event %s not found", id.String())
 		}
 		transactions = append(transactions, e.Txs()...)
 	}
 
 	transactions = inter.FilterSkippedTxs(transactions, block.SkippedTxs)
 
-	return transactions
+	return transactions, nil
 }
@@ -0,0 +1,40 @@
+package gossip
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/Fantom-foundation/go-opera/gossip/blockproc/drivermodule"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+)
+
+// calcEpochMetrics calculates the validators metrics of the epoch as of the given block.
+func calcEpochMetrics(block iblockproc.BlockCtx, bs iblockproc.BlockState, es iblockproc.EpochState) *iblockproc.EpochMetrics {
+	metrics := drivermodule.EpochMetrics(block, bs, es)
+	res := &iblockproc.EpochMetrics{
+		Epoch:      es.Epoch,
+		EpochGas:   bs.EpochGas,
+		Validators: make([]iblockproc.ValidatorEpochMetrics, len(metrics)),
+	}
+	for i, m := range metrics {
+		res.Validators[i] = iblockproc.ValidatorEpochMetrics{
+			ID:         es.Validators.GetID(idx.Validator(i)),
+			Missed:     m.Missed,
+			Uptime:     m.Uptime,
+			Originated: new(big.Int).Set(m.OriginatedTxFee),
+		}
+	}
+	return res
+}
+
+// SetEpochMetrics stores the validators metrics of a sealed epoch.
+func (s *Store) SetEpochMetrics(m *iblockproc.EpochMetrics) {
+	s.rlp.Set(s.table.EpochMetrics, m.Epoch.Bytes(), m)
+}
+
+// GetEpochMetrics returns the validators metrics of a sealed epoch, or nil if they weren't recorded.
+func (s *Store) GetEpochMetrics(epoch idx.Epoch) *iblockproc.EpochMetrics {
+	m, _ := s.rlp.Get(s.table.EpochMetrics, epoch.Bytes(), &iblockproc.EpochMetrics{}).(*iblockproc.EpochMetrics)
+	return m
+}
@@ -0,0 +1,60 @@
+package gossip
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/utils"
+)
+
+func TestEpochMetrics(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	const validatorsNum = 3
+
+	env := newTestEnv(2, validatorsNum)
+	defer env.Close()
+
+	// start a new epoch
+	_, err := env.ApplyTxs(nextEpoch, env.Transfer(1, 2, utils.ToFtm(1)))
+	require.NoError(err)
+	sealed := env.store.GetEpoch()
+	startBs, startEs := env.store.GetHistoryBlockEpochState(sealed)
+	require.NotNil(startBs)
+
+	// originate fees in the epoch
+	txs := make([]*types.Transaction, validatorsNum)
+	for i := range txs {
+		txs[i] = env.Transfer(idx.ValidatorID(i+1), 1, utils.ToFtm(1))
+	}
+	_, err = env.ApplyTxs(sameEpoch, txs...)
+	require.NoError(err)
+
+	// seal the epoch
+	_, err = env.ApplyTxs(nextEpoch, env.Transfer(1, 2, utils.ToFtm(1)))
+	require.NoError(err)
+	require.Equal(sealed+1, env.store.GetEpoch())
+
+	metrics := env.store.GetEpochMetrics(sealed)
+	require.NotNil(metrics)
+	require.Equal(sealed, metrics.Epoch)
+	require.NotZero(metrics.EpochGas)
+	require.Len(metrics.Validators, validatorsNum)
+
+	totalFee := new(big.Int)
+	for _, m := range metrics.Validators {
+		require.True(startEs.Validators.Exists(m.ID))
+		fee := new(big.Int).Sub(m.Originated, startBs.GetValidatorState(m.ID, startEs.Validators).Originated)
+		require.True(fee.Sign() >= 0)
+		totalFee.Add(totalFee, fee)
+	}
+	require.True(totalFee.Sign() > 0)
+
+	require.Nil(env.store.GetEpochMetrics(sealed + 1))
+}
@@ -52,6 +52,9 @@ func (s *Store) GetEventPayload(id hash.Event) *inter.EventPayload {
 
 	key := id.Bytes()
 	w, _ := s.rlp.Get(s.table.Events, key, &inter.EventPayload{}).(*inter.EventPayload)
+	if w == nil {
+		w = s.getFrozenEvent(id)
+	}
 
 	if w != nil {
 		fixEventTxHashes(w)
@@ -76,6 +79,9 @@ func (s *Store) GetEvent(id hash.Event) *inter.Event {
 
 	key := id.Bytes()
 	w, _ := s.rlp.Get(s.table.Events, key, &inter.EventPayload{}).(*inter.EventPayload)
+	if w == nil {
+		w = s.getFrozenEvent(id)
+	}
 	if w == nil {
 		return nil
 	}
@@ -104,20 +110,43 @@ func (s *Store) forEachEvent(it ethdb.Iterator, onEvent func(event *inter.EventP
 	}
 }
 
+func (s *Store) decodeEvent(onEvent func(event *inter.EventPayload) bool) func(key hash.Event, event rlp.RawValue) bool {
+	return func(_ hash.Event, raw rlp.RawValue) bool {
+		event := &inter.EventPayload{}
+		err := rlp.DecodeBytes(raw, event)
+		if err != nil {
+			s.Log.Crit("Failed to decode event", "err", err)
+		}
+		return onEvent(event)
+	}
+}
+
 func (s *Store) ForEachEpochEvent(epoch idx.Epoch, onEvent func(event *inter.EventPayload) bool) {
+	if epoch < s.GetFreezerProgress().Epoch {
+		s.forEachFrozenEvent(epoch.Bytes(), func(key hash.Event, event rlp.RawValue) bool {
+			return key.Epoch() == epoch && s.decodeEvent(onEvent)(key, event)
+		})
+		return
+	}
 	it := s.table.Events.NewIterator(epoch.Bytes(), nil)
 	defer it.Release()
 	s.forEachEvent(it, onEvent)
 }
 
 func (s *Store) ForEachEvent(start idx.Epoch, onEvent func(event *inter.EventPayload) bool) {
-	it := s.table.Events.NewIterator(nil, start.Bytes())
+	if !s.forEachFrozenEvent(start.Bytes(), s.decodeEvent(onEvent)) {
+		return
+	}
+	it := s.table.Events.NewIterator(nil, s.notFrozenEventsStart(start.Bytes()))
 	defer it.Release()
 	s.forEachEvent(it, onEvent)
 }
 
 func (s *Store) ForEachEventRLP(start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) {
-	it := s.table.Events.NewIterator(nil, start)
+	if !s.forEachFrozenEvent(start, onEvent) {
+		return
+	}
+	it := s.table.Events.NewIterator(nil, s.notFrozenEventsStart(start))
 	defer it.Release()
 	for it.Next() {
 		if !onEvent(hash.BytesToEvent(it.Key()), it.Value()) {
@@ -132,6 +161,16 @@ func (s *Store) FindEventHashes(epoch idx.Epoch, lamport idx.Lamport, hashPrefix
 	prefix.Write(hashPrefix)
 	res := make(hash.Events, 0, 10)
 
+	if epoch < s.GetFreezerProgress().Epoch {
+		s.forEachFrozenEvent(prefix.Bytes(), func(key hash.Event, _ rlp.RawValue) bool {
+			if !bytes.HasPrefix(key.Bytes(), prefix.Bytes()) {
+				return false
+			}
+			res = append(res, key)
+			return true
+		})
+		return res
+	}
 	it := s.table.Events.NewIterator(prefix.Bytes(), nil)
 	defer it.Release()
 	for it.Next() {
@@ -149,6 +188,9 @@ func (s *Store) GetEventPayloadRLP(id hash.Event) rlp.RawValue {
 	if err != nil {
 		s.Log.Crit("Failed to get key-value", "err", err)
 	}
+	if data == nil {
+		return s.getFrozenEventRLP(id)
+	}
 	return data
 }
 
@@ -158,7 +200,7 @@ func (s *Store) HasEvent(h hash.Event) bool {
 		return has
 	}
 	has, _ := s.table.Events.Has(h.Bytes())
-	return has
+	return has || s.getFrozenEventRLP(h) != nil
 }
 
 func (s *Store) loadHighestLamport() idx.Lamport {
@@ -0,0 +1,350 @@
+package gossip
+
+import (
+	"bytes"
+
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/utils/dbutil/freezer"
+)
+
+/*
+	The freezer keeps the finalized blocks, receipts and events of old epochs in flat files.
+	Blocks and receipts are numbered by block index. Each item of the events table is the event key followed by
+	the event RLP, events of an epoch are sorted by key. Each item of the epochs table is the number of
+	the first events item of the epoch.
+	Items of a migration step are appended and synced before they are deleted from the key-value DB,
+	so readers always find them in either of the stores.
+*/
+
+const (
+	frozenBlocks   = "blocks"
+	frozenReceipts = "receipts"
+	frozenEvents   = "events"
+	frozenEpochs   = "epochs"
+
+	eventKeySize = 32
+)
+
+// FreezerProgress is the first epoch and block which aren't moved into the freezer.
+type FreezerProgress struct {
+	Epoch idx.Epoch
+	Block idx.Block
+}
+
+func (s *Store) openFreezer() {
+	if len(s.cfg.FreezerDir) == 0 {
+		if s.GetFreezerProgress() != (FreezerProgress{}) {
+			s.Log.Crit("Freezer isn't configured, but the history was moved into it")
+		}
+		return
+	}
+	f, err := freezer.Open(s.cfg.FreezerDir, []string{frozenBlocks, frozenReceipts, frozenEvents, frozenEpochs}, freezer.DefaultMaxFileSize)
+	if err != nil {
+		s.Log.Crit("Failed to open freezer", "dir", s.cfg.FreezerDir, "err", err)
+	}
+	s.freezer = f
+	if err := s.rollbackFreezer(); err != nil {
+		s.Log.Crit("Failed to rollback freezer", "dir", s.cfg.FreezerDir, "err", err)
+	}
+	s.evm.AttachFreezer(f.Table(frozenReceipts))
+}
+
+// rollbackFreezer discards the items which were appended by a migration step which wasn't committed
+func (s *Store) rollbackFreezer() error {
+	p := s.GetFreezerProgress()
+	if err := s.freezer.Table(frozenBlocks).TruncateHead(uint64(p.Block)); err != nil {
+		return err
+	}
+	if err := s.freezer.Table(frozenReceipts).TruncateHead(uint64(p.Block)); err != nil {
+		return err
+	}
+	epochs := s.freezer.Table(frozenEpochs)
+	if _, head := epochs.Items(); head > uint64(p.Epoch) {
+		// no epochs are committed if the epoch is out of bounds
+		var eventsHead uint64
+		start, err := epochs.Get(uint64(p.Epoch))
+		if err == nil {
+			eventsHead = bigendian.BytesToUint64(start)
+		} else if err != freezer.ErrOutOfBounds {
+			return err
+		}
+		if err := s.freezer.Table(frozenEvents).TruncateHead(eventsHead); err != nil {
+			return err
+		}
+		return epochs.TruncateHead(uint64(p.Epoch))
+	}
+	return nil
+}
+
+// HasFreezer returns true if the finalized history is moved into the freezer.
+func (s *Store) HasFreezer() bool {
+	return s.freezer != nil
+}
+
+// GetFreezerProgress returns the first epoch and block which aren't moved into the freezer.
+func (s *Store) GetFreezerProgress() FreezerProgress {
+	if v := s.cache.FreezerProgress.Load(); v != nil {
+		return v.(FreezerProgress)
+	}
+	p, ok := s.rlp.Get(s.table.FreezerProgress, []byte("p"), &FreezerProgress{}).(*FreezerProgress)
+	if !ok {
+		p = &FreezerProgress{}
+	}
+	s.cache.FreezerProgress.Store(*p)
+	return *p
+}
+
+func (s *Store) setFreezerProgress(p FreezerProgress) {
+	s.rlp.Set(s.table.FreezerProgress, []byte("p"), &p)
+	s.cache.FreezerProgress.Store(p)
+}
+
+func (s *Store) getFrozen(name string, item uint64) []byte {
+	if s.freezer == nil {
+		return nil
+	}
+	blob, err := s.freezer.Table(name).Get(item)
+	if err == freezer.ErrOutOfBounds {
+		return nil
+	}
+	if err != nil {
+		s.Log.Crit("Failed to read freezer", "table", name, "err", err)
+	}
+	return blob
+}
+
+func (s *Store) getFrozenBlock(n idx.Block) *inter.Block {
+	blob := s.getFrozen(frozenBlocks, uint64(n))
+	if len(blob) == 0 {
+		return nil
+	}
+	block := &inter.Block{}
+	if err := rlp.DecodeBytes(blob, block); err != nil {
+		s.Log.Crit("Failed to decode rlp", "err", err, "size", len(blob))
+	}
+	return block
+}
+
+func (s *Store) getFrozenEpochStart(epoch idx.Epoch) (uint64, bool) {
+	b := s.getFrozen(frozenEpochs, uint64(epoch))
+	if b == nil {
+		return 0, false
+	}
+	return bigendian.BytesToUint64(b), true
+}
+
+// frozenEpochEvents returns the range of the events table items of the epoch
+func (s *Store) frozenEpochEvents(epoch idx.Epoch) (start, end uint64) {
+	eventsTail, eventsHead := s.freezer.Table(frozenEvents).Items()
+	start, ok := s.getFrozenEpochStart(epoch)
+	if !ok {
+		if _, epochsHead := s.freezer.Table(frozenEpochs).Items(); uint64(epoch) >= epochsHead {
+			return eventsHead, eventsHead
+		}
+		return eventsTail, eventsTail
+	}
+	end, ok = s.getFrozenEpochStart(epoch + 1)
+	if !ok {
+		end = eventsHead
+	}
+	if start < eventsTail {
+		start = eventsTail
+	}
+	return start, end
+}
+
+// frozenEventsLowerBound returns the first item of the range whose event key isn't less than the key
+func (s *Store) frozenEventsLowerBound(from, to uint64, key []byte) uint64 {
+	for from < to {
+		mid := from + (to-from)/2
+		blob := s.getFrozen(frozenEvents, mid)
+		if len(blob) < eventKeySize || bytes.Compare(blob[:eventKeySize], key) < 0 {
+			from = mid + 1
+		} else {
+			to = mid
+		}
+	}
+	return from
+}
+
+func (s *Store) getFrozenEventRLP(id hash.Event) rlp.RawValue {
+	if s.freezer == nil {
+		return nil
+	}
+	from, to := s.frozenEpochEvents(id.Epoch())
+	i := s.frozenEventsLowerBound(from, to, id.Bytes())
+	if i >= to {
+		return nil
+	}
+	blob := s.getFrozen(frozenEvents, i)
+	if len(blob) < eventKeySize || !bytes.Equal(blob[:eventKeySize], id.Bytes()) {
+		return nil
+	}
+	return blob[eventKeySize:]
+}
+
+func (s *Store) getFrozenEvent(id hash.Event) *inter.EventPayload {
+	blob := s.getFrozenEventRLP(id)
+	if blob == nil {
+		return nil
+	}
+	e := &inter.EventPayload{}
+	if err := rlp.DecodeBytes(blob, e); err != nil {
+		s.Log.Crit("Failed to decode event", "err", err)
+	}
+	return e
+}
+
+// forEachFrozenEvent iterates the frozen events starting from the key, in the order of keys.
+// Returns false if the iteration was stopped by onEvent.
+func (s *Store) forEachFrozenEvent(start []byte, onEvent func(key hash.Event, event rlp.RawValue) bool) bool {
+	if s.freezer == nil {
+		return true
+	}
+	progress := s.GetFreezerProgress()
+	var epoch idx.Epoch
+	if len(start) >= 4 {
+		epoch = idx.BytesToEpoch(start[:4])
+	}
+	if epoch >= progress.Epoch {
+		return true
+	}
+	_, to := s.frozenEpochEvents(progress.Epoch - 1)
+	from, end := s.frozenEpochEvents(epoch)
+	for i := s.frozenEventsLowerBound(from, end, start); i < to; i++ {
+		blob := s.getFrozen(frozenEvents, i)
+		if len(blob) < eventKeySize {
+			// truncated concurrently
+			continue
+		}
+		if !onEvent(hash.BytesToEvent(blob[:eventKeySize]), blob[eventKeySize:]) {
+			return false
+		}
+	}
+	return true
+}
+
+// notFrozenEventsStart returns the first key of the events, which aren't moved into the freezer, not less than the key
+func (s *Store) notFrozenEventsStart(start []byte) []byte {
+	progress := s.GetFreezerProgress().Epoch
+	if progress == 0 || bytes.Compare(start, progress.Bytes()) >= 0 {
+		return start
+	}
+	return progress.Bytes()
+}
+
+// freezeBlocks appends the blocks and their receipts into the freezer.
+// The range has to start from the first block which isn't frozen.
+func (s *Store) freezeBlocks(from, to idx.Block) error {
+	blocks := s.freezer.Table(frozenBlocks)
+	receipts := s.freezer.Table(frozenReceipts)
+	for n := from; n <= to; n++ {
+		block, err := s.table.Blocks.Get(n.Bytes())
+		if err != nil {
+			return err
+		}
+		if err := blocks.Append(uint64(n), block); err != nil {
+			return err
+		}
+		if err := receipts.Append(uint64(n), s.evm.GetRawReceiptsRLPNotFrozen(n)); err != nil {
+			return err
+		}
+	}
+	return s.freezer.Sync()
+}
+
+// delFrozenBlocks deletes the frozen blocks and their receipts from the key-value DB.
+// The genesis block is kept, so it survives pruning of the frozen blocks.
+func (s *Store) delFrozenBlocks(from, to idx.Block) {
+	genesis := s.GetGenesisBlockIndex()
+	for n := from; n <= to; n++ {
+		if genesis != nil && n == *genesis {
+			continue
+		}
+		s.deleteKey(s.table.Blocks, n.Bytes())
+		s.cache.Blocks.Remove(n)
+		s.evm.DelFrozenReceipts(n)
+	}
+	p := s.GetFreezerProgress()
+	p.Block = to + 1
+	s.setFreezerProgress(p)
+}
+
+// freezeEpoch appends the events of the epoch into the freezer.
+// The epoch has to be the first epoch which isn't frozen.
+func (s *Store) freezeEpoch(epoch idx.Epoch) error {
+	events := s.freezer.Table(frozenEvents)
+	_, head := events.Items()
+	if err := s.freezer.Table(frozenEpochs).Append(uint64(epoch), bigendian.Uint64ToBytes(head)); err != nil {
+		return err
+	}
+	it := s.table.Events.NewIterator(epoch.Bytes(), nil)
+	defer it.Release()
+	for it.Next() {
+		item := make([]byte, 0, len(it.Key())+len(it.Value()))
+		item = append(append(item, it.Key()...), it.Value()...)
+		if err := events.Append(head, item); err != nil {
+			return err
+		}
+		head++
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return s.freezer.Sync()
+}
+
+// delFrozenEpoch deletes the frozen events of the epoch from the key-value DB
+func (s *Store) delFrozenEpoch(epoch idx.Epoch) {
+	for _, key := range s.keysWithPrefix(s.table.Events, epoch.Bytes()) {
+		s.DelEvent(hash.BytesToEvent(key))
+	}
+	p := s.GetFreezerProgress()
+	p.Epoch = epoch + 1
+	s.setFreezerProgress(p)
+}
+
+// firstStoredEpoch returns the lowest epoch of the stored events.
+func (s *Store) firstStoredEpoch() idx.Epoch {
+	it := s.table.Events.NewIterator(nil, nil)
+	defer it.Release()
+	if !it.Next() {
+		return 0
+	}
+	return idx.BytesToEpoch(it.Key()[:4])
+}
+
+// pruneFrozenBlocks deletes the frozen blocks and receipts below the block
+func (s *Store) pruneFrozenBlocks(tail idx.Block) {
+	if s.freezer == nil {
+		return
+	}
+	for _, name := range []string{frozenBlocks, frozenReceipts} {
+		if err := s.freezer.Table(name).TruncateTail(uint64(tail)); err != nil {
+			s.Log.Crit("Failed to truncate freezer", "table", name, "err", err)
+		}
+	}
+}
+
+// pruneFrozenEpoch deletes the frozen events of the epoch and the older epochs
+func (s *Store) pruneFrozenEpoch(epoch idx.Epoch) {
+	if s.freezer == nil {
+		return
+	}
+	epochs := s.freezer.Table(frozenEpochs)
+	if _, head := epochs.Items(); uint64(epoch) >= head {
+		return
+	}
+	_, end := s.frozenEpochEvents(epoch)
+	if err := s.freezer.Table(frozenEvents).TruncateTail(end); err != nil {
+		s.Log.Crit("Failed to truncate freezer", "table", frozenEvents, "err", err)
+	}
+	if err := epochs.TruncateTail(uint64(epoch) + 1); err != nil {
+		s.Log.Crit("Failed to truncate freezer", "table", frozenEpochs, "err", err)
+	}
+}
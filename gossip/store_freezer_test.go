@@ -0,0 +1,156 @@
+package gossip
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/flushable"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+)
+
+func TestStoreFreezer(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "freezer")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cfg := LiteStoreConfig()
+	cfg.FreezerDir = dir
+	store := NewStore(flushable.NewSyncedPool(memorydb.NewProducer(""), []byte{0}), cfg)
+	defer store.Close()
+	require.True(store.HasFreezer())
+
+	const blocks = 10
+	for n := idx.Block(1); n <= blocks; n++ {
+		store.SetBlock(n, &inter.Block{
+			Time:    inter.Timestamp(n),
+			Atropos: hash.FakeEvent(),
+		})
+		store.evm.SetRawReceipts(n, []*types.ReceiptForStorage{{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(n),
+			Logs:              []*types.Log{},
+		}})
+	}
+	var events hash.Events
+	for epoch := idx.Epoch(1); epoch <= 3; epoch++ {
+		for lamport := idx.Lamport(1); lamport <= 4; lamport++ {
+			me := &inter.MutableEventPayload{}
+			me.SetVersion(1)
+			me.SetEpoch(epoch)
+			me.SetLamport(lamport)
+			me.SetCreator(idx.ValidatorID(lamport))
+			me.SetPayloadHash(inter.CalcPayloadHash(me))
+			e := me.Build()
+			store.SetEvent(e)
+			events = append(events, e.ID())
+		}
+	}
+	encode := func(b *inter.Block) []byte {
+		raw, err := rlp.EncodeToBytes(b)
+		require.NoError(err)
+		return raw
+	}
+	expectedBlocks := make(map[idx.Block][]byte)
+	expectedReceipts := make(map[idx.Block]rlp.RawValue)
+	for n := idx.Block(1); n <= blocks; n++ {
+		expectedBlocks[n] = encode(store.GetBlock(n))
+		expectedReceipts[n] = store.evm.GetRawReceiptsRLP(n)
+		require.NotNil(expectedReceipts[n])
+	}
+
+	require.NoError(store.freezeBlocks(1, 6))
+	store.delFrozenBlocks(1, 6)
+	for epoch := idx.Epoch(1); epoch <= 2; epoch++ {
+		require.NoError(store.freezeEpoch(epoch))
+		store.delFrozenEpoch(epoch)
+	}
+	require.Equal(FreezerProgress{Epoch: 3, Block: 7}, store.GetFreezerProgress())
+
+	check := func(blocksTail idx.Block, eventsTail idx.Epoch) {
+		for n := idx.Block(1); n <= blocks; n++ {
+			if n < blocksTail {
+				require.Nil(store.GetBlock(n), n)
+				require.Nil(store.evm.GetRawReceiptsRLP(n), n)
+				continue
+			}
+			require.True(store.HasBlock(n), n)
+			require.Equal(expectedBlocks[n], encode(store.GetBlock(n)), n)
+			require.Equal(expectedReceipts[n], store.evm.GetRawReceiptsRLP(n), n)
+		}
+		var expected hash.Events
+		for _, id := range events {
+			if id.Epoch() < eventsTail {
+				require.False(store.HasEvent(id))
+				require.Nil(store.GetEventPayload(id))
+				continue
+			}
+			expected = append(expected, id)
+			require.True(store.HasEvent(id))
+			require.Equal(id, store.GetEventPayload(id).ID())
+			require.Equal(id, store.GetEvent(id).ID())
+			require.NotNil(store.GetEventPayloadRLP(id))
+			require.Equal(hash.Events{id}, store.FindEventHashes(id.Epoch(), id.Lamport(), id.Bytes()[8:12]))
+		}
+		// the frozen events are iterated before the not frozen ones, without gaps and duplicates
+		var got hash.Events
+		store.ForEachEventRLP(nil, func(key hash.Event, _ rlp.RawValue) bool {
+			got = append(got, key)
+			return true
+		})
+		require.Equal(len(expected), len(got))
+		for i := 1; i < len(got); i++ {
+			require.Less(string(got[i-1].Bytes()), string(got[i].Bytes()))
+		}
+		for epoch := idx.Epoch(1); epoch <= 3; epoch++ {
+			num := 0
+			store.ForEachEpochEvent(epoch, func(e *inter.EventPayload) bool {
+				require.Equal(epoch, e.Epoch())
+				num++
+				return true
+			})
+			if epoch < eventsTail {
+				require.Equal(0, num)
+			} else {
+				require.Equal(4, num)
+			}
+		}
+	}
+	check(1, 1)
+	num := 0
+	store.ForEachBlock(func(n idx.Block, b *inter.Block) {
+		require.Equal(expectedBlocks[n], encode(b))
+		num++
+	})
+	require.Equal(blocks, num)
+
+	// the items of a not committed migration step are discarded
+	require.NoError(store.freezeBlocks(7, 8))
+	require.NoError(store.freezeEpoch(3))
+	require.NoError(store.rollbackFreezer())
+	require.Equal(FreezerProgress{Epoch: 3, Block: 7}, store.GetFreezerProgress())
+	_, head := store.freezer.Table(frozenBlocks).Items()
+	require.Equal(uint64(7), head)
+	_, head = store.freezer.Table(frozenEpochs).Items()
+	require.Equal(uint64(3), head)
+	_, head = store.freezer.Table(frozenEvents).Items()
+	require.Equal(uint64(8), head)
+	check(1, 1)
+
+	// the frozen history is pruned
+	store.cache.Blocks.Purge()
+	store.cache.Events.Purge()
+	store.cache.EventsHeaders.Purge()
+	store.pruneFrozenBlocks(4)
+	store.pruneFrozenEpoch(1)
+	check(4, 2)
+}
@@ -0,0 +1,124 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// HistoryStart is the earliest epoch and block whose history isn't pruned.
+type HistoryStart struct {
+	Epoch idx.Epoch
+	Block idx.Block
+}
+
+// GetHistoryStart returns the earliest epoch and block whose history isn't pruned.
+func (s *Store) GetHistoryStart() HistoryStart {
+	if v := s.cache.HistoryStart.Load(); v != nil {
+		return v.(HistoryStart)
+	}
+	hs, ok := s.rlp.Get(s.table.HistoryStart, []byte("s"), &HistoryStart{}).(*HistoryStart)
+	if !ok {
+		hs = &HistoryStart{}
+	}
+	s.cache.HistoryStart.Store(*hs)
+	return *hs
+}
+
+// SetHistoryStart stores the earliest epoch and block whose history isn't pruned.
+func (s *Store) SetHistoryStart(hs HistoryStart) {
+	s.rlp.Set(s.table.HistoryStart, []byte("s"), &hs)
+	s.cache.HistoryStart.Store(hs)
+}
+
+// PruneBlocksHistory deletes blocks of the range with their transactions, receipts, logs and LLR block records,
+// including the frozen ones.
+// Events of the blocks have to be still present. The genesis block and the fake block 0 are kept.
+func (s *Store) PruneBlocksHistory(from, to idx.Block, signer types.Signer) {
+	genesis := s.GetGenesisBlockIndex()
+	for n := from; n <= to; n++ {
+		if n == 0 || genesis != nil && n == *genesis {
+			continue
+		}
+		block := s.GetBlock(n)
+		if block == nil {
+			continue
+		}
+		txs := s.GetBlockTxs(n, block)
+		s.evm.PruneBlock(n, common.Hash(block.Atropos), txs, signer)
+
+		s.deleteKey(s.table.Blocks, n.Bytes())
+		s.cache.Blocks.Remove(n)
+		s.deleteKey(s.table.BlockHashes, block.Atropos.Bytes())
+		s.cache.BlockHashes.Remove(block.Atropos)
+		s.deleteKey(s.table.LlrBlockResults, n.Bytes())
+		s.deleteKeys(s.table.LlrBlockVotesIndex, n.Bytes())
+	}
+	s.evm.PruneLogsAndTraces(from, to)
+	s.pruneFrozenBlocks(to + 1)
+}
+
+// PruneEpochHistory deletes events, the history block/epoch state and LLR records of the epoch.
+func (s *Store) PruneEpochHistory(epoch idx.Epoch) {
+	for _, key := range s.keysWithPrefix(s.table.Events, epoch.Bytes()) {
+		s.DelEvent(hash.BytesToEvent(key))
+	}
+	s.deleteKey(s.table.BlockEpochStateHistory, epoch.Bytes())
+	s.cache.BlockEpochStateHistory.Remove(epoch)
+	s.deleteKey(s.table.LlrEpochResults, epoch.Bytes())
+	s.deleteKeys(s.table.LlrEpochVotes, epoch.Bytes())
+	s.deleteKeys(s.table.LlrEpochVoteIndex, epoch.Bytes())
+	s.deleteKeys(s.table.LlrBlockVotes, epoch.Bytes())
+	s.pruneFrozenEpoch(epoch)
+}
+
+// firstStoredBlock returns the lowest index of the stored blocks.
+func (s *Store) firstStoredBlock() idx.Block {
+	it := s.table.Blocks.NewIterator(nil, nil)
+	defer it.Release()
+	if !it.Next() {
+		return 0
+	}
+	return idx.BytesToBlock(it.Key())
+}
+
+// firstHistoryEpoch returns the lowest epoch of the stored history block/epoch states or events.
+func (s *Store) firstHistoryEpoch() idx.Epoch {
+	first := s.firstStoredEpoch()
+	it := s.table.BlockEpochStateHistory.NewIterator(nil, nil)
+	defer it.Release()
+	if it.Next() {
+		if epoch := idx.BytesToEpoch(it.Key()); first == 0 || epoch < first {
+			first = epoch
+		}
+	}
+	return first
+}
+
+func (s *Store) deleteKey(table kvdb.Store, key []byte) {
+	if err := table.Delete(key); err != nil {
+		s.Log.Crit("Failed to delete key", "err", err)
+	}
+}
+
+// deleteKeys deletes all the keys with the prefix.
+func (s *Store) deleteKeys(table kvdb.Store, prefix []byte) {
+	for _, key := range s.keysWithPrefix(table, prefix) {
+		s.deleteKey(table, key)
+	}
+}
+
+func (s *Store) keysWithPrefix(table kvdb.Store, prefix []byte) [][]byte {
+	var keys [][]byte
+	it := table.NewIterator(prefix, nil)
+	defer it.Release()
+	for it.Next() {
+		keys = append(keys, common.CopyBytes(it.Key()))
+	}
+	if err := it.Error(); err != nil {
+		s.Log.Crit("Failed to iterate keys", "err", err)
+	}
+	return keys
+}
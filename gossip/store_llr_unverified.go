@@ -0,0 +1,60 @@
+package gossip
+
+import (
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+)
+
+const (
+	unverifiedBlockPrefix = 'b'
+	unverifiedEpochPrefix = 'e'
+)
+
+func (s *Store) setUnverifiedRecord(prefix byte, key []byte, unverified bool) {
+	var err error
+	if unverified {
+		err = s.table.LlrUnverifiedRecords.Put(append([]byte{prefix}, key...), []byte{})
+	} else {
+		err = s.table.LlrUnverifiedRecords.Delete(append([]byte{prefix}, key...))
+	}
+	if err != nil {
+		s.Log.Crit("Failed to put key-value", "err", err)
+	}
+}
+
+func (s *Store) isUnverifiedRecord(prefix byte, key []byte) bool {
+	has, err := s.table.LlrUnverifiedRecords.Has(append([]byte{prefix}, key...))
+	if err != nil {
+		s.Log.Crit("Failed to get key-value", "err", err)
+	}
+	return has
+}
+
+// SetUnverifiedBlockRecord marks the block record as imported without the verification by the LLR votes
+func (s *Store) SetUnverifiedBlockRecord(n idx.Block, unverified bool) {
+	s.setUnverifiedRecord(unverifiedBlockPrefix, n.Bytes(), unverified)
+}
+
+// IsUnverifiedBlockRecord returns true if the block record was imported without the verification by the LLR votes
+func (s *Store) IsUnverifiedBlockRecord(n idx.Block) bool {
+	return s.isUnverifiedRecord(unverifiedBlockPrefix, n.Bytes())
+}
+
+// HasVerifiedBlock returns true if the block is stored and isn't an unverified imported record
+func (s *Store) HasVerifiedBlock(n idx.Block) bool {
+	return s.HasBlock(n) && !s.IsUnverifiedBlockRecord(n)
+}
+
+// SetUnverifiedEpochRecord marks the epoch record as imported without the verification by the LLR votes
+func (s *Store) SetUnverifiedEpochRecord(epoch idx.Epoch, unverified bool) {
+	s.setUnverifiedRecord(unverifiedEpochPrefix, epoch.Bytes(), unverified)
+}
+
+// IsUnverifiedEpochRecord returns true if the epoch record was imported without the verification by the LLR votes
+func (s *Store) IsUnverifiedEpochRecord(epoch idx.Epoch) bool {
+	return s.isUnverifiedRecord(unverifiedEpochPrefix, epoch.Bytes())
+}
+
+// HasVerifiedHistoryBlockEpochState returns true if the epoch record is stored and isn't an unverified imported record
+func (s *Store) HasVerifiedHistoryBlockEpochState(epoch idx.Epoch) bool {
+	return s.HasHistoryBlockEpochState(epoch) && !s.IsUnverifiedEpochRecord(epoch)
+}
@@ -0,0 +1,39 @@
+package gossip
+
+import (
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+)
+
+func TestUnverifiedRecordsAreNotFilled(t *testing.T) {
+	require := require.New(t)
+	store := NewMemStore()
+
+	for n := idx.Block(1); n <= 3; n++ {
+		store.SetBlock(n, &inter.Block{Atropos: hash.FakeEvent()})
+	}
+	store.SetUnverifiedBlockRecord(2, true)
+	store.setLlrState(LlrState{LowestBlockToFill: 1})
+	require.True(store.HasVerifiedBlock(1))
+	require.False(store.HasVerifiedBlock(2))
+	require.True(store.HasBlock(2))
+
+	// the unverified block stops the lowest block to fill
+	updateLowestBlockToFill(1, store)
+	require.Equal(idx.Block(2), store.GetLlrState().LowestBlockToFill)
+
+	// the block is replaced by the verified record
+	store.SetUnverifiedBlockRecord(2, false)
+	updateLowestBlockToFill(2, store)
+	require.Equal(idx.Block(4), store.GetLlrState().LowestBlockToFill)
+
+	require.False(store.IsUnverifiedEpochRecord(1))
+	store.SetUnverifiedEpochRecord(1, true)
+	require.True(store.IsUnverifiedEpochRecord(1))
+	require.False(store.IsUnverifiedBlockRecord(1))
+}
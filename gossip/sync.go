@@ -39,6 +39,10 @@ func (ss *syncStatus) Is(s ...syncStage) bool {
 	return false
 }
 
+func (ss *syncStatus) Stage() syncStage {
+	return syncStage(atomic.LoadUint32(&ss.stage))
+}
+
 func (ss *syncStatus) Set(s syncStage) {
 	atomic.StoreUint32(&ss.stage, uint32(s))
 }
@@ -0,0 +1,238 @@
+package traces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/evmstore"
+	"github.com/Fantom-foundation/go-opera/txtrace"
+)
+
+// Backend provides the chain data required by the trace API.
+type Backend interface {
+	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*evmcore.EvmHeader, error)
+	HeaderByHash(ctx context.Context, blockHash common.Hash) (*evmcore.EvmHeader, error)
+	GetTxPosition(txid common.Hash) *evmstore.TxPosition
+
+	EvmTraceIndex() *txtrace.Index
+}
+
+type Config struct {
+	// Block range limit for traces search by addresses (indexed).
+	IndexedFilterBlockRangeLimit idx.Block
+	// Block range limit for traces search without addresses (full scan).
+	UnindexedFilterBlockRangeLimit idx.Block
+	// Max number of traces returned by a single filter request.
+	MaxFilterResults int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		IndexedFilterBlockRangeLimit:   100000,
+		UnindexedFilterBlockRangeLimit: 1000,
+		MaxFilterResults:               10000,
+	}
+}
+
+// FilterArgs represents the arguments for trace_filter.
+type FilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	After       *uint            `json:"after"`
+	Count       *uint            `json:"count"`
+}
+
+// PublicTraceAPI provides Parity-style access to the recorded traces of the transactions.
+type PublicTraceAPI struct {
+	b      Backend
+	config Config
+}
+
+// NewPublicTraceAPI creates a new trace API.
+func NewPublicTraceAPI(b Backend, cfg Config) *PublicTraceAPI {
+	return &PublicTraceAPI{
+		b:      b,
+		config: cfg,
+	}
+}
+
+// Block returns the traces of all the transactions of the block.
+func (api *PublicTraceAPI) Block(ctx context.Context, number rpc.BlockNumber) ([]txtrace.ActionTrace, error) {
+	header, err := api.b.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	block := idx.Block(header.Number.Uint64())
+
+	res := make([]txtrace.ActionTrace, 0, 16)
+	err = api.b.EvmTraceIndex().ForEachTxInBlocks(ctx, block, block, func(_ txtrace.TxID, traces []txtrace.ActionTrace) bool {
+		res = append(res, traces...)
+		return true
+	})
+	return res, err
+}
+
+// Transaction returns the traces of the transaction.
+func (api *PublicTraceAPI) Transaction(ctx context.Context, hash common.Hash) ([]txtrace.ActionTrace, error) {
+	position := api.b.GetTxPosition(hash)
+	if position == nil {
+		return nil, nil
+	}
+	return api.b.EvmTraceIndex().GetTxTraces(txtrace.TxID{
+		Block:    position.Block,
+		Position: position.BlockOffset,
+	})
+}
+
+// Get returns the trace of the transaction at the given trace address.
+func (api *PublicTraceAPI) Get(ctx context.Context, hash common.Hash, traceAddress []hexutil.Uint64) (*txtrace.ActionTrace, error) {
+	traces, err := api.Transaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	for i := range traces {
+		if equalTraceAddress(traces[i].TraceAddress, traceAddress) {
+			return &traces[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Filter returns the traces matching the given block range and sender/recipient addresses.
+// Traces are matched by both addresses lists, if they are not empty.
+func (api *PublicTraceAPI) Filter(ctx context.Context, args FilterArgs) ([]txtrace.ActionTrace, error) {
+	from, err := api.blockNumber(ctx, args.FromBlock, rpc.EarliestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	to, err := api.blockNumber(ctx, args.ToBlock, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, errors.New("invalid block range")
+	}
+
+	indexed := len(args.FromAddress) != 0 || len(args.ToAddress) != 0
+	if indexed && to-from > api.config.IndexedFilterBlockRangeLimit {
+		return nil, fmt.Errorf("too wide blocks range, the limit is %d", api.config.IndexedFilterBlockRangeLimit)
+	}
+	if !indexed && to-from > api.config.UnindexedFilterBlockRangeLimit {
+		return nil, fmt.Errorf("too wide blocks range, the limit is %d", api.config.UnindexedFilterBlockRangeLimit)
+	}
+
+	var (
+		after = uint(0)
+		count = uint(api.config.MaxFilterResults)
+	)
+	if args.After != nil {
+		after = *args.After
+	}
+	if args.Count != nil && *args.Count < count {
+		count = *args.Count
+	}
+
+	fromSet := addressSet(args.FromAddress)
+	toSet := addressSet(args.ToAddress)
+	res := make([]txtrace.ActionTrace, 0, 16)
+	skipped := uint(0)
+	// onTraces returns false when enough traces are collected
+	onTraces := func(traces []txtrace.ActionTrace) bool {
+		for _, t := range traces {
+			if fromSet != nil {
+				if _, ok := fromSet[t.Sender()]; !ok {
+					continue
+				}
+			}
+			if toSet != nil {
+				if _, ok := toSet[t.Recipient()]; !ok {
+					continue
+				}
+			}
+			if skipped < after {
+				skipped++
+				continue
+			}
+			if uint(len(res)) >= count {
+				return false
+			}
+			res = append(res, t)
+		}
+		return uint(len(res)) < count
+	}
+
+	index := api.b.EvmTraceIndex()
+	if !indexed {
+		err = index.ForEachTxInBlocks(ctx, from, to, func(_ txtrace.TxID, traces []txtrace.ActionTrace) bool {
+			return onTraces(traces)
+		})
+		return res, err
+	}
+
+	// the index is read only until enough traces are collected
+	var tracesErr error
+	err = index.FindTxs(ctx, from, to, args.FromAddress, args.ToAddress, func(id txtrace.TxID) bool {
+		var traces []txtrace.ActionTrace
+		traces, tracesErr = index.GetTxTraces(id)
+		return tracesErr == nil && onTraces(traces)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if tracesErr != nil {
+		return nil, tracesErr
+	}
+	return res, nil
+}
+
+func (api *PublicTraceAPI) blockNumber(ctx context.Context, number *rpc.BlockNumber, def rpc.BlockNumber) (idx.Block, error) {
+	if number == nil {
+		number = &def
+	}
+	if *number == rpc.EarliestBlockNumber {
+		return 0, nil
+	}
+	header, err := api.b.HeaderByNumber(ctx, *number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block #%d not found", *number)
+	}
+	return idx.Block(header.Number.Uint64()), nil
+}
+
+func addressSet(addrs []common.Address) map[common.Address]struct{} {
+	if len(addrs) == 0 {
+		return nil
+	}
+	set := make(map[common.Address]struct{}, len(addrs))
+	for _, addr := range addrs {
+		set[addr] = struct{}{}
+	}
+	return set
+}
+
+func equalTraceAddress(a []uint32, b []hexutil.Uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if uint64(a[i]) != uint64(b[i]) {
+			return false
+		}
+	}
+	return true
+}
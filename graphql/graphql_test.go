@@ -0,0 +1,445 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/evmcore"
+	"github.com/Fantom-foundation/go-opera/gossip/filters"
+)
+
+// TestSchemaResolvers checks that every schema field has a matching resolver.
+func TestSchemaResolvers(t *testing.T) {
+	_, err := newSchema(nil, filters.DefaultConfig())
+	require.NoError(t, err)
+}
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+	logAddr    = common.Address{0x10}
+	testTopic  = common.Hash{0x20}
+)
+
+// testBackend serves the blocks, the other methods panic
+type testBackend struct {
+	Backend
+
+	db       ethdb.Database
+	config   *params.ChainConfig
+	blocks   []*evmcore.EvmBlock
+	receipts []types.Receipts
+}
+
+func blockHash(n uint64) common.Hash {
+	return common.Hash{0xb0, byte(n)}
+}
+
+// newTestBackend creates the empty genesis block and a block for each of the txs lists.
+// A transaction with a non-empty data emits a log, a transaction without the data fails.
+func newTestBackend(t *testing.T, txs ...[]*types.DynamicFeeTx) *testBackend {
+	b := &testBackend{
+		db:     rawdb.NewMemoryDatabase(),
+		config: params.AllEthashProtocolChanges,
+	}
+	signer := types.LatestSignerForChainID(b.config.ChainID)
+	nonce := uint64(0)
+	for n := uint64(0); n <= uint64(len(txs)); n++ {
+		block := evmcore.NewEvmBlock(&evmcore.EvmHeader{
+			Number:  new(big.Int).SetUint64(n),
+			Hash:    blockHash(n),
+			BaseFee: big.NewInt(1),
+		}, nil)
+		if n > 0 {
+			block.ParentHash = blockHash(n - 1)
+		}
+		receipts := types.Receipts{}
+		logIndex := uint(0)
+		if n > 0 {
+			for i, inner := range txs[n-1] {
+				inner.ChainID = b.config.ChainID
+				inner.Nonce = nonce
+				nonce++
+				tx, err := types.SignNewTx(testKey, signer, inner)
+				require.NoError(t, err)
+				block.Transactions = append(block.Transactions, tx)
+				receipt := &types.Receipt{
+					Status:           types.ReceiptStatusFailed,
+					TxHash:           tx.Hash(),
+					GasUsed:          21000 + uint64(i),
+					BlockHash:        block.Hash,
+					BlockNumber:      block.Number,
+					TransactionIndex: uint(i),
+					Logs:             []*types.Log{},
+				}
+				if len(inner.Data) != 0 {
+					receipt.Status = types.ReceiptStatusSuccessful
+					receipt.Logs = append(receipt.Logs, &types.Log{
+						Address:     logAddr,
+						Topics:      []common.Hash{testTopic},
+						Data:        inner.Data,
+						BlockNumber: n,
+						TxHash:      tx.Hash(),
+						TxIndex:     uint(i),
+						BlockHash:   block.Hash,
+						Index:       logIndex,
+					})
+					logIndex++
+				}
+				block.GasUsed += receipt.GasUsed
+				receipt.CumulativeGasUsed = block.GasUsed
+				receipts = append(receipts, receipt)
+			}
+		}
+		b.blocks = append(b.blocks, block)
+		b.receipts = append(b.receipts, receipts)
+	}
+	return b
+}
+
+func (b *testBackend) ChainDb() ethdb.Database {
+	return b.db
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return b.config
+}
+
+func (b *testBackend) CurrentBlock() *evmcore.EvmBlock {
+	return b.blocks[len(b.blocks)-1]
+}
+
+func (b *testBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmBlock, error) {
+	if number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber {
+		return b.CurrentBlock(), nil
+	}
+	if number < 0 || int(number) >= len(b.blocks) {
+		return nil, nil
+	}
+	return b.blocks[number], nil
+}
+
+func (b *testBackend) BlockByHash(ctx context.Context, hash common.Hash) (*evmcore.EvmBlock, error) {
+	for _, block := range b.blocks {
+		if block.Hash == hash {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*evmcore.EvmHeader, error) {
+	block, err := b.BlockByNumber(ctx, number)
+	return block.Header(), err
+}
+
+func (b *testBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*evmcore.EvmHeader, error) {
+	block, err := b.BlockByHash(ctx, hash)
+	return block.Header(), err
+}
+
+func (b *testBackend) GetReceiptsByNumber(ctx context.Context, number rpc.BlockNumber) (types.Receipts, error) {
+	block, err := b.BlockByNumber(ctx, number)
+	if block == nil {
+		return nil, err
+	}
+	return b.receipts[block.NumberU64()], nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	block, err := b.BlockByHash(ctx, hash)
+	if block == nil {
+		return nil, err
+	}
+	return b.receipts[block.NumberU64()], nil
+}
+
+func (b *testBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*types.Log, error) {
+	receipts, err := b.GetReceipts(ctx, hash)
+	logs := make([][]*types.Log, len(receipts))
+	for i, r := range receipts {
+		logs[i] = r.Logs
+	}
+	return logs, err
+}
+
+func (b *testBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*types.Transaction, uint64, uint64, error) {
+	for n, block := range b.blocks {
+		for i, tx := range block.Transactions {
+			if tx.Hash() == txHash {
+				return tx, uint64(n), uint64(i), nil
+			}
+		}
+	}
+	return nil, 0, 0, nil
+}
+
+func (b *testBackend) GetPoolTransaction(txHash common.Hash) *types.Transaction {
+	return nil
+}
+
+// query executes the GraphQL query and decodes the response data into res
+func query(t *testing.T, b Backend, q string, res interface{}) error {
+	s, err := newSchema(b, filters.DefaultConfig())
+	require.NoError(t, err)
+	response := s.Exec(context.Background(), q, "", nil)
+	if len(response.Errors) != 0 {
+		return response.Errors[0]
+	}
+	require.NoError(t, json.Unmarshal(response.Data, res))
+	return nil
+}
+
+func TestBlockResolver(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t, []*types.DynamicFeeTx{
+		{To: &common.Address{1}, Gas: 50000, GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(2), Data: []byte{1}},
+		{To: &common.Address{2}, Gas: 50000, GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(2)},
+	})
+	tx0, tx1 := b.blocks[1].Transactions[0], b.blocks[1].Transactions[1]
+
+	type block struct {
+		Number           int64
+		Hash             common.Hash
+		GasUsed          int64
+		Parent           *struct{ Number int64 }
+		TransactionCount int32
+		Transactions     []struct {
+			Hash              common.Hash
+			Index             int32
+			Status            int64
+			GasUsed           int64
+			EffectiveGasPrice string
+			From              struct{ Address common.Address }
+			To                struct{ Address common.Address }
+		}
+	}
+	const fields = `{ number hash gasUsed parent { number } transactionCount
+		transactions { hash index status gasUsed effectiveGasPrice from { address } to { address } } }`
+	var res struct {
+		ByNumber *block
+		ByHash   *block
+		Latest   *block
+		Genesis  *block
+		Unknown  *block
+	}
+	require.NoError(query(t, b, `{
+		byNumber: block(number: 1) `+fields+`
+		byHash: block(hash: "`+blockHash(1).Hex()+`") `+fields+`
+		latest: block `+fields+`
+		genesis: block(number: 0) `+fields+`
+		unknown: block(number: 2) `+fields+`
+	}`, &res))
+
+	require.NotNil(res.ByNumber)
+	require.Equal(res.ByNumber, res.ByHash)
+	require.Equal(res.ByNumber, res.Latest)
+	require.Equal(int64(1), res.ByNumber.Number)
+	require.Equal(blockHash(1), res.ByNumber.Hash)
+	require.Equal(int64(21000+21001), res.ByNumber.GasUsed)
+	require.Equal(int64(0), res.ByNumber.Parent.Number)
+	require.Equal(int32(2), res.ByNumber.TransactionCount)
+	require.Len(res.ByNumber.Transactions, 2)
+	txs := res.ByNumber.Transactions
+	require.Equal(tx0.Hash(), txs[0].Hash)
+	require.Equal(int32(0), txs[0].Index)
+	require.Equal(int64(types.ReceiptStatusSuccessful), txs[0].Status)
+	require.Equal(int64(21000), txs[0].GasUsed)
+	// base fee plus the tip
+	require.Equal("0x3", txs[0].EffectiveGasPrice)
+	require.Equal(testAddr, txs[0].From.Address)
+	require.Equal(common.Address{1}, txs[0].To.Address)
+	require.Equal(tx1.Hash(), txs[1].Hash)
+	require.Equal(int32(1), txs[1].Index)
+	require.Equal(int64(types.ReceiptStatusFailed), txs[1].Status)
+
+	require.NotNil(res.Genesis)
+	require.Nil(res.Genesis.Parent)
+	require.Zero(res.Genesis.TransactionCount)
+	require.Empty(res.Genesis.Transactions)
+	require.Nil(res.Unknown)
+}
+
+func TestTransactionResolver(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t, []*types.DynamicFeeTx{
+		{To: &common.Address{1}, Gas: 50000, GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(2)},
+		{To: &common.Address{2}, Gas: 60000, GasFeeCap: big.NewInt(10), GasTipCap: big.NewInt(2), Data: []byte{1, 2}},
+	})
+	tx := b.blocks[1].Transactions[1]
+
+	var res struct {
+		Transaction *struct {
+			Hash      common.Hash
+			Nonce     string
+			Gas       string
+			InputData string
+			Index     int32
+			Block     struct{ Number int64 }
+			Logs      []struct {
+				Index       int32
+				Account     struct{ Address common.Address }
+				Topics      []common.Hash
+				Data        string
+				Transaction struct{ Hash common.Hash }
+			}
+		}
+		Unknown *struct{ Hash common.Hash }
+	}
+	require.NoError(query(t, b, `{
+		transaction(hash: "`+tx.Hash().Hex()+`") {
+			hash nonce gas inputData index block { number }
+			logs { index account { address } topics data transaction { hash } }
+		}
+		unknown: transaction(hash: "`+common.Hash{1}.Hex()+`") { hash }
+	}`, &res))
+
+	require.NotNil(res.Transaction)
+	require.Equal(tx.Hash(), res.Transaction.Hash)
+	require.Equal("0x1", res.Transaction.Nonce)
+	require.Equal("0xea60", res.Transaction.Gas)
+	require.Equal("0x0102", res.Transaction.InputData)
+	require.Equal(int32(1), res.Transaction.Index)
+	require.Equal(int64(1), res.Transaction.Block.Number)
+	require.Len(res.Transaction.Logs, 1)
+	l := res.Transaction.Logs[0]
+	require.Equal(int32(0), l.Index)
+	require.Equal(logAddr, l.Account.Address)
+	require.Equal([]common.Hash{testTopic}, l.Topics)
+	require.Equal("0x0102", l.Data)
+	require.Equal(tx.Hash(), l.Transaction.Hash)
+	require.Nil(res.Unknown)
+}
+
+func TestLogsResolver(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t,
+		[]*types.DynamicFeeTx{
+			{To: &common.Address{1}, Gas: 50000, GasFeeCap: big.NewInt(10), Data: []byte{1}},
+			{To: &common.Address{1}, Gas: 50000, GasFeeCap: big.NewInt(10)},
+			{To: &common.Address{1}, Gas: 50000, GasFeeCap: big.NewInt(10), Data: []byte{2}},
+		},
+		[]*types.DynamicFeeTx{},
+		[]*types.DynamicFeeTx{
+			{To: &common.Address{1}, Gas: 50000, GasFeeCap: big.NewInt(10), Data: []byte{3}},
+		},
+	)
+
+	type log struct {
+		Index       int32
+		Data        string
+		Transaction struct {
+			Hash  common.Hash
+			Block struct{ Number int64 }
+		}
+	}
+	const fields = `{ index data transaction { hash block { number } } }`
+	var res struct {
+		Range      []log
+		Latest     []log
+		Block      struct{ Logs []log }
+		Filtered   struct{ Logs []log }
+		EmptyBlock struct{ Logs []log }
+	}
+	require.NoError(query(t, b, `{
+		range: logs(filter: { fromBlock: 1, toBlock: 2 }) `+fields+`
+		latest: logs(filter: {}) `+fields+`
+		block: block(number: 1) { logs(filter: {}) `+fields+` }
+		filtered: block(number: 1) { logs(filter: { addresses: ["`+common.Address{2}.Hex()+`"] }) `+fields+` }
+		emptyBlock: block(number: 2) { logs(filter: {}) `+fields+` }
+	}`, &res))
+
+	require.Len(res.Range, 2)
+	require.Equal("0x01", res.Range[0].Data)
+	require.Equal(b.blocks[1].Transactions[0].Hash(), res.Range[0].Transaction.Hash)
+	require.Equal(int64(1), res.Range[0].Transaction.Block.Number)
+	require.Equal(int32(1), res.Range[1].Index)
+	require.Equal("0x02", res.Range[1].Data)
+	require.Equal(b.blocks[1].Transactions[2].Hash(), res.Range[1].Transaction.Hash)
+
+	require.Len(res.Latest, 1)
+	require.Equal("0x03", res.Latest[0].Data)
+	require.Equal(int64(3), res.Latest[0].Transaction.Block.Number)
+
+	require.Equal(res.Range, res.Block.Logs)
+	require.Empty(res.Filtered.Logs)
+	require.Empty(res.EmptyBlock.Logs)
+
+	var bad struct{ Logs []log }
+	require.Error(query(t, b, `{ logs(filter: { fromBlock: 1, toBlock: 100000 }) `+fields+` }`, &bad))
+}
+
+func TestBlocksPagination(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t, []*types.DynamicFeeTx{}, []*types.DynamicFeeTx{}, []*types.DynamicFeeTx{})
+
+	numbers := func(blocks []struct{ Number int64 }) []int64 {
+		res := make([]int64, len(blocks))
+		for i, b := range blocks {
+			res[i] = b.Number
+		}
+		return res
+	}
+	var res struct {
+		Page     []struct{ Number int64 }
+		ToLatest []struct{ Number int64 }
+		All      []struct{ Number int64 }
+		Reversed []struct{ Number int64 }
+	}
+	require.NoError(query(t, b, `{
+		page: blocks(from: 1, to: 2) { number }
+		toLatest: blocks(from: 2) { number }
+		all: blocks { number }
+		reversed: blocks(from: 2, to: 1) { number }
+	}`, &res))
+	require.Equal([]int64{1, 2}, numbers(res.Page))
+	require.Equal([]int64{2, 3}, numbers(res.ToLatest))
+	require.Equal([]int64{0, 1, 2, 3}, numbers(res.All))
+	require.Empty(res.Reversed)
+
+	// blocks past the head aren't resolved
+	var beyond struct{ Blocks []struct{ Number int64 } }
+	require.Error(query(t, b, `{ blocks(from: 3, to: 4) { number } }`, &beyond))
+}
+
+func TestQueryLimits(t *testing.T) {
+	require := require.New(t)
+
+	b := newTestBackend(t, []*types.DynamicFeeTx{}, []*types.DynamicFeeTx{})
+
+	// a query of block parents with the given fields depth
+	nested := func(depth int) string {
+		q := "number"
+		for i := 2; i < depth; i++ {
+			q = "parent { " + q + " }"
+		}
+		return "{ block { " + q + " } }"
+	}
+	var res interface{}
+	require.NoError(query(t, b, nested(maxQueryDepth), &res))
+	err := query(t, b, nested(maxQueryDepth+1), &res)
+	require.Error(err)
+	require.Contains(err.Error(), "exceeds max depth")
+
+	// the range within the limit is allowed, but the blocks past the head aren't resolved
+	require.EqualError(query(t, b, `{ blocks(from: 0, to: 1023) { number } }`, &res), "graphql: block not found")
+	require.EqualError(query(t, b, `{ blocks(from: 0, to: 1024) { number } }`, &res),
+		"graphql: block range is too large, the limit is 1024 blocks")
+	require.EqualError(query(t, b, `{ logs(filter: { fromBlock: 0, toBlock: 2000 }) { index } }`, &res),
+		"graphql: block range is too large, the limit is 1024 blocks")
+}
@@ -0,0 +1,391 @@
+package graphql
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+)
+
+// Event represents a Lachesis DAG event.
+// backend and id are mandatory; the event itself is fetched when required.
+type Event struct {
+	backend Backend
+	id      hash.Event
+	event   *inter.EventPayload
+}
+
+// resolve returns the internal event object, fetching it if needed.
+func (e *Event) resolve(ctx context.Context) (*inter.EventPayload, error) {
+	if e.event == nil {
+		event, err := e.backend.GetEventPayload(ctx, e.id.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if event == nil {
+			return nil, errEventNotFound
+		}
+		e.event = event
+	}
+	return e.event, nil
+}
+
+func (e *Event) ID(ctx context.Context) common.Hash {
+	return common.Hash(e.id)
+}
+
+func (e *Event) Epoch(ctx context.Context) Long {
+	return Long(e.id.Epoch())
+}
+
+func (e *Event) Seq(ctx context.Context) (Long, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(event.Seq()), nil
+}
+
+func (e *Event) Frame(ctx context.Context) (Long, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(event.Frame()), nil
+}
+
+func (e *Event) Lamport(ctx context.Context) Long {
+	return Long(e.id.Lamport())
+}
+
+func (e *Event) Creator(ctx context.Context) (*Validator, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{
+		epoch: &Epoch{backend: e.backend, number: event.Epoch()},
+		id:    event.Creator(),
+	}, nil
+}
+
+func (e *Event) CreationTime(ctx context.Context) (Long, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(event.CreationTime()), nil
+}
+
+func (e *Event) MedianTime(ctx context.Context) (Long, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(event.MedianTime()), nil
+}
+
+func (e *Event) GasPowerUsed(ctx context.Context) (Long, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(event.GasPowerUsed()), nil
+}
+
+func (e *Event) ExtraData(ctx context.Context) (hexutil.Bytes, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return hexutil.Bytes{}, err
+	}
+	return event.Extra(), nil
+}
+
+func (e *Event) Parents(ctx context.Context) ([]*Event, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Event, 0, len(event.Parents()))
+	for _, p := range event.Parents() {
+		ret = append(ret, &Event{
+			backend: e.backend,
+			id:      p,
+		})
+	}
+	return ret, nil
+}
+
+// Transactions returns the event transactions. Note, their block is resolved by the
+// transactions index, so it isn't necessarily decided by this event.
+func (e *Event) Transactions(ctx context.Context) ([]*Transaction, error) {
+	event, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Transaction, 0, len(event.Txs()))
+	for _, tx := range event.Txs() {
+		ret = append(ret, &Transaction{
+			backend: e.backend,
+			hash:    tx.Hash(),
+		})
+	}
+	return ret, nil
+}
+
+// Epoch represents a Lachesis epoch.
+// backend and number are mandatory; the epoch state is fetched when required.
+type Epoch struct {
+	backend Backend
+	number  idx.Epoch
+	bs      *iblockproc.BlockState
+	es      *iblockproc.EpochState
+}
+
+// resolve returns the epoch and block states of the epoch, fetching them if needed.
+// The states are taken at the start of a sealed epoch, or at the latest block for the current epoch.
+func (e *Epoch) resolve(ctx context.Context) (*iblockproc.BlockState, *iblockproc.EpochState, error) {
+	if e.es == nil {
+		epoch := rpc.BlockNumber(e.number)
+		if e.number == e.backend.CurrentEpoch(ctx) {
+			epoch = rpc.PendingBlockNumber
+		}
+		bs, es, err := e.backend.GetEpochBlockState(ctx, epoch)
+		if err != nil {
+			return nil, nil, err
+		}
+		if es == nil || bs == nil || es.Epoch != e.number {
+			return nil, nil, errEpochNotFound
+		}
+		e.bs, e.es = bs, es
+	}
+	return e.bs, e.es, nil
+}
+
+func (e *Epoch) Number(ctx context.Context) Long {
+	return Long(e.number)
+}
+
+func (e *Epoch) Start(ctx context.Context) (Long, error) {
+	_, es, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(es.EpochStart), nil
+}
+
+func (e *Epoch) PrevEpochStart(ctx context.Context) (Long, error) {
+	_, es, err := e.resolve(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return Long(es.PrevEpochStart), nil
+}
+
+func (e *Epoch) StateRoot(ctx context.Context) (common.Hash, error) {
+	_, es, err := e.resolve(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.Hash(es.EpochStateRoot), nil
+}
+
+func (e *Epoch) LastBlock(ctx context.Context) (*Block, error) {
+	bs, _, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	number := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(bs.LastBlock.Idx))
+	return &Block{
+		backend:      e.backend,
+		numberOrHash: &number,
+	}, nil
+}
+
+func (e *Epoch) TotalWeight(ctx context.Context) (hexutil.Big, error) {
+	_, es, err := e.resolve(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	total := new(big.Int)
+	for _, id := range es.Validators.IDs() {
+		if profile, ok := es.ValidatorProfiles[id]; ok {
+			total.Add(total, profile.Weight)
+		}
+	}
+	return hexutil.Big(*total), nil
+}
+
+func (e *Epoch) Validators(ctx context.Context) ([]*Validator, error) {
+	_, es, err := e.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := es.Validators.SortedIDs()
+	ret := make([]*Validator, 0, len(ids))
+	for _, id := range ids {
+		ret = append(ret, &Validator{
+			epoch: e,
+			id:    id,
+		})
+	}
+	return ret, nil
+}
+
+// Validator represents a validator of a particular epoch.
+type Validator struct {
+	epoch *Epoch
+	id    idx.ValidatorID
+}
+
+// state returns the validator block state, or nil if it isn't a validator of the epoch.
+func (v *Validator) state(ctx context.Context) (*iblockproc.ValidatorBlockState, error) {
+	bs, es, err := v.epoch.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !es.Validators.Exists(v.id) {
+		return nil, nil
+	}
+	return bs.GetValidatorState(v.id, es.Validators), nil
+}
+
+func (v *Validator) ID(ctx context.Context) Long {
+	return Long(v.id)
+}
+
+func (v *Validator) Epoch(ctx context.Context) Long {
+	return Long(v.epoch.number)
+}
+
+func (v *Validator) Weight(ctx context.Context) (*hexutil.Big, error) {
+	_, es, err := v.epoch.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := es.ValidatorProfiles[v.id]
+	if !ok || !es.Validators.Exists(v.id) {
+		return nil, nil
+	}
+	return (*hexutil.Big)(profile.Weight), nil
+}
+
+func (v *Validator) PubKey(ctx context.Context) (*hexutil.Bytes, error) {
+	_, es, err := v.epoch.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := es.ValidatorProfiles[v.id]
+	if !ok {
+		return nil, nil
+	}
+	pubkey := hexutil.Bytes(profile.PubKey.Bytes())
+	return &pubkey, nil
+}
+
+func (v *Validator) OriginatedFee(ctx context.Context) (*hexutil.Big, error) {
+	state, err := v.state(ctx)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(state.Originated), nil
+}
+
+func (v *Validator) LastBlock(ctx context.Context) (*Long, error) {
+	state, err := v.state(ctx)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	ret := Long(state.LastBlock)
+	return &ret, nil
+}
+
+func (v *Validator) LastEvent(ctx context.Context) (*Event, error) {
+	state, err := v.state(ctx)
+	if err != nil || state == nil || state.LastEvent.ID.IsZero() {
+		return nil, err
+	}
+	return &Event{
+		backend: v.epoch.backend,
+		id:      state.LastEvent.ID,
+	}, nil
+}
+
+// Epoch returns the epoch the block belongs to.
+func (b *Block) Epoch(ctx context.Context) (*Epoch, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	epoch := hash.Event(header.Hash).Epoch()
+	if epoch == 0 {
+		// genesis blocks aren't decided by events
+		return nil, nil
+	}
+	return &Epoch{
+		backend: b.backend,
+		number:  epoch,
+	}, nil
+}
+
+// Atropos returns the event which decided the block.
+func (b *Block) Atropos(ctx context.Context) (*Event, error) {
+	header, err := b.resolveHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	event := &Event{
+		backend: b.backend,
+		id:      hash.Event(header.Hash),
+	}
+	if _, err := event.resolve(ctx); err == errEventNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Event fetches a DAG event by its full or short ID.
+func (r *Resolver) Event(ctx context.Context, args struct{ ID string }) (*Event, error) {
+	event, err := r.backend.GetEventPayload(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, nil
+	}
+	return &Event{
+		backend: r.backend,
+		id:      event.ID(),
+		event:   event,
+	}, nil
+}
+
+// Epoch fetches an epoch by its number, or the current epoch if the number isn't supplied.
+func (r *Resolver) Epoch(ctx context.Context, args struct{ Number *Long }) (*Epoch, error) {
+	epoch := &Epoch{
+		backend: r.backend,
+		number:  r.backend.CurrentEpoch(ctx),
+	}
+	if args.Number != nil {
+		if *args.Number <= 0 {
+			return nil, nil
+		}
+		epoch.number = idx.Epoch(*args.Number)
+	}
+	// Resolve the epoch state, return nil if it doesn't exist.
+	if _, _, err := epoch.resolve(ctx); err == errEpochNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return epoch, nil
+}
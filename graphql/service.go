@@ -0,0 +1,97 @@
+// Copyright 2019 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/Fantom-foundation/go-opera/gossip/filters"
+)
+
+const (
+	// maxQueryDepth limits the fields nesting of a query, so a query cannot resolve
+	// an exponential number of objects, e.g. parents of parents of events
+	maxQueryDepth = 16
+	// maxParallelism limits the number of resolvers of a query running in parallel
+	maxParallelism = 10
+)
+
+type handler struct {
+	Schema *graphql.Schema
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := h.Schema.Exec(r.Context(), params.Query, params.OperationName, params.Variables)
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(response.Errors) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(responseJSON)
+}
+
+// New constructs a new GraphQL service instance.
+func New(stack *node.Node, backend Backend, filterCfg filters.Config, cors, vhosts []string) error {
+	if backend == nil {
+		panic("missing backend")
+	}
+	// check if http server with given endpoint exists and enable graphQL on it
+	return newHandler(stack, backend, filterCfg, cors, vhosts)
+}
+
+// newHandler returns a new `http.Handler` that will answer GraphQL queries.
+// It additionally exports an interactive query browser on the / endpoint.
+func newHandler(stack *node.Node, backend Backend, filterCfg filters.Config, cors, vhosts []string) error {
+	s, err := newSchema(backend, filterCfg)
+	if err != nil {
+		return err
+	}
+	h := handler{Schema: s}
+	handler := node.NewHTTPHandlerStack(h, cors, vhosts)
+
+	stack.RegisterHandler("GraphQL UI", "/graphql/ui", GraphiQL{})
+	stack.RegisterHandler("GraphQL", "/graphql", handler)
+	stack.RegisterHandler("GraphQL", "/graphql/", handler)
+
+	return nil
+}
+
+// newSchema parses the GraphQL schema with the resolvers backed by the backend.
+func newSchema(backend Backend, filterCfg filters.Config) (*graphql.Schema, error) {
+	return graphql.ParseSchema(schema, &Resolver{backend, filterCfg},
+		graphql.MaxDepth(maxQueryDepth),
+		graphql.MaxParallelism(maxParallelism))
+}
@@ -27,7 +27,6 @@ import (
 	"github.com/Fantom-foundation/go-opera/opera/contracts/driverauth"
 	"github.com/Fantom-foundation/go-opera/opera/contracts/evmwriter"
 	"github.com/Fantom-foundation/go-opera/opera/contracts/netinit"
-	netinitcall "github.com/Fantom-foundation/go-opera/opera/contracts/netinit/netinitcalls"
 	"github.com/Fantom-foundation/go-opera/opera/contracts/sfc"
 	"github.com/Fantom-foundation/go-opera/opera/genesis"
 	"github.com/Fantom-foundation/go-opera/opera/genesis/gpos"
@@ -135,32 +134,8 @@ func FakeGenesisStoreWithRulesAndStart(num idx.Validator, balance, stake *big.In
 	})
 }
 
-func txBuilder() func(calldata []byte, addr common.Address) *types.Transaction {
-	nonce := uint64(0)
-	return func(calldata []byte, addr common.Address) *types.Transaction {
-		tx := types.NewTransaction(nonce, addr, common.Big0, 1e10, common.Big0, calldata)
-		nonce++
-		return tx
-	}
-}
-
 func GetGenesisTxs(sealedEpoch idx.Epoch, validators gpos.Validators, totalSupply *big.Int, delegations []drivercall.Delegation, driverOwner common.Address) types.Transactions {
-	buildTx := txBuilder()
-	internalTxs := make(types.Transactions, 0, 15)
-	// initialization
-	calldata := netinitcall.InitializeAll(sealedEpoch, totalSupply, sfc.ContractAddress, driverauth.ContractAddress, driver.ContractAddress, evmwriter.ContractAddress, driverOwner)
-	internalTxs = append(internalTxs, buildTx(calldata, netinit.ContractAddress))
-	// push genesis validators
-	for _, v := range validators {
-		calldata := drivercall.SetGenesisValidator(v)
-		internalTxs = append(internalTxs, buildTx(calldata, driver.ContractAddress))
-	}
-	// push genesis delegations
-	for _, delegation := range delegations {
-		calldata := drivercall.SetGenesisDelegation(delegation)
-		internalTxs = append(internalTxs, buildTx(calldata, driver.ContractAddress))
-	}
-	return internalTxs
+	return makegenesis.GetGenesisTxs(sealedEpoch, validators, totalSupply, delegations, driverOwner)
 }
 
 func GetFakeValidators(num idx.Validator) gpos.Validators {
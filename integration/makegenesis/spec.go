@@ -0,0 +1,272 @@
+package makegenesis
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/inter/pos"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/lachesis"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/drivertype"
+	"github.com/Fantom-foundation/go-opera/inter/iblockproc"
+	"github.com/Fantom-foundation/go-opera/inter/ier"
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+	"github.com/Fantom-foundation/go-opera/opera"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/driver"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/driver/drivercall"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/driverauth"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/evmwriter"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/netinit"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/sfc"
+	"github.com/Fantom-foundation/go-opera/opera/genesis"
+	"github.com/Fantom-foundation/go-opera/opera/genesis/gpos"
+	"github.com/Fantom-foundation/go-opera/opera/genesisstore"
+)
+
+type (
+	// NetworkSpec is a declarative description of a network genesis.
+	NetworkSpec struct {
+		// Rules are the network rules. Rules which aren't specified are taken from BaseRules.
+		Rules opera.Rules
+		// BaseRules is one of "main", "test" or "fake".
+		BaseRules string
+
+		// StartTime is the genesis time in unix seconds.
+		StartTime uint64
+		// StartEpoch is the first epoch of the network, 2 by default.
+		StartEpoch idx.Epoch
+		// StartBlock is the first block of the network, 1 by default.
+		StartBlock idx.Block
+		// DriverOwner is the owner of NodeDriverAuth contract, the first validator by default.
+		DriverOwner *common.Address
+
+		Validators  []ValidatorSpec
+		Delegations []DelegationSpec
+		Accounts    []AccountSpec
+	}
+
+	// ValidatorSpec is a genesis validator.
+	// The Stake, if specified, is delegated by the validator's address.
+	ValidatorSpec struct {
+		ID      idx.ValidatorID
+		Address common.Address
+		PubKey  validatorpk.PubKey
+		Stake   *math.HexOrDecimal256
+	}
+
+	// DelegationSpec is a genesis delegation.
+	// LockupEndTime is in unix seconds, LockupDuration is in seconds.
+	DelegationSpec struct {
+		Address         common.Address
+		ValidatorID     idx.ValidatorID
+		Stake           *math.HexOrDecimal256
+		LockedStake     *math.HexOrDecimal256
+		LockupFromEpoch idx.Epoch
+		LockupEndTime   uint64
+		LockupDuration  uint64
+	}
+
+	// AccountSpec is a prefunded account or a predeployed contract.
+	AccountSpec struct {
+		Address common.Address
+		Balance *math.HexOrDecimal256
+		Nonce   uint64
+		Code    hexutil.Bytes
+		Storage map[common.Hash]common.Hash
+	}
+)
+
+// BaseRules returns the rules by their name.
+func BaseRules(name string) (opera.Rules, error) {
+	switch name {
+	case "main":
+		return opera.MainNetRules(), nil
+	case "test":
+		return opera.TestNetRules(), nil
+	case "fake":
+		return opera.FakeNetRules(), nil
+	}
+	return opera.Rules{}, fmt.Errorf("unknown base rules '%s': has to be either 'main' or 'test' or 'fake'", name)
+}
+
+func toBig(v *math.HexOrDecimal256) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return (*big.Int)(v)
+}
+
+// Validate checks the spec for consistency.
+func (spec *NetworkSpec) Validate() error {
+	if len(spec.Rules.Name) == 0 || spec.Rules.NetworkID == 0 {
+		return errors.New("network name and ID must be specified in rules")
+	}
+	if spec.StartEpoch < 2 {
+		return errors.New("start epoch must be at least 2")
+	}
+	if spec.StartBlock < 1 {
+		return errors.New("start block must be at least 1")
+	}
+	if len(spec.Validators) == 0 {
+		return errors.New("no validators")
+	}
+	validators := map[idx.ValidatorID]bool{}
+	stakes := map[idx.ValidatorID]*big.Int{}
+	totalStake := new(big.Int)
+	for _, v := range spec.Validators {
+		if v.ID == 0 {
+			return errors.New("validator ID must be positive")
+		}
+		if validators[v.ID] {
+			return fmt.Errorf("duplicate validator %d", v.ID)
+		}
+		if v.PubKey.Type != validatorpk.Types.Secp256k1 {
+			return fmt.Errorf("validator %d: unsupported pubkey type", v.ID)
+		}
+		validators[v.ID] = true
+		stakes[v.ID] = new(big.Int).Set(toBig(v.Stake))
+	}
+	for _, d := range spec.Delegations {
+		if !validators[d.ValidatorID] {
+			return fmt.Errorf("delegation of %s to unknown validator %d", d.Address.String(), d.ValidatorID)
+		}
+		if toBig(d.LockedStake).Cmp(toBig(d.Stake)) > 0 {
+			return fmt.Errorf("delegation of %s to validator %d: locked stake exceeds stake", d.Address.String(), d.ValidatorID)
+		}
+		stakes[d.ValidatorID].Add(stakes[d.ValidatorID], toBig(d.Stake))
+	}
+	for _, v := range spec.Validators {
+		if stakes[v.ID].Sign() <= 0 {
+			return fmt.Errorf("validator %d has no stake", v.ID)
+		}
+		totalStake.Add(totalStake, stakes[v.ID])
+	}
+	if totalStake.Sign() <= 0 {
+		return errors.New("total stake is zero")
+	}
+	accounts := map[common.Address]bool{}
+	for _, acc := range spec.Accounts {
+		if accounts[acc.Address] {
+			return fmt.Errorf("duplicate account %s", acc.Address.String())
+		}
+		accounts[acc.Address] = true
+	}
+	return nil
+}
+
+// BuildFromSpec builds the genesis described by the spec.
+// The essential network contracts are predeployed, the spec accounts may override them.
+func BuildFromSpec(dbs kvdb.DBProducer, spec NetworkSpec) (*genesisstore.Store, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	builder := NewGenesisBuilder(dbs)
+	startTime := inter.FromUnix(int64(spec.StartTime))
+
+	validators := make(gpos.Validators, 0, len(spec.Validators))
+	var delegations []drivercall.Delegation
+	for _, v := range spec.Validators {
+		validators = append(validators, gpos.Validator{
+			ID:           v.ID,
+			Address:      v.Address,
+			PubKey:       v.PubKey,
+			CreationTime: startTime,
+		})
+		if v.Stake != nil {
+			delegations = append(delegations, drivercall.Delegation{
+				Address:            v.Address,
+				ValidatorID:        v.ID,
+				Stake:              toBig(v.Stake),
+				LockedStake:        new(big.Int),
+				EarlyUnlockPenalty: new(big.Int),
+				Rewards:            new(big.Int),
+			})
+		}
+	}
+	for _, d := range spec.Delegations {
+		delegations = append(delegations, drivercall.Delegation{
+			Address:            d.Address,
+			ValidatorID:        d.ValidatorID,
+			Stake:              toBig(d.Stake),
+			LockedStake:        toBig(d.LockedStake),
+			LockupFromEpoch:    d.LockupFromEpoch,
+			LockupEndTime:      inter.FromUnix(int64(d.LockupEndTime)),
+			LockupDuration:     d.LockupDuration,
+			EarlyUnlockPenalty: new(big.Int),
+			Rewards:            new(big.Int),
+		})
+	}
+
+	// deploy essential contracts
+	builder.SetCode(netinit.ContractAddress, netinit.GetContractBin())
+	builder.SetCode(driver.ContractAddress, driver.GetContractBin())
+	builder.SetCode(driverauth.ContractAddress, driverauth.GetContractBin())
+	builder.SetCode(sfc.ContractAddress, sfc.GetContractBin())
+	// set non-zero code for pre-compiled contracts
+	builder.SetCode(evmwriter.ContractAddress, []byte{0})
+
+	for _, acc := range spec.Accounts {
+		if acc.Balance != nil {
+			builder.AddBalance(acc.Address, toBig(acc.Balance))
+		}
+		if acc.Nonce != 0 {
+			builder.SetNonce(acc.Address, acc.Nonce)
+		}
+		if len(acc.Code) != 0 {
+			builder.SetCode(acc.Address, acc.Code)
+		}
+		for key, val := range acc.Storage {
+			builder.SetStorage(acc.Address, key, val)
+		}
+	}
+
+	builder.SetCurrentEpoch(ier.LlrIdxFullEpochRecord{
+		LlrFullEpochRecord: ier.LlrFullEpochRecord{
+			BlockState: iblockproc.BlockState{
+				LastBlock: iblockproc.BlockCtx{
+					Idx:  spec.StartBlock - 1,
+					Time: startTime,
+				},
+				EpochCheaters:         lachesis.Cheaters{},
+				ValidatorStates:       make([]iblockproc.ValidatorBlockState, 0),
+				NextValidatorProfiles: make(map[idx.ValidatorID]drivertype.Validator),
+			},
+			EpochState: iblockproc.EpochState{
+				Epoch:             spec.StartEpoch - 1,
+				EpochStart:        startTime,
+				PrevEpochStart:    startTime - 1,
+				EpochStateRoot:    hash.Zero,
+				Validators:        pos.NewBuilder().Build(),
+				ValidatorStates:   make([]iblockproc.ValidatorEpochState, 0),
+				ValidatorProfiles: make(map[idx.ValidatorID]drivertype.Validator),
+				Rules:             spec.Rules,
+			},
+		},
+		Idx: spec.StartEpoch - 1,
+	})
+
+	owner := validators[0].Address
+	if spec.DriverOwner != nil {
+		owner = *spec.DriverOwner
+	}
+
+	genesisTxs := GetGenesisTxs(spec.StartEpoch-2, validators, builder.TotalSupply(), delegations, owner)
+	err := builder.ExecuteGenesisTxs(DefaultBlockProc(), genesisTxs)
+	if err != nil {
+		return nil, err
+	}
+
+	return builder.Build(genesis.Header{
+		GenesisID:   builder.CurrentHash(),
+		NetworkID:   spec.Rules.NetworkID,
+		NetworkName: spec.Rules.Name,
+	}), nil
+}
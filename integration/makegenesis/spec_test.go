@@ -0,0 +1,113 @@
+package makegenesis
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter/ier"
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+	"github.com/Fantom-foundation/go-opera/logger"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+func testSpec(t *testing.T) NetworkSpec {
+	rules := opera.FakeNetRules()
+	rules.Name = "spec"
+	rules.NetworkID = 4003
+	spec := NetworkSpec{
+		Rules:      rules,
+		StartTime:  1650000000,
+		StartEpoch: 5,
+		StartBlock: 10,
+	}
+	for i := idx.ValidatorID(1); i <= 3; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		spec.Validators = append(spec.Validators, ValidatorSpec{
+			ID:      i,
+			Address: crypto.PubkeyToAddress(key.PublicKey),
+			PubKey: validatorpk.PubKey{
+				Type: validatorpk.Types.Secp256k1,
+				Raw:  crypto.FromECDSAPub(&key.PublicKey),
+			},
+			Stake: (*math.HexOrDecimal256)(new(big.Int).Mul(big.NewInt(int64(i)), big.NewInt(1e18))),
+		})
+	}
+	spec.Delegations = []DelegationSpec{{
+		Address:     common.Address{1},
+		ValidatorID: 1,
+		Stake:       (*math.HexOrDecimal256)(big.NewInt(1e18)),
+		// 2022-05-01
+		LockupEndTime: 1651363200,
+	}}
+	spec.Accounts = []AccountSpec{{
+		Address: common.Address{2},
+		Balance: (*math.HexOrDecimal256)(big.NewInt(1e18)),
+		Code:    []byte{0x60, 0x01},
+		Storage: map[common.Hash]common.Hash{{1}: {2}},
+	}}
+	return spec
+}
+
+func TestBuildFromSpec(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+	spec := testSpec(t)
+
+	store, err := BuildFromSpec(memorydb.NewProducer(""), spec)
+	require.NoError(err)
+	require.Equal("spec", store.Header().NetworkName)
+	require.Equal(uint64(4003), store.Header().NetworkID)
+
+	var epochs []ier.LlrIdxFullEpochRecord
+	store.Epochs().ForEach(func(er ier.LlrIdxFullEpochRecord) bool {
+		epochs = append(epochs, er)
+		return true
+	})
+	require.Len(epochs, 1)
+	er := epochs[0]
+	require.Equal(idx.Epoch(5), er.Idx)
+	require.Equal(idx.Block(10), er.BlockState.LastBlock.Idx)
+	require.Equal(spec.Rules.String(), er.EpochState.Rules.String())
+	require.Equal(idx.Validator(3), er.EpochState.Validators.Len())
+	// self-stake plus the delegation
+	require.Equal(big.NewInt(2e18), er.EpochState.ValidatorProfiles[1].Weight)
+	require.Equal(big.NewInt(3e18), er.EpochState.ValidatorProfiles[3].Weight)
+	require.Equal(spec.Validators[1].PubKey, er.EpochState.ValidatorProfiles[2].PubKey)
+
+	// the output is reproducible
+	again, err := BuildFromSpec(memorydb.NewProducer(""), spec)
+	require.NoError(err)
+	require.Equal(store.Header(), again.Header())
+
+	spec.Delegations[0].ValidatorID = 4
+	_, err = BuildFromSpec(memorydb.NewProducer(""), spec)
+	require.Error(err)
+}
+
+func TestSpecValidateStake(t *testing.T) {
+	require := require.New(t)
+
+	spec := testSpec(t)
+	require.NoError(spec.Validate())
+
+	// delegations count towards the validator stake
+	spec.Validators[0].Stake = nil
+	require.NoError(spec.Validate())
+
+	spec.Validators[1].Stake = (*math.HexOrDecimal256)(new(big.Int))
+	require.EqualError(spec.Validate(), "validator 2 has no stake")
+
+	for i := range spec.Validators {
+		spec.Validators[i].Stake = nil
+	}
+	spec.Delegations = nil
+	require.Error(spec.Validate())
+}
@@ -0,0 +1,46 @@
+package makegenesis
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/Fantom-foundation/go-opera/opera/contracts/driver"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/driver/drivercall"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/driverauth"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/evmwriter"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/netinit"
+	netinitcall "github.com/Fantom-foundation/go-opera/opera/contracts/netinit/netinitcalls"
+	"github.com/Fantom-foundation/go-opera/opera/contracts/sfc"
+	"github.com/Fantom-foundation/go-opera/opera/genesis/gpos"
+)
+
+func txBuilder() func(calldata []byte, addr common.Address) *types.Transaction {
+	nonce := uint64(0)
+	return func(calldata []byte, addr common.Address) *types.Transaction {
+		tx := types.NewTransaction(nonce, addr, common.Big0, 1e10, common.Big0, calldata)
+		nonce++
+		return tx
+	}
+}
+
+func GetGenesisTxs(sealedEpoch idx.Epoch, validators gpos.Validators, totalSupply *big.Int, delegations []drivercall.Delegation, driverOwner common.Address) types.Transactions {
+	buildTx := txBuilder()
+	internalTxs := make(types.Transactions, 0, 15)
+	// initialization
+	calldata := netinitcall.InitializeAll(sealedEpoch, totalSupply, sfc.ContractAddress, driverauth.ContractAddress, driver.ContractAddress, evmwriter.ContractAddress, driverOwner)
+	internalTxs = append(internalTxs, buildTx(calldata, netinit.ContractAddress))
+	// push genesis validators
+	for _, v := range validators {
+		calldata := drivercall.SetGenesisValidator(v)
+		internalTxs = append(internalTxs, buildTx(calldata, driver.ContractAddress))
+	}
+	// push genesis delegations
+	for _, delegation := range delegations {
+		calldata := drivercall.SetGenesisDelegation(delegation)
+		internalTxs = append(internalTxs, buildTx(calldata, driver.ContractAddress))
+	}
+	return internalTxs
+}
@@ -50,6 +50,11 @@ func Pbl1RoutingConfig() RoutingConfig {
 				Type: "pebble-fsh",
 				Name: "evm-logs",
 			},
+			"evm-traces": {
+				Type:  "pebble-fsh",
+				Name:  "main",
+				Table: "W",
+			},
 			"gossip-%d": {
 				Type:  "leveldb-fsh",
 				Name:  "epoch-%d",
@@ -163,6 +168,11 @@ func Ldb1RoutingConfig() RoutingConfig {
 				Name:  "main",
 				Table: "L",
 			},
+			"evm-traces": {
+				Type:  "leveldb-fsh",
+				Name:  "main",
+				Table: "W",
+			},
 			"gossip-%d": {
 				Type:  "leveldb-fsh",
 				Name:  "epoch-%d",
@@ -300,6 +310,11 @@ func LdbLegacyRoutingConfig() RoutingConfig {
 				Name:  "main",
 				Table: "L",
 			},
+			"evm-traces": {
+				Type:  "leveldb-fsh",
+				Name:  "main",
+				Table: "W",
+			},
 			"gossip-%d": {
 				Type: "leveldb-fsh",
 				Name: "gossip-%d",
@@ -450,6 +465,11 @@ func PblLegacyRoutingConfig() RoutingConfig {
 				Name:  "main",
 				Table: "L",
 			},
+			"evm-traces": {
+				Type:  "pebble-fsh",
+				Name:  "main",
+				Table: "W",
+			},
 			"gossip-%d": {
 				Type: "pebble-fsh",
 				Name: "gossip-%d",
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/Fantom-foundation/lachesis-base/kvdb"
 	"github.com/Fantom-foundation/lachesis-base/kvdb/cachedproducer"
@@ -13,6 +14,35 @@ type RoutingConfig struct {
 	Table map[string]multidb.Route
 }
 
+// FreezerType is the route type of the freezer, which keeps the finalized history in flat files.
+// Name of the route is the freezer directory, either absolute or relative to the chaindata directory.
+const FreezerType multidb.TypeName = "freezer"
+
+// DBsTable returns the routes of the key-value DBs
+func (c RoutingConfig) DBsTable() map[string]multidb.Route {
+	table := make(map[string]multidb.Route, len(c.Table))
+	for req, route := range c.Table {
+		if route.Type != FreezerType {
+			table[req] = route
+		}
+	}
+	return table
+}
+
+// FreezerDir returns the directory of the freezer, or empty string if the freezer isn't routed
+func (c RoutingConfig) FreezerDir(chaindataDir string) string {
+	for _, route := range c.Table {
+		if route.Type != FreezerType {
+			continue
+		}
+		if filepath.IsAbs(route.Name) {
+			return route.Name
+		}
+		return filepath.Join(chaindataDir, route.Name)
+	}
+	return ""
+}
+
 func MakeMultiProducer(rawProducers map[multidb.TypeName]kvdb.IterableDBProducer, scopedProducers map[multidb.TypeName]kvdb.FullDBProducer, cfg RoutingConfig) (kvdb.FullDBProducer, error) {
 	cachedProducers := make(map[multidb.TypeName]kvdb.FullDBProducer)
 	var flushID []byte
@@ -38,7 +68,7 @@ func MakeDirectMultiProducer(rawProducers map[multidb.TypeName]kvdb.IterableDBPr
 }
 
 func makeMultiProducer(scopedProducers map[multidb.TypeName]kvdb.FullDBProducer, cfg RoutingConfig) (kvdb.FullDBProducer, error) {
-	multi, err := multidb.NewProducer(scopedProducers, cfg.Table, TablesKey)
+	multi, err := multidb.NewProducer(scopedProducers, cfg.DBsTable(), TablesKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct multidb: %v", err)
 	}
@@ -0,0 +1,26 @@
+package iblockproc
+
+import (
+	"math/big"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/opera"
+)
+
+// ValidatorEpochMetrics is the validator performance in an epoch, as reported to the SFC contract.
+type ValidatorEpochMetrics struct {
+	ID     idx.ValidatorID
+	Missed opera.BlocksMissed
+	Uptime inter.Timestamp
+	// Originated is the accumulated fee originated by the validator, including the previous epochs
+	Originated *big.Int
+}
+
+// EpochMetrics is the validators performance in an epoch.
+type EpochMetrics struct {
+	Epoch      idx.Epoch
+	EpochGas   uint64
+	Validators []ValidatorEpochMetrics
+}
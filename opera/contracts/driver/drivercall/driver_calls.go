@@ -26,7 +26,7 @@ type Delegation struct {
 	Stake              *big.Int
 	LockedStake        *big.Int
 	LockupFromEpoch    idx.Epoch
-	LockupEndTime      idx.Epoch
+	LockupEndTime      inter.Timestamp
 	LockupDuration     uint64
 	EarlyUnlockPenalty *big.Int
 	Rewards            *big.Int
@@ -73,7 +73,7 @@ func SetGenesisValidator(v gpos.Validator) []byte {
 }
 
 func SetGenesisDelegation(d Delegation) []byte {
-	data, _ := sAbi.Pack("setGenesisDelegation", d.Address, utils.U64toBig(uint64(d.ValidatorID)), d.Stake, d.LockedStake, utils.U64toBig(uint64(d.LockupFromEpoch)), utils.U64toBig(uint64(d.LockupEndTime)), utils.U64toBig(uint64(d.LockupDuration)), d.EarlyUnlockPenalty, d.Rewards)
+	data, _ := sAbi.Pack("setGenesisDelegation", d.Address, utils.U64toBig(uint64(d.ValidatorID)), d.Stake, d.LockedStake, utils.U64toBig(uint64(d.LockupFromEpoch)), utils.U64toBig(uint64(d.LockupEndTime.Unix())), utils.U64toBig(uint64(d.LockupDuration)), d.EarlyUnlockPenalty, d.Rewards)
 	return data
 }
 
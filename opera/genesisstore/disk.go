@@ -70,61 +70,116 @@ type Unit struct {
 	Header   genesis.Header
 }
 
-func OpenGenesisStore(rawReader ReadAtSeekerCloser) (*Store, genesis.Hashes, error) {
-	header := genesis.Header{}
-	hashes := genesis.Hashes{}
-	units := make([]readersmap.Unit, 0, 3)
+// UnitInfo is the metadata of a genesis file unit.
+type UnitInfo struct {
+	Unit
+	// Hash is the root of the unit pieces hashes
+	Hash hash.Hash
+	// Offset is the position of the unit's compressed data in the file
+	Offset           int64
+	CompressedSize   uint64
+	UncompressedSize uint64
+}
+
+// readUnitInfo reads the metadata of the unit which starts at the offset.
+func readUnitInfo(rawReader io.ReaderAt, offset int64) (UnitInfo, error) {
+	info := UnitInfo{}
+	// header cannot be long, cap it with 100000 bytes
+	headerReader := io.NewSectionReader(rawReader, offset, offset+100000)
+	err := checkFileHeader(headerReader)
+	if err != nil {
+		return info, err
+	}
+	err = rlp.Decode(dummyByteReader{headerReader}, &info.Unit)
+	if err != nil {
+		return info, err
+	}
+
+	err = ioread.ReadAll(headerReader, info.Hash[:])
+	if err != nil {
+		return info, err
+	}
+
+	var numB [8]byte
+	err = ioread.ReadAll(headerReader, numB[:])
+	if err != nil {
+		return info, err
+	}
+	info.CompressedSize = bigendian.BytesToUint64(numB[:])
+
+	err = ioread.ReadAll(headerReader, numB[:])
+	if err != nil {
+		return info, err
+	}
+	info.UncompressedSize = bigendian.BytesToUint64(numB[:])
+
+	headerSize, err := headerReader.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return info, err
+	}
+	info.Offset = offset + headerSize
+	return info, nil
+}
+
+// ReadUnitsInfo reads the metadata of all the genesis file units without reading their data.
+func ReadUnitsInfo(rawReader io.ReaderAt) ([]UnitInfo, error) {
+	units := make([]UnitInfo, 0, 3)
 	offset := int64(0)
 	for i := 0; ; i++ {
-		// header cannot be long, cap it with 100000 bytes
-		headerReader := io.NewSectionReader(rawReader, offset, offset+100000)
-		err := checkFileHeader(headerReader)
+		info, err := readUnitInfo(rawReader, offset)
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return nil, hashes, err
+			return nil, err
 		}
-		unit := Unit{}
-		err = rlp.Decode(dummyByteReader{headerReader}, &unit)
-		if err != nil {
-			return nil, hashes, err
-		}
-		if i == 0 {
-			header = unit.Header
-		} else {
-			if !header.Equal(unit.Header) {
-				return nil, hashes, errors.New("subsequent genesis header doesn't match the first header")
-			}
+		if i != 0 && !units[0].Header.Equal(info.Header) {
+			return nil, errors.New("subsequent genesis header doesn't match the first header")
 		}
+		offset = info.Offset + int64(info.CompressedSize)
 
-		var h hash.Hash
-		err = ioread.ReadAll(headerReader, h[:])
-		if err != nil {
-			return nil, hashes, err
-		}
-		hashes[unit.UnitName] = h
+		units = append(units, info)
+	}
+	return units, nil
+}
 
-		var numB [8]byte
-		err = ioread.ReadAll(headerReader, numB[:])
-		if err != nil {
-			return nil, hashes, err
+// ReadCompleteUnitsInfo reads the metadata of the fully written units of a genesis file of the given size,
+// ignoring an incomplete tail which is left by an interrupted export.
+// Returns the size of the complete part of the file.
+func ReadCompleteUnitsInfo(rawReader io.ReaderAt, size int64) ([]UnitInfo, int64) {
+	units := make([]UnitInfo, 0, 3)
+	offset := int64(0)
+	for {
+		info, err := readUnitInfo(rawReader, offset)
+		if err != nil || info.Hash == (hash.Hash{}) || info.Offset+int64(info.CompressedSize) > size {
+			// the unit header is written before the data, its hash and size are written at the end
+			break
 		}
-		dataCompressedSize := bigendian.BytesToUint64(numB[:])
-
-		err = ioread.ReadAll(headerReader, numB[:])
-		if err != nil {
-			return nil, hashes, err
+		if len(units) != 0 && !units[0].Header.Equal(info.Header) {
+			break
 		}
-		uncompressedSize := bigendian.BytesToUint64(numB[:])
+		offset = info.Offset + int64(info.CompressedSize)
 
-		headerSize, err := headerReader.Seek(0, io.SeekCurrent)
-		if err != nil {
-			return nil, hashes, err
+		units = append(units, info)
+	}
+	return units, offset
+}
+
+func OpenGenesisStore(rawReader ReadAtSeekerCloser) (*Store, genesis.Hashes, error) {
+	header := genesis.Header{}
+	hashes := genesis.Hashes{}
+	infos, err := ReadUnitsInfo(rawReader)
+	if err != nil {
+		return nil, hashes, err
+	}
+	units := make([]readersmap.Unit, 0, len(infos))
+	for i, info := range infos {
+		if i == 0 {
+			header = info.Header
 		}
+		hashes[info.UnitName] = info.Hash
 
-		unitReader := io.NewSectionReader(rawReader, offset+headerSize, offset+headerSize+int64(dataCompressedSize))
-		offset += headerSize + int64(dataCompressedSize)
+		unitReader := io.NewSectionReader(rawReader, info.Offset, int64(info.CompressedSize))
 
 		gzipReader, err := gzip.NewReader(unitReader)
 		if err != nil {
@@ -133,7 +188,7 @@ func OpenGenesisStore(rawReader ReadAtSeekerCloser) (*Store, genesis.Hashes, err
 
 		// wrap with a logger
 		// human-readable name
-		name := unit.UnitName
+		name := info.UnitName
 		scanfName := strings.ReplaceAll(name, "-", "")
 		if scanfName[len(scanfName)-1] < '0' || scanfName[len(scanfName)-1] > '9' {
 			scanfName += "0"
@@ -148,10 +203,10 @@ func OpenGenesisStore(rawReader ReadAtSeekerCloser) (*Store, genesis.Hashes, err
 		if _, err := fmt.Sscanf(scanfName, "evm%d", &part); err == nil {
 			name = fmt.Sprintf("EVM unit %d", part)
 		}
-		loggedReader := filelog.Wrap(gzipReader, name, uncompressedSize, time.Minute)
+		loggedReader := filelog.Wrap(gzipReader, name, info.UncompressedSize, time.Minute)
 
 		units = append(units, readersmap.Unit{
-			Name:   unit.UnitName,
+			Name:   info.UnitName,
 			Reader: loggedReader,
 		})
 	}
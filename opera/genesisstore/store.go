@@ -40,6 +40,11 @@ func NewStore(fMap FilesMap, head genesis.Header, close func() error) *Store {
 	}
 }
 
+// Section returns the reader of the raw genesis section by its name.
+func (s *Store) Section(name string) (io.Reader, error) {
+	return s.fMap(name)
+}
+
 // Close leaves underlying database.
 func (s *Store) Close() error {
 	s.fMap = nil
@@ -207,6 +207,77 @@ func (tt *Index) Push(recs ...*types.Log) error {
 	return nil
 }
 
+// PruneBlocks deletes log records of block range together with their topic index entries.
+func (tt *Index) PruneBlocks(from, to idx.Block) error {
+	if from > to {
+		return nil
+	}
+	var (
+		ids  []ID
+		vals [][]byte
+	)
+	it := tt.table.Logrec.NewIterator(nil, uintToBytes(uint64(from)))
+	for it.Next() {
+		if len(it.Key()) != logrecKeySize {
+			continue
+		}
+		var id ID
+		copy(id[:], it.Key())
+		if id.BlockNumber() > uint64(to) {
+			break
+		}
+		ids = append(ids, id)
+		vals = append(vals, common.CopyBytes(it.Value()))
+	}
+	err := it.Error()
+	it.Release()
+	if err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		topics, err := tt.logrecTopics(id, vals[i])
+		if err != nil {
+			return err
+		}
+		for pos, topic := range topics {
+			if err := tt.table.Topic.Delete(topicKey(topic, uint8(pos), id)); err != nil {
+				return err
+			}
+		}
+		if err := tt.table.Logrec.Delete(id.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logrecTopics returns the address and the topics of log record, in the order of their index positions.
+// Topics count isn't stored in the record, so it's found by the address entry of the topic index.
+func (tt *Index) logrecTopics(id ID, buf []byte) ([]common.Hash, error) {
+	for count := 0; count <= MaxTopicsCount; count++ {
+		offset := count*common.HashLength + common.HashLength
+		if len(buf) < offset+common.AddressLength {
+			break
+		}
+		address := common.BytesToAddress(buf[offset : offset+common.AddressLength])
+		val, err := tt.table.Topic.Get(topicKey(address.Hash(), 0, id))
+		if err != nil {
+			return nil, err
+		}
+		if len(val) != uint8Size || val[0] != uint8(count) {
+			continue
+		}
+		topics := make([]common.Hash, 0, count+1)
+		topics = append(topics, address.Hash())
+		for i := 0; i < count; i++ {
+			topics = append(topics, common.BytesToHash(buf[i*common.HashLength:(i+1)*common.HashLength]))
+		}
+		return topics, nil
+	}
+	return nil, nil
+}
+
 func (tt *Index) Close() {
 	_ = tt.table.Topic.Close()
 	_ = tt.table.Logrec.Close()
@@ -485,6 +485,39 @@ func TestPatternLimit(t *testing.T) {
 	}
 }
 
+func TestIndexPruneBlocks(t *testing.T) {
+	logger.SetTestMode(t)
+	require := require.New(t)
+
+	topics, recs, _ := genTestData(100)
+	index := New(memorydb.NewProducer(""))
+	for _, rec := range recs {
+		require.NoError(index.Push(rec))
+	}
+
+	require.NoError(index.PruneBlocks(3, 10))
+
+	var kept []*types.Log
+	topicKeys := 0
+	for _, rec := range recs {
+		if rec.BlockNumber < 3 || rec.BlockNumber > 10 {
+			kept = append(kept, rec)
+			topicKeys += len(rec.Topics) + 1
+		}
+	}
+	got, err := index.FindInBlocks(nil, 0, 1000, [][]common.Hash{{}, topics})
+	require.NoError(err)
+	require.ElementsMatch(kept, got)
+
+	// the topic entries of the pruned logs are deleted as well
+	it := index.table.Topic.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		topicKeys--
+	}
+	require.Zero(topicKeys)
+}
+
 func genTestData(count int) (
 	topics []common.Hash,
 	recs []*types.Log,
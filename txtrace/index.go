@@ -0,0 +1,293 @@
+package txtrace
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Fantom-foundation/lachesis-base/common/bigendian"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/table"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	blockSize    = 8
+	positionSize = 4
+	txKeySize    = blockSize + positionSize
+	addrKeySize  = common.AddressLength + txKeySize
+)
+
+// Index is a persistent storage of the transaction traces, indexed by senders and recipients of the traced actions.
+type Index struct {
+	table struct {
+		// blockN+txPosition -> JSON-encoded []ActionTrace
+		Traces kvdb.Store `table:"t"`
+		// address+blockN+txPosition -> nil, for actions sent from address
+		From kvdb.Store `table:"f"`
+		// address+blockN+txPosition -> nil, for actions sent to address
+		To kvdb.Store `table:"o"`
+	}
+}
+
+// TxID is a position of a transaction in the chain.
+type TxID struct {
+	Block    idx.Block
+	Position uint32
+}
+
+// New Index instance.
+func New(dbs kvdb.DBProducer) *Index {
+	tt := &Index{}
+
+	err := table.OpenTables(&tt.table, dbs, "evm-traces")
+	if err != nil {
+		panic(err)
+	}
+
+	return tt
+}
+
+// Close closes underlying database.
+func (tt *Index) Close() {
+	_ = table.CloseTables(&tt.table)
+}
+
+func txKey(id TxID) []byte {
+	key := make([]byte, 0, txKeySize)
+	key = append(key, bigendian.Uint64ToBytes(uint64(id.Block))...)
+	key = append(key, bigendian.Uint32ToBytes(id.Position)...)
+	return key
+}
+
+func txKeyToID(key []byte) TxID {
+	return TxID{
+		Block:    idx.Block(bigendian.BytesToUint64(key[:blockSize])),
+		Position: bigendian.BytesToUint32(key[blockSize:txKeySize]),
+	}
+}
+
+func addrKey(addr common.Address, id TxID) []byte {
+	key := make([]byte, 0, addrKeySize)
+	key = append(key, addr.Bytes()...)
+	key = append(key, txKey(id)...)
+	return key
+}
+
+// SetTxTraces stores traces of the transaction and indexes their participants.
+func (tt *Index) SetTxTraces(id TxID, traces []ActionTrace) error {
+	b, err := json.Marshal(traces)
+	if err != nil {
+		return err
+	}
+	if err := tt.table.Traces.Put(txKey(id), b); err != nil {
+		return err
+	}
+	for _, t := range traces {
+		if err := tt.table.From.Put(addrKey(t.Sender(), id), []byte{}); err != nil {
+			return err
+		}
+		if err := tt.table.To.Put(addrKey(t.Recipient(), id), []byte{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTxTraces returns stored traces of the transaction, or nil if not found.
+func (tt *Index) GetTxTraces(id TxID) ([]ActionTrace, error) {
+	b, err := tt.table.Traces.Get(txKey(id))
+	if err != nil || b == nil {
+		return nil, err
+	}
+	var traces []ActionTrace
+	err = json.Unmarshal(b, &traces)
+	return traces, err
+}
+
+// ForEachTxInBlocks iterates over the traced transactions of block range in the chain order.
+func (tt *Index) ForEachTxInBlocks(ctx context.Context, from, to idx.Block, onTx func(id TxID, traces []ActionTrace) (gonext bool)) error {
+	if from > to {
+		return nil
+	}
+	it := tt.table.Traces.NewIterator(nil, bigendian.Uint64ToBytes(uint64(from)))
+	defer it.Release()
+	for it.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		id := txKeyToID(it.Key())
+		if id.Block > to {
+			break
+		}
+		var traces []ActionTrace
+		if err := json.Unmarshal(it.Value(), &traces); err != nil {
+			return err
+		}
+		if !onTx(id, traces) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+// PruneBlocks deletes traces of the transactions of block range together with their participants index entries.
+func (tt *Index) PruneBlocks(from, to idx.Block) error {
+	if from > to {
+		return nil
+	}
+	var (
+		ids    []TxID
+		traces [][]ActionTrace
+	)
+	err := tt.ForEachTxInBlocks(context.Background(), from, to, func(id TxID, tx []ActionTrace) bool {
+		ids = append(ids, id)
+		traces = append(traces, tx)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		for _, t := range traces[i] {
+			if err := tt.table.From.Delete(addrKey(t.Sender(), id)); err != nil {
+				return err
+			}
+			if err := tt.table.To.Delete(addrKey(t.Recipient(), id)); err != nil {
+				return err
+			}
+		}
+		if err := tt.table.Traces.Delete(txKey(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindTxs iterates over positions of the transactions of block range, which have at least one traced action
+// sent from one of fromAddrs (if not empty) and sent to one of toAddrs (if not empty).
+// Transactions are iterated in the chain order, the index entries are read only until onTx returns false.
+func (tt *Index) FindTxs(ctx context.Context, from, to idx.Block, fromAddrs, toAddrs []common.Address, onTx func(id TxID) (gonext bool)) error {
+	if from > to || len(fromAddrs) == 0 && len(toAddrs) == 0 {
+		return nil
+	}
+	senders := newTxIDsUnion(tt.table.From, from, to, fromAddrs)
+	defer senders.release()
+	recipients := newTxIDsUnion(tt.table.To, from, to, toAddrs)
+	defer recipients.release()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		id, ok := senders.peek()
+		if len(fromAddrs) == 0 {
+			id, ok = recipients.peek()
+		} else if ok && len(toAddrs) != 0 {
+			// intersect the senders and recipients
+			recipients.skipBefore(id)
+			rid, rok := recipients.peek()
+			if !rok {
+				break
+			}
+			if txIDLess(id, rid) {
+				senders.skipBefore(rid)
+				continue
+			}
+		}
+		if !ok {
+			break
+		}
+		senders.skipUntil(id)
+		recipients.skipUntil(id)
+		if !onTx(id) {
+			break
+		}
+	}
+	if err := senders.error(); err != nil {
+		return err
+	}
+	return recipients.error()
+}
+
+func txIDLess(a, b TxID) bool {
+	if a.Block != b.Block {
+		return a.Block < b.Block
+	}
+	return a.Position < b.Position
+}
+
+// txIDsIterator iterates over the transactions of an address index entries in the chain order
+type txIDsIterator struct {
+	it  kvdb.Iterator
+	to  idx.Block
+	cur TxID
+	ok  bool
+}
+
+func (i *txIDsIterator) next() {
+	i.ok = false
+	if i.it.Next() {
+		id := txKeyToID(i.it.Key()[common.AddressLength:])
+		i.cur, i.ok = id, id.Block <= i.to
+	}
+}
+
+// txIDsUnion merges the transactions of the addresses index entries in the chain order, without duplicates
+type txIDsUnion []*txIDsIterator
+
+func newTxIDsUnion(index kvdb.Store, from, to idx.Block, addrs []common.Address) txIDsUnion {
+	u := make(txIDsUnion, 0, len(addrs))
+	for _, addr := range addrs {
+		i := &txIDsIterator{
+			it: index.NewIterator(addr.Bytes(), bigendian.Uint64ToBytes(uint64(from))),
+			to: to,
+		}
+		i.next()
+		u = append(u, i)
+	}
+	return u
+}
+
+// peek returns the lowest transaction
+func (u txIDsUnion) peek() (id TxID, ok bool) {
+	for _, i := range u {
+		if i.ok && (!ok || txIDLess(i.cur, id)) {
+			id, ok = i.cur, true
+		}
+	}
+	return id, ok
+}
+
+// skipBefore skips the transactions which are lower than id
+func (u txIDsUnion) skipBefore(id TxID) {
+	for _, i := range u {
+		for i.ok && txIDLess(i.cur, id) {
+			i.next()
+		}
+	}
+}
+
+// skipUntil skips the transactions which are lower than id or equal to it
+func (u txIDsUnion) skipUntil(id TxID) {
+	for _, i := range u {
+		for i.ok && !txIDLess(id, i.cur) {
+			i.next()
+		}
+	}
+}
+
+func (u txIDsUnion) error() error {
+	for _, i := range u {
+		if err := i.it.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u txIDsUnion) release() {
+	for _, i := range u {
+		i.it.Release()
+	}
+}
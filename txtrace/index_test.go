@@ -0,0 +1,94 @@
+package txtrace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/Fantom-foundation/lachesis-base/kvdb/memorydb"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func callTrace(from, to common.Address) ActionTrace {
+	return ActionTrace{
+		Type:         CallType,
+		TraceAddress: []uint32{},
+		Action: TraceAction{
+			CallType: "call",
+			From:     &from,
+			To:       &to,
+		},
+	}
+}
+
+func TestIndexFindTxs(t *testing.T) {
+	require := require.New(t)
+	index := New(memorydb.NewProducer(""))
+	defer index.Close()
+
+	var (
+		a = common.Address{1}
+		b = common.Address{2}
+		c = common.Address{3}
+	)
+	txs := map[TxID][]ActionTrace{
+		{Block: 1, Position: 0}:   {callTrace(a, b)},
+		{Block: 1, Position: 1}:   {callTrace(b, c), callTrace(c, a)},
+		{Block: 2, Position: 0}:   {callTrace(a, c)},
+		{Block: 300, Position: 5}: {callTrace(c, b)},
+	}
+	for id, traces := range txs {
+		require.NoError(index.SetTxTraces(id, traces))
+	}
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		from, to idx.Block
+		senders  []common.Address
+		recips   []common.Address
+		exp      []TxID
+	}{
+		{1, 1000, []common.Address{a}, nil, []TxID{{1, 0}, {2, 0}}},
+		{1, 1000, nil, []common.Address{a}, []TxID{{1, 1}}},
+		{1, 1000, []common.Address{a}, []common.Address{c}, []TxID{{2, 0}}},
+		{1, 1000, []common.Address{a, b}, []common.Address{c}, []TxID{{1, 1}, {2, 0}}},
+		{2, 1000, []common.Address{c}, nil, []TxID{{300, 5}}},
+		{1, 2, []common.Address{c}, nil, []TxID{{1, 1}}},
+		{3, 299, []common.Address{a, b, c}, nil, []TxID{}},
+	} {
+		got := []TxID{}
+		err := index.FindTxs(ctx, tc.from, tc.to, tc.senders, tc.recips, func(id TxID) bool {
+			got = append(got, id)
+			return true
+		})
+		require.NoError(err)
+		require.Equal(tc.exp, got)
+	}
+
+	// the iteration is stopped early
+	var found []TxID
+	err := index.FindTxs(ctx, 1, 1000, []common.Address{a, b, c}, nil, func(id TxID) bool {
+		found = append(found, id)
+		return len(found) < 2
+	})
+	require.NoError(err)
+	require.Equal([]TxID{{1, 0}, {1, 1}}, found)
+
+	got, err := index.GetTxTraces(TxID{1, 1})
+	require.NoError(err)
+	require.Equal(txs[TxID{1, 1}], got)
+
+	got, err = index.GetTxTraces(TxID{1, 2})
+	require.NoError(err)
+	require.Nil(got)
+
+	var visited []TxID
+	err = index.ForEachTxInBlocks(ctx, 1, 2, func(id TxID, traces []ActionTrace) bool {
+		require.Equal(txs[id], traces)
+		visited = append(visited, id)
+		return true
+	})
+	require.NoError(err)
+	require.Equal([]TxID{{1, 0}, {1, 1}, {2, 0}}, visited)
+}
@@ -0,0 +1,180 @@
+package txtrace
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// CallTracer records flat traces of internal calls, creates and self-destructions
+// of the transactions processed by EVM.
+// It implements evmcore.TxTracer.
+type CallTracer struct {
+	traces map[common.Hash][]ActionTrace
+
+	current []ActionTrace
+	stack   []int // indexes of the entered (not exited yet) actions in current
+}
+
+// NewCallTracer creates a CallTracer instance.
+func NewCallTracer() *CallTracer {
+	return &CallTracer{
+		traces: make(map[common.Hash][]ActionTrace),
+	}
+}
+
+// StartTx resets the traces of the currently processed transaction.
+func (t *CallTracer) StartTx(tx *types.Transaction, index int) {
+	t.current = make([]ActionTrace, 0, 1)
+	t.stack = t.stack[:0]
+}
+
+// FinishTx memorizes traces of the processed transaction, if it wasn't skipped.
+func (t *CallTracer) FinishTx(tx *types.Transaction, receipt *types.Receipt) {
+	if receipt != nil {
+		t.traces[tx.Hash()] = t.current
+	}
+	t.current = nil
+	t.stack = t.stack[:0]
+}
+
+// Traces returns the recorded traces of the transaction at the given position.
+func (t *CallTracer) Traces(txHash common.Hash, blockHash common.Hash, block idx.Block, position uint32) []ActionTrace {
+	traces := t.traces[txHash]
+	for i := range traces {
+		traces[i].BlockHash = blockHash
+		traces[i].BlockNumber = uint64(block)
+		traces[i].TransactionHash = txHash
+		traces[i].TransactionPosition = uint64(position)
+	}
+	return traces
+}
+
+func (t *CallTracer) enter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	trace := ActionTrace{
+		TraceAddress: []uint32{},
+	}
+	if len(t.stack) != 0 {
+		parent := &t.current[t.stack[len(t.stack)-1]]
+		trace.TraceAddress = append(append(make([]uint32, 0, len(parent.TraceAddress)+1), parent.TraceAddress...), uint32(parent.Subtraces))
+		parent.Subtraces++
+	}
+	var val *hexutil.Big
+	if value != nil {
+		val = (*hexutil.Big)(new(big.Int).Set(value))
+	} else {
+		val = (*hexutil.Big)(new(big.Int))
+	}
+	g := hexutil.Uint64(gas)
+	in := hexutil.Bytes(common.CopyBytes(input))
+	switch typ {
+	case vm.CREATE, vm.CREATE2:
+		trace.Type = CreateType
+		trace.Action = TraceAction{
+			From:  &from,
+			Value: val,
+			Gas:   &g,
+			Init:  &in,
+		}
+		// remember the address of the created contract, the result is set after exit
+		trace.Result = &TraceResult{Address: &to}
+	case vm.SELFDESTRUCT:
+		trace.Type = SuicideType
+		trace.Action = TraceAction{
+			Address:       &from,
+			RefundAddress: &to,
+			Balance:       val,
+		}
+	default:
+		trace.Type = CallType
+		trace.Action = TraceAction{
+			CallType: callType(typ),
+			From:     &from,
+			To:       &to,
+			Value:    val,
+			Gas:      &g,
+			Input:    &in,
+		}
+	}
+	t.current = append(t.current, trace)
+	t.stack = append(t.stack, len(t.current)-1)
+}
+
+func (t *CallTracer) exit(output []byte, gasUsed uint64, err error) {
+	if len(t.stack) == 0 {
+		return
+	}
+	trace := &t.current[t.stack[len(t.stack)-1]]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	if trace.Type == SuicideType {
+		trace.Result = nil
+		return
+	}
+	if err != nil {
+		trace.Error = err.Error()
+		trace.Result = nil
+		return
+	}
+	g := hexutil.Uint64(gasUsed)
+	out := hexutil.Bytes(common.CopyBytes(output))
+	if trace.Type == CreateType {
+		trace.Result.GasUsed = &g
+		trace.Result.Code = &out
+	} else {
+		trace.Result = &TraceResult{
+			GasUsed: &g,
+			Output:  &out,
+		}
+	}
+}
+
+func callType(typ vm.OpCode) string {
+	switch typ {
+	case vm.CALLCODE:
+		return "callcode"
+	case vm.DELEGATECALL:
+		return "delegatecall"
+	case vm.STATICCALL:
+		return "staticcall"
+	default:
+		return "call"
+	}
+}
+
+// CaptureStart implements vm.Tracer.
+func (t *CallTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	typ := vm.CALL
+	if create {
+		typ = vm.CREATE
+	}
+	t.enter(typ, from, to, input, gas, value)
+}
+
+// CaptureState implements vm.Tracer.
+func (t *CallTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+// CaptureEnter implements vm.Tracer.
+func (t *CallTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.enter(typ, from, to, input, gas, value)
+}
+
+// CaptureExit implements vm.Tracer.
+func (t *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	t.exit(output, gasUsed, err)
+}
+
+// CaptureFault implements vm.Tracer.
+func (t *CallTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// CaptureEnd implements vm.Tracer.
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) {
+	t.exit(output, gasUsed, err)
+}
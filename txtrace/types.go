@@ -0,0 +1,81 @@
+package txtrace
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Types of the traced actions
+const (
+	CallType    = "call"
+	CreateType  = "create"
+	SuicideType = "suicide"
+)
+
+// ActionTrace is a flat Parity-style trace of a single call, contract creation
+// or self-destruction, made during a transaction execution.
+type ActionTrace struct {
+	Action              TraceAction  `json:"action"`
+	BlockHash           common.Hash  `json:"blockHash"`
+	BlockNumber         uint64       `json:"blockNumber"`
+	Result              *TraceResult `json:"result"`
+	Error               string       `json:"error,omitempty"`
+	Subtraces           uint64       `json:"subtraces"`
+	TraceAddress        []uint32     `json:"traceAddress"`
+	TransactionHash     common.Hash  `json:"transactionHash"`
+	TransactionPosition uint64       `json:"transactionPosition"`
+	Type                string       `json:"type"`
+}
+
+// TraceAction is the input of a traced action.
+type TraceAction struct {
+	CallType      string          `json:"callType,omitempty"`
+	From          *common.Address `json:"from,omitempty"`
+	To            *common.Address `json:"to,omitempty"`
+	Value         *hexutil.Big    `json:"value,omitempty"`
+	Gas           *hexutil.Uint64 `json:"gas,omitempty"`
+	Input         *hexutil.Bytes  `json:"input,omitempty"`
+	Init          *hexutil.Bytes  `json:"init,omitempty"`
+	Address       *common.Address `json:"address,omitempty"`
+	Balance       *hexutil.Big    `json:"balance,omitempty"`
+	RefundAddress *common.Address `json:"refundAddress,omitempty"`
+}
+
+// TraceResult is the output of a successfully traced action.
+type TraceResult struct {
+	GasUsed *hexutil.Uint64 `json:"gasUsed,omitempty"`
+	Output  *hexutil.Bytes  `json:"output,omitempty"`
+	Code    *hexutil.Bytes  `json:"code,omitempty"`
+	Address *common.Address `json:"address,omitempty"`
+}
+
+// Sender returns the address which initiated the action.
+func (t *ActionTrace) Sender() common.Address {
+	if t.Type == SuicideType && t.Action.Address != nil {
+		return *t.Action.Address
+	}
+	if t.Action.From != nil {
+		return *t.Action.From
+	}
+	return common.Address{}
+}
+
+// Recipient returns the address which received the action, i.e. the callee,
+// the created contract or the refund address.
+func (t *ActionTrace) Recipient() common.Address {
+	switch t.Type {
+	case SuicideType:
+		if t.Action.RefundAddress != nil {
+			return *t.Action.RefundAddress
+		}
+	case CreateType:
+		if t.Result != nil && t.Result.Address != nil {
+			return *t.Result.Address
+		}
+	default:
+		if t.Action.To != nil {
+			return *t.Action.To
+		}
+	}
+	return common.Address{}
+}
@@ -0,0 +1,78 @@
+// Package freezer implements an append-only store for the finalized history,
+// which may be placed on a cheaper storage than the key-value databases.
+package freezer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// DefaultMaxFileSize is the default size limit of a single data file
+const DefaultMaxFileSize = 2 * 1024 * 1024 * 1024
+
+// Freezer is a set of append-only tables, stored in a directory.
+type Freezer struct {
+	dir    string
+	tables map[string]*Table
+}
+
+// Open opens or creates the freezer tables in the directory.
+func Open(dir string, tables []string, maxFileSize uint64) (*Freezer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	f := &Freezer{
+		dir:    dir,
+		tables: make(map[string]*Table, len(tables)),
+	}
+	for _, name := range tables {
+		t, err := openTable(dir, name, maxFileSize)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to open freezer table %s: %v", name, err)
+		}
+		f.tables[name] = t
+	}
+	return f, nil
+}
+
+// Dir returns the directory of the freezer.
+func (f *Freezer) Dir() string {
+	return f.dir
+}
+
+// Table returns the table by name, or nil if it isn't opened.
+func (f *Freezer) Table(name string) *Table {
+	return f.tables[name]
+}
+
+// Sync flushes all the tables to the disk.
+func (f *Freezer) Sync() error {
+	for _, name := range f.names() {
+		if err := f.tables[name].Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all the tables.
+func (f *Freezer) Close() error {
+	var firstErr error
+	for _, name := range f.names() {
+		if err := f.tables[name].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f *Freezer) names() []string {
+	names := make([]string, 0, len(f.tables))
+	for name := range f.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,108 @@
+package freezer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezer(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "freezer")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	item := func(i uint64) []byte {
+		return []byte(fmt.Sprintf("item-%d", i))
+	}
+	open := func() *Table {
+		f, err := Open(dir, []string{"blocks"}, 32)
+		require.NoError(err)
+		return f.Table("blocks")
+	}
+
+	table := open()
+	// the first item of an empty table may have any number
+	for i := uint64(100); i < 120; i++ {
+		require.NoError(table.Append(i, item(i)))
+	}
+	require.NoError(table.Append(120, nil))
+	require.Equal(ErrNotSequential, table.Append(122, item(122)))
+	check := func(tail, head uint64) {
+		gotTail, gotHead := table.Items()
+		require.Equal(tail, gotTail)
+		require.Equal(head, gotHead)
+		for i := tail; i < head-1; i++ {
+			blob, err := table.Get(i)
+			require.NoError(err)
+			require.Equal(item(i), blob)
+		}
+		blob, err := table.Get(120)
+		require.NoError(err)
+		require.Empty(blob)
+		_, err = table.Get(tail - 1)
+		require.Equal(ErrOutOfBounds, err)
+		_, err = table.Get(head)
+		require.Equal(ErrOutOfBounds, err)
+	}
+	check(100, 121)
+
+	// items survive reopening
+	require.NoError(table.Sync())
+	require.NoError(table.Close())
+	table = open()
+	check(100, 121)
+
+	// the data files fully below the tail are deleted
+	files, err := filepath.Glob(filepath.Join(dir, "blocks.*.dat"))
+	require.NoError(err)
+	require.NoError(table.TruncateTail(110))
+	check(110, 121)
+	filesAfter, err := filepath.Glob(filepath.Join(dir, "blocks.*.dat"))
+	require.NoError(err)
+	require.Less(len(filesAfter), len(files))
+	require.NoError(table.Close())
+	table = open()
+	check(110, 121)
+
+	// the index entries which point beyond the written data are dropped after a crash
+	require.NoError(table.Close())
+	idx, err := os.OpenFile(filepath.Join(dir, "blocks.idx"), os.O_RDWR, 0600)
+	require.NoError(err)
+	stat, err := idx.Stat()
+	require.NoError(err)
+	_, err = idx.WriteAt(indexEntry{file: 1000, offset: 1}.marshal(), stat.Size())
+	require.NoError(err)
+	_, err = idx.WriteAt([]byte{1, 2, 3}, stat.Size()+indexEntrySize)
+	require.NoError(err)
+	require.NoError(idx.Close())
+	table = open()
+	check(110, 121)
+
+	// the items after the head are deleted
+	require.NoError(table.TruncateHead(115))
+	check2 := func() {
+		tail, head := table.Items()
+		require.Equal(uint64(110), tail)
+		require.Equal(uint64(115), head)
+		blob, err := table.Get(114)
+		require.NoError(err)
+		require.Equal(item(114), blob)
+		_, err = table.Get(115)
+		require.Equal(ErrOutOfBounds, err)
+	}
+	check2()
+	require.NoError(table.Close())
+	table = open()
+	check2()
+	require.NoError(table.Append(115, item(115)))
+	blob, err := table.Get(115)
+	require.NoError(err)
+	require.Equal(item(115), blob)
+	require.NoError(table.Close())
+}
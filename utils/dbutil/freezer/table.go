@@ -0,0 +1,415 @@
+package freezer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	// ErrOutOfBounds is returned for the items which aren't appended yet or are truncated
+	ErrOutOfBounds = errors.New("freezer item is out of bounds")
+	// ErrNotSequential is returned if an appended item doesn't follow the last item
+	ErrNotSequential = errors.New("freezer items have to be appended sequentially")
+)
+
+const indexEntrySize = 12
+
+// indexEntry points to the end of an item in the data files.
+// The first entry of the index points to the start of the first item.
+type indexEntry struct {
+	file   uint32
+	offset uint64
+}
+
+func (e indexEntry) marshal() []byte {
+	b := make([]byte, indexEntrySize)
+	binary.BigEndian.PutUint32(b[:4], e.file)
+	binary.BigEndian.PutUint64(b[4:], e.offset)
+	return b
+}
+
+func (e *indexEntry) unmarshal(b []byte) {
+	e.file = binary.BigEndian.Uint32(b[:4])
+	e.offset = binary.BigEndian.Uint64(b[4:])
+}
+
+// Table is an append-only sequence of blobs, stored in flat data files with an offsets index.
+// Items are numbered sequentially starting from the first appended item.
+// Truncation of the tail deletes only the data files which are fully below the tail, the index isn't shrunk.
+type Table struct {
+	name        string
+	dir         string
+	maxFileSize uint64
+
+	mu       sync.RWMutex
+	index    *os.File
+	first    uint64 // number of the item which is pointed by the second index entry
+	tail     uint64 // items below the tail are deleted
+	items    uint64 // number of items in the index
+	headNum  uint32
+	headSize uint64
+
+	filesMu sync.Mutex
+	files   map[uint32]*os.File
+}
+
+func openTable(dir, name string, maxFileSize uint64) (*Table, error) {
+	t := &Table{
+		name:        name,
+		dir:         dir,
+		maxFileSize: maxFileSize,
+		files:       make(map[uint32]*os.File),
+	}
+	if err := t.readMeta(); err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(filepath.Join(dir, name+".idx"), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	t.index = index
+	if err := t.repair(); err != nil {
+		_ = t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// repair restores consistency of the index and the data files after an unclean shutdown
+func (t *Table) repair() error {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	size := stat.Size() / indexEntrySize * indexEntrySize
+	if size == 0 {
+		if _, err := t.index.WriteAt(indexEntry{}.marshal(), 0); err != nil {
+			return err
+		}
+		size = indexEntrySize
+	}
+	if err := t.index.Truncate(size); err != nil {
+		return err
+	}
+	t.items = uint64(size/indexEntrySize) - 1
+
+	// drop the index entries which point beyond the written data
+	var last indexEntry
+	for {
+		last, err = t.readEntry(t.items)
+		if err != nil {
+			return err
+		}
+		var size int64
+		if stat, err := os.Stat(t.dataPath(last.file)); err == nil {
+			size = stat.Size()
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		if uint64(size) >= last.offset || t.items == 0 {
+			break
+		}
+		t.items--
+	}
+	if err := t.index.Truncate(int64(t.items+1) * indexEntrySize); err != nil {
+		return err
+	}
+	head, err := t.openFile(last.file)
+	if err != nil {
+		return err
+	}
+	if err := head.Truncate(int64(last.offset)); err != nil {
+		return err
+	}
+	t.headNum, t.headSize = last.file, last.offset
+	// remove the data files which are written after the head
+	files, err := filepath.Glob(filepath.Join(t.dir, t.name+".*.dat"))
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		var num uint32
+		if _, err := fmt.Sscanf(filepath.Base(path), t.name+".%d.dat", &num); err == nil && num > t.headNum {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if t.tail < t.first {
+		t.tail = t.first
+	}
+	if t.tail > t.first+t.items {
+		t.tail = t.first + t.items
+	}
+	return nil
+}
+
+func (t *Table) metaPath() string {
+	return filepath.Join(t.dir, t.name+".meta")
+}
+
+func (t *Table) dataPath(num uint32) string {
+	return filepath.Join(t.dir, fmt.Sprintf("%s.%04d.dat", t.name, num))
+}
+
+func (t *Table) readMeta() error {
+	b, err := ioutil.ReadFile(t.metaPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(b) != 16 {
+		return fmt.Errorf("malformed freezer table meta %s", t.metaPath())
+	}
+	t.first = binary.BigEndian.Uint64(b[:8])
+	t.tail = binary.BigEndian.Uint64(b[8:])
+	return nil
+}
+
+func (t *Table) writeMeta() error {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[:8], t.first)
+	binary.BigEndian.PutUint64(b[8:], t.tail)
+	tmp := t.metaPath() + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, t.metaPath())
+}
+
+func (t *Table) readEntry(i uint64) (indexEntry, error) {
+	b := make([]byte, indexEntrySize)
+	if _, err := t.index.ReadAt(b, int64(i*indexEntrySize)); err != nil {
+		return indexEntry{}, err
+	}
+	var e indexEntry
+	e.unmarshal(b)
+	return e, nil
+}
+
+func (t *Table) openFile(num uint32) (*os.File, error) {
+	t.filesMu.Lock()
+	defer t.filesMu.Unlock()
+	if f, ok := t.files[num]; ok {
+		return f, nil
+	}
+	f, err := os.OpenFile(t.dataPath(num), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	t.files[num] = f
+	return f, nil
+}
+
+func (t *Table) closeFile(num uint32) error {
+	t.filesMu.Lock()
+	defer t.filesMu.Unlock()
+	f, ok := t.files[num]
+	if !ok {
+		return nil
+	}
+	delete(t.files, num)
+	return f.Close()
+}
+
+// Items returns the range of the stored items, [tail, head).
+func (t *Table) Items() (tail, head uint64) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tail, t.first + t.items
+}
+
+// Append adds the item after the last one. The first item of an empty table may have any number.
+func (t *Table) Append(item uint64, blob []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.items == 0 && item != t.first {
+		t.first, t.tail = item, item
+		if err := t.writeMeta(); err != nil {
+			return err
+		}
+	}
+	if item != t.first+t.items {
+		return ErrNotSequential
+	}
+
+	if t.headSize > 0 && t.headSize+uint64(len(blob)) > t.maxFileSize {
+		// the previous data file is never written again
+		prev, err := t.openFile(t.headNum)
+		if err != nil {
+			return err
+		}
+		if err := prev.Sync(); err != nil {
+			return err
+		}
+		t.headNum++
+		t.headSize = 0
+	}
+	head, err := t.openFile(t.headNum)
+	if err != nil {
+		return err
+	}
+	if _, err := head.WriteAt(blob, int64(t.headSize)); err != nil {
+		return err
+	}
+	entry := indexEntry{file: t.headNum, offset: t.headSize + uint64(len(blob))}
+	if _, err := t.index.WriteAt(entry.marshal(), int64(t.items+1)*indexEntrySize); err != nil {
+		return err
+	}
+	t.headSize = entry.offset
+	t.items++
+	return nil
+}
+
+// Get returns the item, or ErrOutOfBounds if it isn't stored.
+func (t *Table) Get(item uint64) ([]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if item < t.tail || item >= t.first+t.items {
+		return nil, ErrOutOfBounds
+	}
+	start, err := t.readEntry(item - t.first)
+	if err != nil {
+		return nil, err
+	}
+	end, err := t.readEntry(item - t.first + 1)
+	if err != nil {
+		return nil, err
+	}
+	if start.file != end.file {
+		// the item is the first one in the data file
+		start.offset = 0
+	}
+	f, err := t.openFile(end.file)
+	if err != nil {
+		return nil, err
+	}
+	blob := make([]byte, end.offset-start.offset)
+	if _, err := f.ReadAt(blob, int64(start.offset)); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// TruncateHead deletes the items starting from the head.
+func (t *Table) TruncateHead(head uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if head < t.first {
+		head = t.first
+	}
+	if head >= t.first+t.items {
+		return nil
+	}
+	t.items = head - t.first
+	last, err := t.readEntry(t.items)
+	if err != nil {
+		return err
+	}
+	if err := t.index.Truncate(int64(t.items+1) * indexEntrySize); err != nil {
+		return err
+	}
+	for num := last.file + 1; num <= t.headNum; num++ {
+		if err := t.closeFile(num); err != nil {
+			return err
+		}
+		if err := os.Remove(t.dataPath(num)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	f, err := t.openFile(last.file)
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(int64(last.offset)); err != nil {
+		return err
+	}
+	t.headNum, t.headSize = last.file, last.offset
+	if t.tail > head {
+		t.tail = head
+		return t.writeMeta()
+	}
+	return nil
+}
+
+// TruncateTail deletes the items below the tail, and the data files which contain only the deleted items.
+func (t *Table) TruncateTail(tail uint64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if head := t.first + t.items; tail > head {
+		tail = head
+	}
+	if tail <= t.tail {
+		return nil
+	}
+	t.tail = tail
+	if err := t.writeMeta(); err != nil {
+		return err
+	}
+
+	// the data file which contains the tail item
+	tailNum := t.headNum
+	if tail < t.first+t.items {
+		end, err := t.readEntry(tail - t.first + 1)
+		if err != nil {
+			return err
+		}
+		tailNum = end.file
+	}
+	for num := tailNum; num > 0; {
+		num--
+		if err := t.closeFile(num); err != nil {
+			return err
+		}
+		err := os.Remove(t.dataPath(num))
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync flushes the appended items to the disk.
+func (t *Table) Sync() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	head, err := t.openFile(t.headNum)
+	if err != nil {
+		return err
+	}
+	if err := head.Sync(); err != nil {
+		return err
+	}
+	return t.index.Sync()
+}
+
+// Close closes the files of the table.
+func (t *Table) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var errs []error
+	t.filesMu.Lock()
+	for num, f := range t.files {
+		errs = append(errs, f.Close())
+		delete(t.files, num)
+	}
+	t.filesMu.Unlock()
+	errs = append(errs, t.index.Close())
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
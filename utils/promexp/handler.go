@@ -0,0 +1,69 @@
+package promexp
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/metrics/prometheus"
+)
+
+// bufferedResponse captures the output of a wrapped handler
+type bufferedResponse struct {
+	header http.Header
+	buf    bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(int) {}
+
+// Handler returns an HTTP handler which dumps the labeled metrics of reg,
+// followed by the flat metrics of flat (if not nil), in the Prometheus text format.
+func Handler(reg *Registry, flat metrics.Registry) http.Handler {
+	var flatHandler http.Handler
+	if flat != nil {
+		flatHandler = prometheus.Handler(flat)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out := &bufferedResponse{header: make(http.Header)}
+		err := reg.Write(&out.buf)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if flatHandler != nil {
+			flatHandler.ServeHTTP(out, r)
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Header().Set("Content-Length", fmt.Sprint(out.buf.Len()))
+		_, _ = w.Write(out.buf.Bytes())
+	})
+}
+
+// Setup starts a dedicated HTTP server which serves the metrics of DefaultRegistry and
+// metrics.DefaultRegistry on the /metrics path.
+func Setup(address string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler(DefaultRegistry, metrics.DefaultRegistry))
+	srv := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Info("Starting Prometheus metrics server", "addr", fmt.Sprintf("http://%s/metrics", address))
+	go func() {
+		if err := srv.ListenAndServe(); err != nil {
+			log.Error("Failure in running Prometheus metrics server", "err", err)
+		}
+	}()
+}
@@ -0,0 +1,290 @@
+// Package promexp implements labeled metrics which are exposed in the Prometheus text format.
+package promexp
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	counterType   = "counter"
+	gaugeType     = "gauge"
+	histogramType = "histogram"
+)
+
+// DefaultBuckets are the default histogram buckets, in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type series struct {
+	values []string
+	value  float64
+	// histogram only
+	counts []uint64
+	count  uint64
+}
+
+type family struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+
+	series map[string]*series
+	mu     sync.Mutex
+}
+
+func (f *family) get(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s: expected %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	s := f.series[key]
+	if s == nil {
+		s = &series{
+			values: append([]string{}, values...),
+		}
+		if f.typ == histogramType {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) deleteIf(cond func(values []string) bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key, s := range f.series {
+		if cond(s.values) {
+			delete(f.series, key)
+		}
+	}
+}
+
+// Registry is a set of metric families
+type Registry struct {
+	families map[string]*family
+	mu       sync.Mutex
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// DefaultRegistry is the registry of the package-level constructors
+var DefaultRegistry = NewRegistry()
+
+func (r *Registry) getOrRegister(name, help, typ string, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.typ != typ || len(f.labels) != len(labels) {
+			panic(fmt.Sprintf("metric %s is already registered with a different type or labels", name))
+		}
+		return f
+	}
+	f := &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.families[name] = f
+	return f
+}
+
+// CounterVec is a set of counters partitioned by label values
+type CounterVec struct {
+	f *family
+}
+
+// GaugeVec is a set of gauges partitioned by label values
+type GaugeVec struct {
+	f *family
+}
+
+// HistogramVec is a set of histograms partitioned by label values
+type HistogramVec struct {
+	f *family
+}
+
+// NewCounterVec registers a counter in the registry
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{r.getOrRegister(name, help, counterType, labels, nil)}
+}
+
+// NewGaugeVec registers a gauge in the registry
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{r.getOrRegister(name, help, gaugeType, labels, nil)}
+}
+
+// NewHistogramVec registers a histogram in the registry. Buckets are upper bounds in increasing order
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{r.getOrRegister(name, help, histogramType, labels, buckets)}
+}
+
+// NewCounterVec registers a counter in the default registry
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return DefaultRegistry.NewCounterVec(name, help, labels...)
+}
+
+// NewGaugeVec registers a gauge in the default registry
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return DefaultRegistry.NewGaugeVec(name, help, labels...)
+}
+
+// NewHistogramVec registers a histogram in the default registry
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return DefaultRegistry.NewHistogramVec(name, help, buckets, labels...)
+}
+
+// Add increases the counter by delta, which must be non-negative
+func (v *CounterVec) Add(delta float64, values ...string) {
+	v.f.mu.Lock()
+	defer v.f.mu.Unlock()
+	v.f.get(values).value += delta
+}
+
+// Inc increases the counter by 1
+func (v *CounterVec) Inc(values ...string) {
+	v.Add(1, values...)
+}
+
+// Set sets the gauge value
+func (v *GaugeVec) Set(value float64, values ...string) {
+	v.f.mu.Lock()
+	defer v.f.mu.Unlock()
+	v.f.get(values).value = value
+}
+
+// SetMax sets the gauge value if it's greater than the current one
+func (v *GaugeVec) SetMax(value float64, values ...string) {
+	v.f.mu.Lock()
+	defer v.f.mu.Unlock()
+	s := v.f.get(values)
+	if value > s.value {
+		s.value = value
+	}
+}
+
+// DeleteIf deletes the gauges which label values satisfy the condition
+func (v *GaugeVec) DeleteIf(cond func(values []string) bool) {
+	v.f.deleteIf(cond)
+}
+
+// Observe adds a value to the histogram
+func (v *HistogramVec) Observe(value float64, values ...string) {
+	v.f.mu.Lock()
+	defer v.f.mu.Unlock()
+	s := v.f.get(values)
+	for i, bound := range v.f.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.value += value
+}
+
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	pairs := make([]string, 0, len(names)+1)
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabel(values[i])))
+	}
+	if len(extraName) != 0 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, extraValue))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (f *family) write(w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.series) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		s := f.series[key]
+		if f.typ != histogramType {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, formatLabels(f.labels, s.values, "", ""), formatFloat(s.value)); err != nil {
+				return err
+			}
+			continue
+		}
+		for i, bound := range f.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, formatLabels(f.labels, s.values, "le", formatFloat(bound)), s.counts[i]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, formatLabels(f.labels, s.values, "le", "+Inf"), s.count); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n", f.name, formatLabels(f.labels, s.values, "", ""), formatFloat(s.value)); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_count%s %d\n", f.name, formatLabels(f.labels, s.values, "", ""), s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write writes all the metrics in the Prometheus text format
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	families := r.families
+	r.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.mu.Lock()
+		f := families[name]
+		r.mu.Unlock()
+		if err := f.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package promexp
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	require := require.New(t)
+
+	reg := NewRegistry()
+	events := reg.NewCounterVec("opera_events_total", "Events.", "validator")
+	frame := reg.NewGaugeVec("opera_frame", "Frame.", "epoch")
+	latency := reg.NewHistogramVec("opera_latency_seconds", "Latency.", []float64{1, 5})
+	reg.NewGaugeVec("opera_empty", "Empty.")
+
+	events.Inc("2")
+	events.Add(2, "1")
+	events.Inc("2")
+	frame.Set(5, "10")
+	frame.SetMax(3, "10")
+	frame.Set(1, "11")
+	frame.DeleteIf(func(values []string) bool {
+		return values[0] == "11"
+	})
+	latency.Observe(0.5)
+	latency.Observe(3)
+	latency.Observe(10)
+
+	buf := &bytes.Buffer{}
+	require.NoError(reg.Write(buf))
+	require.Equal(`# HELP opera_events_total Events.
+# TYPE opera_events_total counter
+opera_events_total{validator="1"} 2
+opera_events_total{validator="2"} 2
+# HELP opera_frame Frame.
+# TYPE opera_frame gauge
+opera_frame{epoch="10"} 5
+# HELP opera_latency_seconds Latency.
+# TYPE opera_latency_seconds histogram
+opera_latency_seconds_bucket{le="1"} 1
+opera_latency_seconds_bucket{le="5"} 2
+opera_latency_seconds_bucket{le="+Inf"} 3
+opera_latency_seconds_sum 13.5
+opera_latency_seconds_count 3
+`, buf.String())
+
+	// same metric is returned on re-registration
+	reg.NewCounterVec("opera_events_total", "Events.", "validator").Inc("1")
+	require.Panics(func() {
+		reg.NewGaugeVec("opera_events_total", "Events.", "validator")
+	})
+	require.Panics(func() {
+		events.Inc()
+	})
+}
+
+func TestHandler(t *testing.T) {
+	require := require.New(t)
+
+	reg := NewRegistry()
+	reg.NewGaugeVec("opera_label", "Label.", "name").Set(1, "a\"b")
+	flat := metrics.NewRegistry()
+	metrics.NewRegisteredGauge("flat/gauge", flat)
+
+	rec := httptest.NewRecorder()
+	Handler(reg, flat).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(err)
+	require.Contains(string(body), `opera_label{name="a\"b"} 1`)
+	require.Contains(string(body), "# TYPE flat_gauge gauge")
+}
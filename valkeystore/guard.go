@@ -0,0 +1,119 @@
+package valkeystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Fantom-foundation/lachesis-base/hash"
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+)
+
+var ErrDoubleSign = errors.New("refusing to sign an event which may be a double-sign")
+
+// SignedEvent is the last event signed by a key
+type SignedEvent struct {
+	Epoch idx.Epoch `json:"epoch"`
+	Seq   idx.Event `json:"seq"`
+	Hash  hash.Hash `json:"hash"`
+}
+
+// SignGuard protects the keys from signing two different events with the same epoch and seq.
+// The last signed event of every key is persisted before the signature is returned,
+// so the protection survives restarts. It's safe for concurrent use.
+type SignGuard struct {
+	path string
+	last map[string]SignedEvent
+	mu   sync.Mutex
+}
+
+// NewSignGuard loads the last signed events from the file, the file is created if it doesn't exist.
+func NewSignGuard(path string) (*SignGuard, error) {
+	g := &SignGuard{
+		path: path,
+		last: make(map[string]SignedEvent),
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return g, g.write()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &g.last); err != nil {
+		return nil, fmt.Errorf("failed to parse sign guard file %s: %v", path, err)
+	}
+	return g, nil
+}
+
+// Last returns the last signed event of the key
+func (g *SignGuard) Last(pubkey validatorpk.PubKey) (SignedEvent, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	last, ok := g.last[pubkey.String()]
+	return last, ok
+}
+
+// Sign calls sign if the event is above the last signed event of the key, or if it's the same event.
+// The event is persisted as the last signed one before sign is called.
+func (g *SignGuard) Sign(pubkey validatorpk.PubKey, e inter.EventLocator, sign func(digest []byte) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	signed := SignedEvent{
+		Epoch: e.Epoch,
+		Seq:   e.Seq,
+		Hash:  e.HashToSign(),
+	}
+	key := pubkey.String()
+	if last, ok := g.last[key]; !ok || last != signed {
+		if ok && (signed.Epoch < last.Epoch || signed.Epoch == last.Epoch && signed.Seq <= last.Seq) {
+			return nil, ErrDoubleSign
+		}
+		g.last[key] = signed
+		if err := g.write(); err != nil {
+			if ok {
+				g.last[key] = last
+			} else {
+				delete(g.last, key)
+			}
+			return nil, err
+		}
+	}
+	return sign(signed.Hash.Bytes())
+}
+
+// write persists the last signed events atomically
+func (g *SignGuard) write() error {
+	data, err := json.Marshal(g.last)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(g.path), 0700); err != nil {
+		return err
+	}
+	tmp := g.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, g.path)
+}
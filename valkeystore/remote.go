@@ -0,0 +1,175 @@
+package valkeystore
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+	"github.com/Fantom-foundation/go-opera/inter/validatorpk"
+	"github.com/Fantom-foundation/go-opera/valkeystore/encryption"
+)
+
+// RemoteSignerTimeout is the maximum duration of a remote signer call.
+const RemoteSignerTimeout = 5 * time.Second
+
+var (
+	ErrInvalidSignature = errors.New("remote signer returned invalid signature")
+	ErrOnlyEvents       = errors.New("remote signer signs only events")
+)
+
+// EventSignerI signs events by their locators, so the signer is able to guard against double-signing.
+type EventSignerI interface {
+	SignEvent(pubkey validatorpk.PubKey, e inter.EventLocator) ([]byte, error)
+}
+
+// RemoteSignerConfig is the connection config of the external signer.
+type RemoteSignerConfig struct {
+	// AuthToken is sent as the bearer token of HTTP requests
+	AuthToken string
+	// TLS is used for HTTPS connections, the system config is used if nil
+	TLS *tls.Config
+}
+
+// RemoteSigner signs events by an external signer, which holds the validator keys.
+// The signer is connected over IPC or HTTPS and serves the RemoteSignerAPI methods
+// in the "validator" namespace, so the private keys never reach the node.
+type RemoteSigner struct {
+	client *rpc.Client
+}
+
+// NewRemoteSigner connects to the external signer by the IPC path or HTTP URL.
+func NewRemoteSigner(endpoint string, cfg RemoteSignerConfig) (*RemoteSigner, error) {
+	var client *rpc.Client
+	var err error
+	if strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://") {
+		client, err = rpc.DialHTTPWithClient(endpoint, &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: cfg.TLS,
+			},
+			Timeout: RemoteSignerTimeout,
+		})
+		if err == nil && cfg.AuthToken != "" {
+			client.SetHeader("Authorization", "Bearer "+cfg.AuthToken)
+		}
+	} else {
+		client, err = rpc.DialIPC(context.Background(), endpoint)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewRemoteSignerWithClient(client), nil
+}
+
+// NewRemoteSignerWithClient creates a signer that uses the given RPC client.
+func NewRemoteSignerWithClient(client *rpc.Client) *RemoteSigner {
+	return &RemoteSigner{
+		client: client,
+	}
+}
+
+// Has checks whether the external signer is able to sign with the key.
+func (s *RemoteSigner) Has(pubkey validatorpk.PubKey) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), RemoteSignerTimeout)
+	defer cancel()
+
+	var has bool
+	err := s.client.CallContext(ctx, &has, "validator_hasKey", pubkey.String())
+	return has, err
+}
+
+// Sign always fails, because the external signer doesn't sign arbitrary digests.
+func (s *RemoteSigner) Sign(pubkey validatorpk.PubKey, digest []byte) ([]byte, error) {
+	return nil, ErrOnlyEvents
+}
+
+// SignEvent signs the event by the external signer.
+// The signature is verified against the pubkey before it's returned.
+func (s *RemoteSigner) SignEvent(pubkey validatorpk.PubKey, e inter.EventLocator) ([]byte, error) {
+	if pubkey.Type != validatorpk.Types.Secp256k1 {
+		return nil, encryption.ErrNotSupportedType
+	}
+	locator, err := rlp.EncodeToBytes(&e)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), RemoteSignerTimeout)
+	defer cancel()
+
+	var sig hexutil.Bytes
+	err = s.client.CallContext(ctx, &sig, "validator_signEvent", pubkey.String(), hexutil.Bytes(locator))
+	if err != nil {
+		return nil, err
+	}
+	// accept both RSV and RS signatures
+	if len(sig) == crypto.SignatureLength {
+		sig = sig[:64]
+	}
+	if len(sig) != 64 || !crypto.VerifySignature(pubkey.Raw, e.HashToSign().Bytes(), sig) {
+		return nil, ErrInvalidSignature
+	}
+	return sig, nil
+}
+
+// Close closes the connection to the external signer.
+func (s *RemoteSigner) Close() {
+	s.client.Close()
+}
+
+// RemoteSignerAPI is the external signer side of the RemoteSigner protocol.
+// It signs events by the unlocked keys of the keystore, the guard refuses to sign double-signs.
+type RemoteSignerAPI struct {
+	keystore KeystoreI
+	signer   SignerI
+	guard    *SignGuard
+}
+
+// NewRemoteSignerAPI creates the API to be registered in the "validator" namespace.
+func NewRemoteSignerAPI(keystore KeystoreI, guard *SignGuard) *RemoteSignerAPI {
+	return &RemoteSignerAPI{
+		keystore: keystore,
+		signer:   NewSigner(keystore),
+		guard:    guard,
+	}
+}
+
+// HasKey returns true if the key is unlocked for signing.
+func (api *RemoteSignerAPI) HasKey(pubkey validatorpk.PubKey) bool {
+	return api.keystore.Unlocked(pubkey)
+}
+
+// SignEvent signs the event by the key, the event locator is RLP-encoded.
+// The signature is returned in [R || S] format.
+func (api *RemoteSignerAPI) SignEvent(pubkey validatorpk.PubKey, locator hexutil.Bytes) (hexutil.Bytes, error) {
+	var e inter.EventLocator
+	if err := rlp.DecodeBytes(locator, &e); err != nil {
+		return nil, err
+	}
+	if !api.keystore.Unlocked(pubkey) {
+		return nil, ErrLocked
+	}
+	return api.guard.Sign(pubkey, e, func(digest []byte) ([]byte, error) {
+		return api.signer.Sign(pubkey, digest)
+	})
+}
+
+// NewRemoteSignerHandler serves the signer RPC over HTTP to the requests with the bearer token only.
+func NewRemoteSignerHandler(server *rpc.Server, authToken string) http.Handler {
+	expected := []byte("Bearer " + authToken)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(authToken) == 0 || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		server.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,154 @@
+package valkeystore
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Fantom-foundation/lachesis-base/inter/idx"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Fantom-foundation/go-opera/inter"
+)
+
+func testLocator(epoch, seq uint32, payload byte) inter.EventLocator {
+	e := inter.EventLocator{
+		Epoch:   idx.Epoch(epoch),
+		Seq:     idx.Event(seq),
+		Lamport: idx.Lamport(seq),
+		Creator: 1,
+	}
+	e.PayloadHash[0] = payload
+	return e
+}
+
+func TestRemoteSigner(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "signer")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	keystore := NewDefaultMemKeystore()
+	require.NoError(keystore.Add(pubkey1, key1, "auth1"))
+	require.NoError(keystore.Add(pubkey2, key2, "auth2"))
+	require.NoError(keystore.Unlock(pubkey1, "auth1"))
+
+	guard, err := NewSignGuard(filepath.Join(dir, "guard.json"))
+	require.NoError(err)
+	server := rpc.NewServer()
+	defer server.Stop()
+	require.NoError(server.RegisterName("validator", NewRemoteSignerAPI(keystore, guard)))
+	signer := NewRemoteSignerWithClient(rpc.DialInProc(server))
+	defer signer.Close()
+
+	has, err := signer.Has(pubkey1)
+	require.NoError(err)
+	require.True(has)
+	has, err = signer.Has(pubkey2)
+	require.NoError(err)
+	require.False(has)
+
+	e := testLocator(2, 5, 1)
+	sig, err := signer.SignEvent(pubkey1, e)
+	require.NoError(err)
+	expSig, err := NewSigner(keystore).Sign(pubkey1, e.HashToSign().Bytes())
+	require.NoError(err)
+	require.Equal(expSig, sig)
+
+	// arbitrary digests aren't signed
+	_, err = signer.Sign(pubkey1, crypto.Keccak256([]byte("digest")))
+	require.Equal(ErrOnlyEvents, err)
+
+	_, err = signer.SignEvent(pubkey2, e)
+	require.EqualError(err, ErrLocked.Error())
+}
+
+func TestSignGuard(t *testing.T) {
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "guard")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "guard.json")
+
+	signed := 0
+	sign := func(digest []byte) ([]byte, error) {
+		signed++
+		return digest, nil
+	}
+	guard, err := NewSignGuard(path)
+	require.NoError(err)
+	_, ok := guard.Last(pubkey1)
+	require.False(ok)
+
+	e := testLocator(2, 5, 1)
+	digest, err := guard.Sign(pubkey1, e, sign)
+	require.NoError(err)
+	require.Equal(e.HashToSign().Bytes(), digest)
+	// the same event may be signed again
+	_, err = guard.Sign(pubkey1, e, sign)
+	require.NoError(err)
+	// a different event with the same seq is a double-sign
+	_, err = guard.Sign(pubkey1, testLocator(2, 5, 2), sign)
+	require.Equal(ErrDoubleSign, err)
+	_, err = guard.Sign(pubkey1, testLocator(2, 4, 2), sign)
+	require.Equal(ErrDoubleSign, err)
+	_, err = guard.Sign(pubkey1, testLocator(1, 10, 2), sign)
+	require.Equal(ErrDoubleSign, err)
+	// other keys are guarded separately
+	_, err = guard.Sign(pubkey2, testLocator(1, 1, 2), sign)
+	require.NoError(err)
+	require.Equal(3, signed)
+
+	// the protection survives restarts
+	guard, err = NewSignGuard(path)
+	require.NoError(err)
+	last, ok := guard.Last(pubkey1)
+	require.True(ok)
+	require.Equal(SignedEvent{Epoch: 2, Seq: 5, Hash: e.HashToSign()}, last)
+	_, err = guard.Sign(pubkey1, testLocator(2, 5, 2), sign)
+	require.Equal(ErrDoubleSign, err)
+	_, err = guard.Sign(pubkey1, testLocator(2, 6, 2), sign)
+	require.NoError(err)
+	_, err = guard.Sign(pubkey1, testLocator(3, 1, 2), sign)
+	require.NoError(err)
+	require.Equal(5, signed)
+
+	// corrupted file isn't ignored
+	require.NoError(ioutil.WriteFile(path, []byte("{"), 0600))
+	_, err = NewSignGuard(path)
+	require.Error(err)
+}
+
+func TestRemoteSignerHandlerAuth(t *testing.T) {
+	require := require.New(t)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	handler := NewRemoteSignerHandler(server, "token")
+
+	request := func(auth string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"rpc_modules"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	require.Equal(http.StatusUnauthorized, request(""))
+	require.Equal(http.StatusUnauthorized, request("Bearer wrong"))
+	require.Equal(http.StatusOK, request("Bearer token"))
+
+	// empty token doesn't authorize anyone
+	handler = NewRemoteSignerHandler(server, "")
+	require.Equal(http.StatusUnauthorized, request("Bearer "))
+}